@@ -0,0 +1,57 @@
+import React from 'react'
+
+import classNames from 'classnames'
+import RegexIcon from 'mdi-react/RegexIcon'
+
+import { pluralize } from '@sourcegraph/common'
+import { displayRepoName } from '@sourcegraph/shared/src/components/RepoLink'
+import { CaptureGroupMatch, getCaptureGroupMatchUrl } from '@sourcegraph/shared/src/search/stream'
+import { Code, Link } from '@sourcegraph/wildcard'
+
+import { ResultContainer } from './ResultContainer'
+
+import styles from './SearchResult.module.scss'
+
+export interface CaptureGroupSearchResultProps {
+    result: CaptureGroupMatch
+    onSelect: () => void
+    containerClassName?: string
+    as?: React.ElementType
+    index: number
+}
+
+export const CaptureGroupSearchResult: React.FunctionComponent<CaptureGroupSearchResultProps> = ({
+    result,
+    onSelect,
+    containerClassName,
+    as,
+    index,
+}) => {
+    const renderTitle = (): JSX.Element => (
+        <div className={styles.title}>
+            <span className={classNames('test-search-result-label', styles.titleInner)}>
+                <Code>{result.value}</Code>
+                <span className="text-muted">
+                    {' in '}
+                    <Link to={getCaptureGroupMatchUrl(result)}>{displayRepoName(result.repository)}</Link>
+                </span>
+            </span>
+        </div>
+    )
+
+    return (
+        <ResultContainer
+            index={index}
+            icon={RegexIcon}
+            collapsible={false}
+            defaultExpanded={false}
+            title={renderTitle()}
+            matchCountLabel={`${result.count} ${pluralize('match', result.count, 'matches')}`}
+            resultType={result.type}
+            onResultClicked={onSelect}
+            repoName={result.repository}
+            className={containerClassName}
+            as={as}
+        />
+    )
+}
@@ -0,0 +1,56 @@
+import React from 'react'
+
+import classNames from 'classnames'
+import AccountIcon from 'mdi-react/AccountIcon'
+
+import { displayRepoName } from '@sourcegraph/shared/src/components/RepoLink'
+import { getPersonMatchUrl, PersonMatch } from '@sourcegraph/shared/src/search/stream'
+import { Link } from '@sourcegraph/wildcard'
+
+import { ResultContainer } from './ResultContainer'
+
+import styles from './SearchResult.module.scss'
+
+export interface PersonSearchResultProps {
+    result: PersonMatch
+    onSelect: () => void
+    containerClassName?: string
+    as?: React.ElementType
+    index: number
+}
+
+export const PersonSearchResult: React.FunctionComponent<PersonSearchResultProps> = ({
+    result,
+    onSelect,
+    containerClassName,
+    as,
+    index,
+}) => {
+    const renderTitle = (): JSX.Element => (
+        <div className={styles.title}>
+            <span className={classNames('test-search-result-label', styles.titleInner)}>
+                {result.name || result.handle || result.email}
+                {result.name && result.email && <span className="text-muted"> &lt;{result.email}&gt;</span>}
+                <span className="text-muted">
+                    {' in '}
+                    <Link to={getPersonMatchUrl(result)}>{displayRepoName(result.repository)}</Link>
+                </span>
+            </span>
+        </div>
+    )
+
+    return (
+        <ResultContainer
+            index={index}
+            icon={AccountIcon}
+            collapsible={false}
+            defaultExpanded={false}
+            title={renderTitle()}
+            resultType={result.type}
+            onResultClicked={onSelect}
+            repoName={result.repository}
+            className={containerClassName}
+            as={as}
+        />
+    )
+}
@@ -1,9 +1,11 @@
+export * from './CaptureGroupSearchResult'
 export * from './CodeExcerpt'
 export * from './CodeHostIcon'
 export * from './CommitSearchResult'
 export * from './CommitSearchResultMatch'
 export * from './FileSearchResult'
 export * from './LastSyncedIcon'
+export * from './PersonSearchResult'
 export * from './RepoFileLink'
 export * from './RepoSearchResult'
 export * from './ResultContainer'
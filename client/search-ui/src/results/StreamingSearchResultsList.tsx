@@ -10,7 +10,14 @@ import { Observable } from 'rxjs'
 import { HoverMerged } from '@sourcegraph/client-api'
 import { Hoverifier } from '@sourcegraph/codeintellify'
 import { SearchContextProps } from '@sourcegraph/search'
-import { CommitSearchResult, RepoSearchResult, FileSearchResult, FetchFileParameters } from '@sourcegraph/search-ui'
+import {
+    CaptureGroupSearchResult,
+    CommitSearchResult,
+    PersonSearchResult,
+    RepoSearchResult,
+    FileSearchResult,
+    FetchFileParameters,
+} from '@sourcegraph/search-ui'
 import { ActionItemAction } from '@sourcegraph/shared/src/actions/ActionItem'
 import { FilePrefetcher, PrefetchableFile } from '@sourcegraph/shared/src/components/PrefetchableFile'
 import { displayRepoName } from '@sourcegraph/shared/src/components/RepoLink'
@@ -185,6 +192,26 @@ export const StreamingSearchResultsList: React.FunctionComponent<
                             as="li"
                         />
                     )
+                case 'person':
+                    return (
+                        <PersonSearchResult
+                            index={index}
+                            result={result}
+                            onSelect={() => logSearchResultClicked(index, 'person')}
+                            containerClassName={resultClassName}
+                            as="li"
+                        />
+                    )
+                case 'group':
+                    return (
+                        <CaptureGroupSearchResult
+                            index={index}
+                            result={result}
+                            onSelect={() => logSearchResultClicked(index, 'group')}
+                            containerClassName={resultClassName}
+                            as="li"
+                        />
+                    )
                 case 'repo':
                     return (
                         <RepoSearchResult
@@ -107,6 +107,20 @@ To use this filter, the search query must contain \`type:diff\` or \`type:commit
         commonRank: 100,
         examples: ['before:"last thursday"', 'before:"november 1 2019"'],
     },
+    {
+        ...createQueryExampleFromString('{username}'),
+        field: FilterType['blame.author'],
+        description:
+            'Only include matched lines of file contents that were last changed by the user, according to `git blame`. Regexps are supported and match the name or email of the author.',
+        examples: ['TODO blame.author:nick', 'TODO -blame.author:bot@example.com'],
+    },
+    {
+        ...createQueryExampleFromString('"{last thursday}"'),
+        field: FilterType['blame.before'],
+        description:
+            'Only include matched lines of file contents that were last changed before the specified time frame, according to `git blame`.',
+        examples: ['FIXME blame.before:"1 year ago"'],
+    },
     {
         ...createQueryExampleFromString('{yes}'),
         field: FilterType.case,
@@ -234,8 +248,10 @@ To use this filter, the search query must contain \`type:diff\` or \`type:commit
 - \`select:file\`
 - \`select:file.directory\`
 - \`select:file.path\`
+- \`select:file.owners\`
 - \`select:content\`
 - \`select:symbol.symboltype\`
+- \`select:blame.author\`
 
 See [language definition](https://docs.sourcegraph.com/code_search/reference/language#select) for more information on possible values.`,
         examples: ['fmt.Errorf select:repo', 'select:commit.diff.added //TODO', 'select:file.directory'],
@@ -12,6 +12,8 @@ export enum FilterType {
     archived = 'archived',
     author = 'author',
     before = 'before',
+    'blame.author' = 'blame.author',
+    'blame.before' = 'blame.before',
     case = 'case',
     committer = 'committer',
     content = 'content',
@@ -74,6 +76,7 @@ export const filterTypeKeysWithAliases: (FilterType | AliasedFilterType)[] = [
 
 export enum NegatedFilters {
     author = '-author',
+    'blame.author' = '-blame.author',
     committer = '-committer',
     content = '-content',
     f = '-f',
@@ -97,6 +100,7 @@ export type NegatableFilter =
     | FilterType.content
     | FilterType.committer
     | FilterType.author
+    | FilterType['blame.author']
     | FilterType.message
 
 export const isNegatableFilter = (filter: FilterType): filter is NegatableFilter =>
@@ -110,6 +114,7 @@ export const isNegatedFilter = (filter: string): filter is NegatedFilters =>
 
 const negatedFilterToNegatableFilter: { [key: string]: NegatableFilter } = {
     '-author': FilterType.author,
+    '-blame.author': FilterType['blame.author'],
     '-committer': FilterType.committer,
     '-content': FilterType.content,
     '-f': FilterType.file,
@@ -202,6 +207,17 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         description: 'Commits made before a certain date',
         placeholder: '"time frame"',
     },
+    [FilterType['blame.author']]: {
+        negatable: true,
+        description: negated =>
+            `${negated ? 'Exclude' : 'Include only'} matched lines last changed by a user, according to git blame.`,
+        placeholder: '"author name/email"',
+    },
+    [FilterType['blame.before']]: {
+        description: 'Matched lines last changed before a certain date, according to git blame',
+        placeholder: '"time frame"',
+        singular: true,
+    },
     [FilterType.case]: {
         description: 'Treat the search pattern as case-sensitive.',
         discreteValues: () => ['yes', 'no'].map(value => ({ label: value })),
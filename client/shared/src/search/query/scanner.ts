@@ -333,7 +333,9 @@ const comment = scanToken(
     (value, { start, end }): Comment => ({ type: 'comment', value, range: { start, end } })
 )
 
-const filterField = scanToken(new RegExp(`-?(${filterTypeKeysWithAliases.join('|')})+(?=:)`, 'i'))
+const filterField = scanToken(
+    new RegExp(`-?(${filterTypeKeysWithAliases.map(key => key.replace('.', '\\.')).join('|')})+(?=:)`, 'i')
+)
 
 const filterValue = oneOf<Literal>(quoted('"'), quoted("'"), scanBalancedLiteral, literal)
 
@@ -15,7 +15,8 @@ describe('selectorCompletion', () => {
             file,
             content,
             symbol,
-            commit
+            commit,
+            blame
         `)
     })
 
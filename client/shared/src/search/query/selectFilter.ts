@@ -11,10 +11,11 @@ export const SELECTORS: Access[] = [
     },
     {
         name: 'file',
-        fields: [{ name: 'directory' }, { name: 'path' }],
+        fields: [{ name: 'directory' }, { name: 'owners' }, { name: 'path' }],
     },
     {
         name: 'content',
+        fields: [{ name: 'group(1)' }],
     },
     {
         name: 'symbol',
@@ -51,6 +52,10 @@ export const SELECTORS: Access[] = [
         name: 'commit',
         fields: [{ name: 'diff', fields: [{ name: 'added' }, { name: 'removed' }] }],
     },
+    {
+        name: 'blame',
+        fields: [{ name: 'author' }],
+    },
 ]
 
 /**
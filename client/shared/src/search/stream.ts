@@ -29,7 +29,14 @@ export type SearchEvent =
     | { type: 'error'; data: ErrorLike }
     | { type: 'done'; data: {} }
 
-export type SearchMatch = ContentMatch | RepositoryMatch | CommitMatch | SymbolMatch | PathMatch
+export type SearchMatch =
+    | ContentMatch
+    | RepositoryMatch
+    | CommitMatch
+    | SymbolMatch
+    | PathMatch
+    | PersonMatch
+    | CaptureGroupMatch
 
 export interface PathMatch {
     type: 'path'
@@ -149,6 +156,30 @@ export interface RepositoryMatch {
     descriptionMatches?: Range[]
 }
 
+/**
+ * A person selected from the results of a search, such as the author of matched
+ * lines (select:blame.author) or an owner of matched files (select:file.owners).
+ * Owners may also be teams.
+ */
+export interface PersonMatch {
+    type: 'person'
+    name: string
+    email?: string
+    handle?: string
+    repository: string
+}
+
+/**
+ * A distinct value of a capture group of the search pattern in the content
+ * matches of a repository (select:content.group(...)).
+ */
+export interface CaptureGroupMatch {
+    type: 'group'
+    value: string
+    count: number
+    repository: string
+}
+
 /**
  * An aggregate type representing a progress update.
  * Should be replaced when a new ones come in.
@@ -178,6 +209,10 @@ export interface Progress {
 
     // The URL of the trace for this query, if it exists.
     trace?: string
+
+    // The cursor of the next page of results of a paginated search. Only set
+    // on the final progress event of a search which hit its result limit.
+    cursor?: string
 }
 
 export interface Skipped {
@@ -550,6 +585,14 @@ export function getCommitMatchUrl(commitMatch: CommitMatch): string {
     return '/' + encodeURI(commitMatch.repository) + '/-/commit/' + commitMatch.oid
 }
 
+export function getPersonMatchUrl(personMatch: PersonMatch): string {
+    return '/' + encodeURI(personMatch.repository)
+}
+
+export function getCaptureGroupMatchUrl(captureGroupMatch: CaptureGroupMatch): string {
+    return '/' + encodeURI(captureGroupMatch.repository)
+}
+
 export function getMatchUrl(match: SearchMatch): string {
     switch (match.type) {
         case 'path':
@@ -558,6 +601,10 @@ export function getMatchUrl(match: SearchMatch): string {
             return getFileMatchUrl(match)
         case 'commit':
             return getCommitMatchUrl(match)
+        case 'person':
+            return getPersonMatchUrl(match)
+        case 'group':
+            return getCaptureGroupMatchUrl(match)
         case 'repo':
             return getRepoMatchUrl(match)
     }
@@ -16,6 +16,7 @@ import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
 import { Link, Code, Text } from '@sourcegraph/wildcard'
 
 import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema.json'
+import azureDevOpsSchemaJSON from '../../../../../schema/azure_devops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
 import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
@@ -24,6 +25,7 @@ import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
 import goModulesSchemaJSON from '../../../../../schema/go-modules.schema.json'
 import jvmPackagesSchemaJSON from '../../../../../schema/jvm-packages.schema.json'
+import mercurialSchemaJSON from '../../../../../schema/mercurial.schema.json'
 import npmPackagesSchemaJSON from '../../../../../schema/npm-packages.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../../schema/other_external_service.schema.json'
 import pagureSchemaJSON from '../../../../../schema/pagure.schema.json'
@@ -31,6 +33,7 @@ import perforceSchemaJSON from '../../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../../schema/phabricator.schema.json'
 import pythonPackagesJSON from '../../../../../schema/python-packages.schema.json'
 import rustPackagesJSON from '../../../../../schema/rust-packages.schema.json'
+import subversionSchemaJSON from '../../../../../schema/subversion.schema.json'
 import { ExternalRepositoryFields, ExternalServiceKind } from '../../graphql-operations'
 import { EditorAction } from '../../settings/EditorActionsGroup'
 import { PerforceIcon } from '../PerforceIcon'
@@ -1201,6 +1204,92 @@ const PERFORCE: AddExternalServiceOptions = {
         },
     ],
 }
+const SUBVERSION: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.SUBVERSION,
+    title: 'Subversion',
+    icon: GitIcon,
+    jsonSchema: subversionSchemaJSON,
+    defaultDisplayName: 'Subversion',
+    defaultConfig: `{
+  "url": "https://svn.example.com/repos",
+  "username": "<username>",
+  "password": "<password>"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL below which your Subversion
+                    repositories live. Each directory directly below it is mirrored as a repository, unless you list
+                    the repositories in <Field>repos</Field>.
+                </li>
+                <li>
+                    Set <Field>username</Field> and <Field>password</Field> to the credentials of a user with read
+                    access to the repositories.
+                </li>
+                <li>
+                    If your repositories do not use the standard <Code>trunk</Code>, <Code>branches</Code> and{' '}
+                    <Code>tags</Code> layout, set <Field>layout</Field>.
+                </li>
+                <li>
+                    Optionally, map Subversion usernames to Git authors in <Field>authors</Field>.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addAuthor',
+            label: 'Add author',
+            run: (config: string) => {
+                const value = 'Jane Doe <jane@example.com>'
+                const edits = modify(config, ['authors', 'jdoe'], value, defaultModificationOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+const MERCURIAL: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.MERCURIAL,
+    title: 'Mercurial',
+    icon: GitIcon,
+    jsonSchema: mercurialSchemaJSON,
+    defaultDisplayName: 'Mercurial',
+    defaultConfig: `{
+  "url": "https://hg.example.com",
+  "repos": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to be the URL of your Mercurial host. If your
+                    repositories require authentication, include the credentials in the URL.
+                </li>
+                <li>
+                    Add the paths of the repositories you wish to index to the <Field>repos</Field> field. These will be
+                    appended to the host URL to obtain the repository URLs.
+                </li>
+            </ol>
+            <Text>
+                Bookmarks and named branches are mirrored as Git branches, with the <Code>default</Code> branch
+                becoming <Code>master</Code>.
+            </Text>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addRepo',
+            label: 'Add a repository',
+            run: (config: string) => {
+                const value = 'path/to/repository'
+                const edits = modify(config, ['repos', -1], value, defaultModificationOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+
 const JVM_PACKAGES: AddExternalServiceOptions = {
     kind: ExternalServiceKind.JVMPACKAGES,
     title: 'JVM Dependencies',
@@ -1235,6 +1324,42 @@ const JVM_PACKAGES: AddExternalServiceOptions = {
     editorActions: [],
 }
 
+const AZURE_DEVOPS: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.AZUREDEVOPS,
+    title: 'Azure DevOps',
+    icon: GitIcon,
+    jsonSchema: azureDevOpsSchemaJSON,
+    defaultDisplayName: 'Azure DevOps',
+    defaultConfig: `{
+  "url": "https://dev.azure.com",
+  "username": "<username>",
+  "token": "<personal access token>",
+  "orgs": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to <Value>https://dev.azure.com</Value> or to the
+                    URL of your Azure DevOps Server collection.
+                </li>
+                <li>
+                    Create a personal access token with the <Code>Code (Read)</Code> scope and set{' '}
+                    <Field>username</Field> and <Field>token</Field> in the configuration below.
+                </li>
+                <li>
+                    Set <Field>orgs</Field> to the organizations whose repositories should be mirrored, or{' '}
+                    <Field>projects</Field> to individual projects (<Value>org/project</Value>).
+                </li>
+                <li>
+                    You can optionally exclude repositories using the <Field>exclude</Field> field.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [],
+}
+
 const PAGURE: AddExternalServiceOptions = {
     kind: ExternalServiceKind.PAGURE,
     title: 'Pagure',
@@ -1407,6 +1532,9 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucket: BITBUCKET_CLOUD,
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
+    azuredevops: AZURE_DEVOPS,
+    subversion: SUBVERSION,
+    mercurial: MERCURIAL,
     srcservegit: SRC_SERVE_GIT,
     gitolite: GITOLITE,
     git: GENERIC_GIT,
@@ -1438,6 +1566,7 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [ExternalServiceKind.OTHER]: GENERIC_GIT,
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
+    [ExternalServiceKind.AZUREDEVOPS]: AZURE_DEVOPS,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
     [ExternalServiceKind.GERRIT]: GERRIT,
     [ExternalServiceKind.PAGURE]: PAGURE,
@@ -1446,6 +1575,8 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.NPMPACKAGES]: NPM_PACKAGES,
     [ExternalServiceKind.PYTHONPACKAGES]: PYTHON_PACKAGES,
     [ExternalServiceKind.RUSTPACKAGES]: RUST_PACKAGES,
+    [ExternalServiceKind.SUBVERSION]: SUBVERSION,
+    [ExternalServiceKind.MERCURIAL]: MERCURIAL,
 }
 
 export const externalRepoIcon = (
@@ -115,6 +115,21 @@ export const AggregationModeControls: FC<AggregationModeControlsProps> = props =
                     </Button>
                 </Tooltip>
             </div>
+
+            <div onMouseEnter={() => handleModeHover(SearchAggregationMode.OWNER)}>
+                <Tooltip content={availabilityGroups[SearchAggregationMode.OWNER]?.reasonUnavailable}>
+                    <Button
+                        variant="secondary"
+                        size={size}
+                        outline={mode !== SearchAggregationMode.OWNER}
+                        disabled={!isModeAvailable(SearchAggregationMode.OWNER)}
+                        data-testid="owner-aggregation-mode"
+                        onClick={() => onModeChange(SearchAggregationMode.OWNER)}
+                    >
+                        Owner
+                    </Button>
+                </Tooltip>
+            </div>
         </div>
     )
 }
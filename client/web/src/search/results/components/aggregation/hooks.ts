@@ -54,7 +54,7 @@ function useSyncedWithURLState<State, SerializedState>(
     return [queryParameter, setNextState]
 }
 
-type SerializedAggregationMode = 'repo' | 'path' | 'author' | 'group' | ''
+type SerializedAggregationMode = 'repo' | 'path' | 'author' | 'group' | 'owner' | ''
 
 const aggregationModeSerializer = (mode: SearchAggregationMode | null): SerializedAggregationMode => {
     switch (mode) {
@@ -66,6 +66,8 @@ const aggregationModeSerializer = (mode: SearchAggregationMode | null): Serializ
             return 'author'
         case SearchAggregationMode.CAPTURE_GROUP:
             return 'group'
+        case SearchAggregationMode.OWNER:
+            return 'owner'
 
         default:
             return ''
@@ -84,6 +86,8 @@ const aggregationModeDeserializer = (
             return SearchAggregationMode.AUTHOR
         case 'group':
             return SearchAggregationMode.CAPTURE_GROUP
+        case 'owner':
+            return SearchAggregationMode.OWNER
 
         default:
             return null
@@ -12,6 +12,7 @@ const (
 	GraphQL    = "graphql"
 
 	SearchStream  = "search.stream"
+	SearchExport  = "search.export"
 	ComputeStream = "compute.stream"
 
 	SrcCli             = "src-cli"
@@ -57,6 +58,7 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/bitbucket-cloud-webhooks").Methods("POST").Name(BitbucketCloudWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/search/stream").Methods("GET").Name(SearchStream)
+	base.Path("/search/export").Methods("GET").Name(SearchExport)
 	base.Path("/compute/stream").Methods("GET", "POST").Name(ComputeStream)
 	base.Path("/src-cli/versions/{rest:.*}").Methods("GET", "POST").Name(SrcCliVersionCache)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCli)
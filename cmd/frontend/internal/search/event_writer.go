@@ -1,7 +1,12 @@
 package search
 
 import (
+	"encoding/json"
+
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/printer"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
@@ -72,3 +77,22 @@ func (e *eventWriter) Alert(alert *search.Alert) error {
 		ProposedQueries: pqs,
 	})
 }
+
+func (e *eventWriter) Explain(plan job.Job, estimates []*jobutil.JobEstimate) error {
+	jobs := make([]streamhttp.EventJobEstimate, 0, len(estimates))
+	for _, estimate := range estimates {
+		jobs = append(jobs, streamhttp.EventJobEstimate{
+			Job:           estimate.Job,
+			Repos:         estimate.Repos,
+			ReposLimitHit: estimate.ReposLimitHit,
+			Indexed:       estimate.Indexed,
+			Unindexed:     estimate.Unindexed,
+			Global:        estimate.Global,
+			Fanout:        estimate.Fanout,
+		})
+	}
+	return e.inner.Event("explain", streamhttp.EventExplain{
+		Plan: json.RawMessage(printer.JSONVerbose(plan, job.VerbosityBasic)),
+		Jobs: jobs,
+	})
+}
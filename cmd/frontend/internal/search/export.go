@@ -0,0 +1,477 @@
+package search
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ExportHandler is an http handler which streams back all results of a search
+// as CSV or JSON Lines, one row per matched line, symbol, path, repository or
+// commit.
+//
+// Unlike StreamHandler, it writes results as soon as they are found instead
+// of batching them into events. Writing blocks the search, so a slow client
+// slows the search down rather than buffering results in memory.
+func ExportHandler(db database.DB) http.Handler {
+	logger := log.Scoped("searchExportHandler", "")
+	return &exportHandler{
+		logger:       logger,
+		db:           db,
+		searchClient: client.NewSearchClient(logger, db, search.Indexed(), search.SearcherURLs()),
+	}
+}
+
+type exportHandler struct {
+	logger       log.Logger
+	db           database.DB
+	searchClient client.SearchClient
+}
+
+// The formats results can be exported in.
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+)
+
+type exportArgs struct {
+	Query       string
+	Version     string
+	PatternType string
+	Format      string
+}
+
+func parseExportURLQuery(q url.Values) (*exportArgs, error) {
+	get := func(k, def string) string {
+		v := q.Get(k)
+		if v == "" {
+			return def
+		}
+		return v
+	}
+
+	a := exportArgs{
+		Query:       get("q", ""),
+		Version:     get("v", "V3"),
+		PatternType: get("t", ""),
+		Format:      get("format", exportFormatCSV),
+	}
+
+	if a.Query == "" {
+		return nil, errors.New("no query found")
+	}
+	if a.Format != exportFormatCSV && a.Format != exportFormatJSONL {
+		return nil, errors.Errorf("format must be %q or %q, got %q", exportFormatCSV, exportFormatJSONL, a.Format)
+	}
+
+	return &a, nil
+}
+
+var queryCountRegex = lazyregexp.New(`\bcount:(\d+|all)\b`)
+
+// withCountAll returns query with count:all, unless it already specifies a
+// count, so that exports return all results by default.
+func withCountAll(query string) string {
+	if queryCountRegex.MatchString(query) {
+		return query
+	}
+	return query + " count:all"
+}
+
+func (h *exportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tr, ctx := trace.New(r.Context(), "search.ServeExport", "")
+	defer tr.Finish()
+
+	err := h.serveHTTP(ctx, w, r, tr)
+	if err != nil {
+		tr.SetError(err)
+	}
+}
+
+func (h *exportHandler) serveHTTP(ctx context.Context, w http.ResponseWriter, r *http.Request, tr *trace.Trace) (err error) {
+	start := time.Now()
+
+	args, err := parseExportURLQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+	tr.TagFields(
+		otlog.String("query", args.Query),
+		otlog.String("version", args.Version),
+		otlog.String("pattern_type", args.PatternType),
+		otlog.String("format", args.Format),
+	)
+
+	settings, err := graphqlbackend.DecodedViewerFinalSettings(ctx, h.db)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	inputs, err := h.searchClient.Plan(ctx, args.Version, strPtr(args.PatternType), withCountAll(args.Query), search.Streaming, settings, envvar.SourcegraphDotComMode())
+	if err != nil {
+		var queryErr *client.QueryError
+		if errors.As(err, &queryErr) {
+			http.Error(w, queryErr.Err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return err
+	}
+
+	var rw exportRowWriter
+	switch args.Format {
+	case exportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="search-results.csv"`)
+		rw = newCSVRowWriter(w)
+	case exportFormatJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="search-results.jsonl"`)
+		rw = newJSONLRowWriter(w)
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Stop searching once we can no longer write, for example because the
+	// client went away.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	flusher, _ := w.(http.Flusher)
+	stream := &exportStream{
+		ctx:     ctx,
+		cancel:  cancel,
+		logger:  h.logger,
+		db:      h.db,
+		w:       rw,
+		flusher: flusher,
+		progress: &streamclient.ProgressAggregator{
+			Start: start,
+			Limit: inputs.MaxResults(),
+		},
+	}
+	if err := rw.WriteHeader(); err != nil {
+		return err
+	}
+
+	alert, err := h.searchClient.Execute(ctx, stream, inputs)
+	logSearch(ctx, h.logger, alert, err, start, inputs.OriginalQuery, stream.progress)
+	if stream.err != nil {
+		return stream.err
+	}
+	if err != nil {
+		// The status code has already been sent, so all we can do is
+		// log the error.
+		h.logger.Error("search export failed", log.String("query", args.Query), log.Error(err))
+	}
+	return err
+}
+
+// exportStream writes the results of a search to an exportRowWriter.
+type exportStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger log.Logger
+	db     database.DB
+
+	mu       sync.Mutex
+	w        exportRowWriter
+	flusher  http.Flusher
+	progress *streamclient.ProgressAggregator
+	// err is the first error writing rows. Once set, results are dropped.
+	err error
+}
+
+func (s *exportStream) Send(event streaming.SearchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return
+	}
+	s.progress.Update(event)
+
+	repoMetadata, err := getEventRepoMetadata(s.ctx, s.db, event)
+	if err != nil {
+		s.logger.Error("failed to get repo metadata", log.Error(err))
+		return
+	}
+
+	for _, match := range event.Results {
+		repo := match.RepoName()
+
+		// Like the streaming search, don't export matches which we cannot
+		// map to a repo the actor has access to.
+		if md, ok := repoMetadata[repo.ID]; !ok || md.Name != repo.Name {
+			continue
+		}
+
+		for _, row := range exportRows(match) {
+			if err := s.w.WriteRow(row); err != nil {
+				s.fail(err)
+				return
+			}
+		}
+	}
+
+	if err := s.w.Flush(); err != nil {
+		s.fail(err)
+		return
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func (s *exportStream) fail(err error) {
+	s.err = errors.Wrap(err, "writing search export")
+	s.cancel()
+}
+
+// exportRow is a row of a search export. Which fields are set depends on the
+// type of the result.
+type exportRow struct {
+	// Type is the type of the result: content, symbol, path, repo, commit,
+	// diff, person or group.
+	Type       string `json:"type"`
+	Repository string `json:"repository"`
+	// Revision is the revision the result was searched at, if it was not
+	// the default branch.
+	Revision string `json:"revision,omitempty"`
+	// Commit is the commit of files and the ID of commits.
+	Commit string `json:"commit,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// Line is the 1-based line of content and symbol results.
+	Line int `json:"line,omitempty"`
+	// Preview is the matched line of content results, the name of symbols,
+	// the subject of commits, the name of persons and the value of capture
+	// groups.
+	Preview string     `json:"preview,omitempty"`
+	Author  string     `json:"author,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+	// Count is the number of matches of capture group values.
+	Count int `json:"count,omitempty"`
+}
+
+var exportColumns = []string{"type", "repository", "revision", "commit", "path", "line", "preview", "author", "date", "count"}
+
+func (r exportRow) columns() []string {
+	var line, date, count string
+	if r.Line > 0 {
+		line = strconv.Itoa(r.Line)
+	}
+	if r.Date != nil {
+		date = r.Date.UTC().Format(time.RFC3339)
+	}
+	if r.Count > 0 {
+		count = strconv.Itoa(r.Count)
+	}
+	return []string{r.Type, r.Repository, r.Revision, r.Commit, r.Path, line, r.Preview, r.Author, date, count}
+}
+
+// exportRows returns the rows of the result match.
+func exportRows(match result.Match) []exportRow {
+	switch v := match.(type) {
+	case *result.FileMatch:
+		base := exportRow{
+			Repository: string(v.Repo.Name),
+			Commit:     string(v.CommitID),
+			Path:       v.Path,
+		}
+		if v.InputRev != nil {
+			base.Revision = *v.InputRev
+		}
+
+		var rows []exportRow
+		if len(v.Symbols) > 0 {
+			for _, sym := range v.Symbols {
+				row := base
+				row.Type = "symbol"
+				row.Line = sym.Symbol.Line
+				row.Preview = sym.Symbol.Name
+				rows = append(rows, row)
+			}
+			return rows
+		}
+
+		lineMatches := v.ChunkMatches.AsLineMatches()
+		if len(lineMatches) == 0 {
+			row := base
+			row.Type = "path"
+			return []exportRow{row}
+		}
+		for _, lm := range lineMatches {
+			row := base
+			row.Type = "content"
+			row.Line = int(lm.LineNumber) + 1
+			row.Preview = lm.Preview
+			rows = append(rows, row)
+		}
+		return rows
+
+	case *result.RepoMatch:
+		return []exportRow{{
+			Type:       "repo",
+			Repository: string(v.Name),
+			Revision:   v.Rev,
+		}}
+
+	case *result.CommitMatch:
+		return exportCommitRows(v)
+
+	case *result.CommitDiffMatch:
+		return exportCommitRows(v.ToCommitMatch())
+
+	case *result.CaptureGroupMatch:
+		return []exportRow{{
+			Type:       "group",
+			Repository: string(v.Repo.Name),
+			Preview:    v.Value,
+			Count:      v.Count,
+		}}
+
+	case *result.PersonMatch:
+		preview := v.Name
+		if v.Email != "" {
+			preview += " <" + v.Email + ">"
+		}
+		return []exportRow{{
+			Type:       "person",
+			Repository: string(v.Repo.Name),
+			Preview:    preview,
+		}}
+	}
+	return nil
+}
+
+// exportCommitRows returns a row for each modified file of diff results, or a
+// single row for commit results.
+func exportCommitRows(cm *result.CommitMatch) []exportRow {
+	subject, _, _ := strings.Cut(string(cm.Commit.Message), "\n")
+	date := cm.Commit.Author.Date
+	base := exportRow{
+		Type:       "commit",
+		Repository: string(cm.Repo.Name),
+		Commit:     string(cm.Commit.ID),
+		Preview:    subject,
+		Author:     cm.Commit.Author.Name,
+		Date:       &date,
+	}
+	if len(cm.SourceRefs) > 0 {
+		base.Revision = cm.SourceRefs[0]
+	}
+
+	if cm.DiffPreview == nil {
+		return []exportRow{base}
+	}
+
+	base.Type = "diff"
+	if len(cm.Diff) == 0 {
+		return []exportRow{base}
+	}
+	rows := make([]exportRow, 0, len(cm.Diff))
+	for _, fd := range cm.Diff {
+		row := base
+		row.Path = fd.NewName
+		if row.Path == "/dev/null" {
+			row.Path = fd.OrigName
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// exportRowWriter writes the rows of a search export in a format.
+type exportRowWriter interface {
+	// WriteHeader writes the header of the export, if the format has one.
+	WriteHeader() error
+	WriteRow(exportRow) error
+	// Flush writes any buffered rows.
+	Flush() error
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func newCSVRowWriter(w io.Writer) *csvRowWriter {
+	return &csvRowWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvRowWriter) WriteHeader() error {
+	return w.w.Write(exportColumns)
+}
+
+func (w *csvRowWriter) WriteRow(row exportRow) error {
+	columns := row.columns()
+	for i, cell := range columns {
+		columns[i] = escapeCSVFormula(cell)
+	}
+	return w.w.Write(columns)
+}
+
+// escapeCSVFormula prefixes cells which spreadsheet applications would
+// interpret as a formula with a single quote, so that matched content cannot
+// run formulas when the export is opened.
+func escapeCSVFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
+
+func (w *csvRowWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlRowWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLRowWriter(w io.Writer) *jsonlRowWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlRowWriter{enc: enc}
+}
+
+func (w *jsonlRowWriter) WriteHeader() error {
+	return nil
+}
+
+func (w *jsonlRowWriter) WriteRow(row exportRow) error {
+	return w.enc.Encode(row)
+}
+
+func (w *jsonlRowWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,206 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func newExportTestServer(t *testing.T, wantQuery string) *httptest.Server {
+	t.Helper()
+
+	graphqlbackend.MockDecodedViewerFinalSettings = &schema.Settings{}
+	t.Cleanup(func() { graphqlbackend.MockDecodedViewerFinalSettings = nil })
+
+	repo := types.MinimalRepo{ID: 1, Name: "github.com/foo/bar"}
+	hidden := types.MinimalRepo{ID: 2, Name: "github.com/foo/hidden"}
+	date := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultHook(func(_ context.Context, _ string, _ *string, q string, _ search.Protocol, _ *schema.Settings, _ bool) (*search.Inputs, error) {
+		require.Equal(t, wantQuery, q)
+		return &search.Inputs{}, nil
+	})
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, _ *search.Inputs) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{
+				&result.FileMatch{
+					File: result.File{Repo: repo, CommitID: "deadbeef", Path: "main.go"},
+					ChunkMatches: result.ChunkMatches{{
+						Content:      "func main() {",
+						ContentStart: result.Location{Offset: 14, Line: 2},
+						Ranges: result.Ranges{{
+							Start: result.Location{Offset: 19, Line: 2, Column: 5},
+							End:   result.Location{Offset: 23, Line: 2, Column: 9},
+						}},
+					}},
+				},
+				// Not returned by the repo store, so it must not be exported.
+				&result.FileMatch{File: result.File{Repo: hidden, Path: "secret.go"}},
+			},
+		})
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{
+				&result.RepoMatch{Name: repo.Name, ID: repo.ID},
+				&result.CommitMatch{
+					Repo: repo,
+					Commit: gitdomain.Commit{
+						ID:      "cafe",
+						Message: "Fix main\n\nLonger description.",
+						Author:  gitdomain.Signature{Name: "Alice", Date: date},
+					},
+					MessagePreview: &result.MatchedString{Content: "Fix main"},
+				},
+			},
+		})
+		return nil, nil
+	})
+
+	mockRepos := database.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultHook(func(_ context.Context, ids ...api2.RepoID) ([]*types.SearchedRepo, error) {
+		var out []*types.SearchedRepo
+		for _, id := range ids {
+			if id == repo.ID {
+				out = append(out, &types.SearchedRepo{ID: repo.ID, Name: repo.Name})
+			}
+		}
+		return out, nil
+	})
+
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(&exportHandler{
+		logger:       logtest.Scoped(t),
+		db:           db,
+		searchClient: mock,
+	})
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestServeExport_csv(t *testing.T) {
+	ts := newExportTestServer(t, "main count:all")
+
+	res, err := http.Get(ts.URL + "?q=main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+
+	records, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"type", "repository", "revision", "commit", "path", "line", "preview", "author", "date", "count"},
+		{"content", "github.com/foo/bar", "", "deadbeef", "main.go", "3", "func main() {", "", "", ""},
+		{"repo", "github.com/foo/bar", "", "", "", "", "", "", "", ""},
+		{"commit", "github.com/foo/bar", "", "cafe", "", "", "Fix main", "Alice", "2022-08-01T12:00:00Z", ""},
+	}
+	require.Equal(t, want, records)
+}
+
+func TestServeExport_jsonl(t *testing.T) {
+	ts := newExportTestServer(t, "main count:10")
+
+	res, err := http.Get(ts.URL + "?q=main+count:10&format=jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+	var rowTypes []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		var row exportRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatal(err)
+		}
+		require.Equal(t, "github.com/foo/bar", row.Repository)
+		rowTypes = append(rowTypes, row.Type)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, []string{"content", "repo", "commit"}, rowTypes)
+}
+
+func TestServeExport_invalidFormat(t *testing.T) {
+	ts := newExportTestServer(t, "")
+
+	res, err := http.Get(ts.URL + "?q=main&format=xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestCSVRowWriter_formulas(t *testing.T) {
+	var buf bytes.Buffer
+	w := newCSVRowWriter(&buf)
+	require.NoError(t, w.WriteRow(exportRow{
+		Type:       "content",
+		Repository: "github.com/foo/bar",
+		Path:       "-cmd.csv",
+		Line:       1,
+		Preview:    "=HYPERLINK(\"https://example.com\")",
+		Author:     "@alice",
+	}))
+	require.NoError(t, w.Flush())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"content", "github.com/foo/bar", "", "", "'-cmd.csv", "1", "'=HYPERLINK(\"https://example.com\")", "'@alice", "", ""},
+	}
+	require.Equal(t, want, records)
+}
+
+func TestExportRows_diff(t *testing.T) {
+	rows := exportRows(&result.CommitMatch{
+		Repo:        types.MinimalRepo{Name: "github.com/foo/bar"},
+		Commit:      gitdomain.Commit{ID: "cafe", Message: "Remove old"},
+		SourceRefs:  []string{"main"},
+		DiffPreview: &result.MatchedString{},
+		Diff: []result.DiffFile{
+			{OrigName: "a.go", NewName: "a.go"},
+			{OrigName: "old.go", NewName: "/dev/null"},
+		},
+	})
+
+	var paths []string
+	for _, row := range rows {
+		require.Equal(t, "diff", row.Type)
+		require.Equal(t, "main", row.Revision)
+		paths = append(paths, row.Path)
+	}
+	require.Equal(t, []string{"a.go", "old.go"}, paths)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
@@ -114,6 +115,11 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 		}
 	}
 
+	// Explain the plan of the search instead of running it.
+	if args.Explain {
+		return h.serveExplain(ctx, eventWriter, inputs)
+	}
+
 	// Display is the number of results we send down. If display is < 0 we
 	// want to send everything we find before hitting a limit. Otherwise we
 	// can only send up to limit results.
@@ -123,6 +129,23 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 		displayLimit = limit
 	}
 
+	// Paginated searches compute the cursor of the next page while they run.
+	var paginator *search.Paginator
+	if args.Paginate {
+		if displayLimit < limit {
+			return errors.New("display must not be smaller than the result limit of paginated searches")
+		}
+		cursor := search.NewCursor(args.cursorQuery())
+		if args.Cursor != "" {
+			cursor, err = search.DecodeCursor(args.Cursor, args.cursorQuery())
+			if err != nil {
+				return err
+			}
+		}
+		paginator = search.NewPaginator(cursor)
+		ctx = search.WithPaginator(ctx, paginator)
+	}
+
 	progress := &streamclient.ProgressAggregator{
 		Start:        start,
 		Limit:        limit,
@@ -160,6 +183,11 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 	alert, err := h.searchClient.Execute(ctx, batchedStream, inputs)
 	// Clean up streams before writing to eventWriter again.
 	batchedStream.Done()
+	if paginator != nil {
+		if next := paginator.Next(); next != nil {
+			progress.Cursor = next.Encode()
+		}
+	}
 	eventHandler.Done()
 	if alert != nil {
 		eventWriter.Alert(alert)
@@ -168,6 +196,20 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr *trace.Trace, eventWriter
 	return err
 }
 
+// serveExplain writes the planned job tree of the search of inputs along with
+// its estimated cost, without running the search.
+func (h *streamHandler) serveExplain(ctx context.Context, eventWriter *eventWriter, inputs *search.Inputs) error {
+	planJob, err := jobutil.NewPlanJob(inputs, inputs.Plan)
+	if err != nil {
+		return err
+	}
+	estimates, err := jobutil.Explain(ctx, h.searchClient.JobClients(), planJob)
+	if err != nil {
+		return err
+	}
+	return eventWriter.Explain(planJob, estimates)
+}
+
 func logSearch(ctx context.Context, logger log.Logger, alert *search.Alert, err error, start time.Time, originalQuery string, progress *streamclient.ProgressAggregator) {
 	status := graphqlbackend.DetermineStatusForLogs(alert, progress.Stats, err)
 
@@ -206,6 +248,16 @@ type args struct {
 	Display            int
 	EnableChunkMatches bool
 
+	// Paginate is true if the final progress event should include the
+	// cursor of the next page of results. Cursor is the cursor of the page
+	// to return, which is the first page if empty.
+	Paginate bool
+	Cursor   string
+
+	// Explain is true if the planned job tree of the search and its
+	// estimated cost should be returned instead of results.
+	Explain bool
+
 	// Optional decoration parameters for server-side rendering a result set
 	// or subset. Decorations may specify, e.g., highlighting results with
 	// HTML markup up-front, and/or including context lines around file results.
@@ -245,6 +297,19 @@ func parseURLQuery(q url.Values) (*args, error) {
 		return nil, errors.Errorf("chunk matches must be parseable as a boolean, got %q: %w", chunkMatches, err)
 	}
 
+	a.Cursor = get("cursor", "")
+	paginate := get("paginate", "f")
+	if a.Paginate, err = strconv.ParseBool(paginate); err != nil {
+		return nil, errors.Errorf("paginate must be parseable as a boolean, got %q: %w", paginate, err)
+	}
+	// Passing a cursor implies pagination.
+	a.Paginate = a.Paginate || a.Cursor != ""
+
+	explain := get("explain", "f")
+	if a.Explain, err = strconv.ParseBool(explain); err != nil {
+		return nil, errors.Errorf("explain must be parseable as a boolean, got %q: %w", explain, err)
+	}
+
 	decorationLimit := get("dl", "0")
 	if a.DecorationLimit, err = strconv.Atoi(decorationLimit); err != nil {
 		return nil, errors.Errorf("decorationLimit must be an integer, got %q: %w", decorationLimit, err)
@@ -258,6 +323,12 @@ func parseURLQuery(q url.Values) (*args, error) {
 	return &a, nil
 }
 
+// cursorQuery identifies the search of a, so that cursors of other searches
+// are rejected.
+func (a *args) cursorQuery() string {
+	return a.Version + "\x00" + a.PatternType + "\x00" + a.Query
+}
+
 func strPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -294,6 +365,12 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo,
 		return fromRepository(v, repoCache)
 	case *result.CommitMatch:
 		return fromCommit(v, repoCache)
+	case *result.CommitDiffMatch:
+		return fromCommit(v.ToCommitMatch(), repoCache)
+	case *result.PersonMatch:
+		return fromPerson(v)
+	case *result.CaptureGroupMatch:
+		return fromCaptureGroup(v)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -506,6 +583,27 @@ func fromCommit(commit *result.CommitMatch, repoCache map[api.RepoID]*types.Sear
 	return commitEvent
 }
 
+func fromPerson(person *result.PersonMatch) *streamhttp.EventPersonMatch {
+	return &streamhttp.EventPersonMatch{
+		Type:         streamhttp.PersonMatchType,
+		Name:         person.Name,
+		Email:        person.Email,
+		Handle:       person.Handle,
+		Repository:   string(person.Repo.Name),
+		RepositoryID: int32(person.Repo.ID),
+	}
+}
+
+func fromCaptureGroup(cm *result.CaptureGroupMatch) *streamhttp.EventCaptureGroupMatch {
+	return &streamhttp.EventCaptureGroupMatch{
+		Type:         streamhttp.CaptureGroupMatchType,
+		Value:        cm.Value,
+		Count:        cm.Count,
+		Repository:   string(cm.Repo.Name),
+		RepositoryID: int32(cm.Repo.ID),
+	}
+}
+
 // eventStreamOTHook returns a StatHook which logs to log.
 func eventStreamOTHook(log func(...otlog.Field)) func(streamhttp.WriterStat) {
 	return func(stat streamhttp.WriterStat) {
@@ -128,6 +128,60 @@ func TestServeStream_chunkMatches(t *testing.T) {
 	require.Len(t, chunkMatches[0].Ranges, 1)
 }
 
+func TestServeStream_explain(t *testing.T) {
+	graphqlbackend.MockDecodedViewerFinalSettings = &schema.Settings{}
+	t.Cleanup(func() { graphqlbackend.MockDecodedViewerFinalSettings = nil })
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultHook(func(_ context.Context, _ string, _ *string, queryString string, _ search.Protocol, _ *schema.Settings, _ bool) (*search.Inputs, error) {
+		plan, err := query.Pipeline(query.InitLiteral(queryString))
+		require.NoError(t, err)
+		return &search.Inputs{
+			Plan:         plan,
+			Query:        plan.ToQ(),
+			UserSettings: &schema.Settings{},
+			PatternType:  query.SearchTypeLiteral,
+			Protocol:     search.Streaming,
+			Features:     &search.Features{},
+		}, nil
+	})
+
+	ts := httptest.NewServer(&streamHandler{
+		logger:              logtest.Scoped(t),
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		searchClient:        mock,
+	})
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?q=type:repo+foo&explain=t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var explain *streamhttp.EventExplain
+	decoder := streamhttp.FrontendStreamDecoder{
+		OnExplain: func(ev *streamhttp.EventExplain) {
+			explain = ev
+		},
+	}
+	err = decoder.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 200 {
+		t.Errorf("expected status 200, got %d", res.StatusCode)
+	}
+
+	// The search is explained instead of run.
+	require.Empty(t, mock.ExecuteFunc.History())
+	require.NotNil(t, explain)
+	require.Contains(t, string(explain.Plan), "RepoSearchJob")
+	// Repository searches do not search the repositories they resolve.
+	require.Empty(t, explain.Jobs)
+}
+
 func TestDisplayLimit(t *testing.T) {
 	cases := []struct {
 		queryString         string
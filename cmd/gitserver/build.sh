@@ -11,6 +11,7 @@ cleanup() {
 trap cleanup EXIT
 
 cp -a ./cmd/gitserver/p4-fusion-install-alpine.sh "$OUTPUT"
+cp -a ./cmd/gitserver/git-cinnabar-install-alpine.sh "$OUTPUT"
 
 # Environment for building linux binaries
 export GO111MODULE=on
@@ -0,0 +1,49 @@
+#!/bin/sh
+
+# This script installs git-cinnabar within an alpine container. git-cinnabar is
+# the git remote helper gitserver uses to convert Mercurial repositories.
+
+set -eu
+
+tmpdir=$(mktemp -d)
+cd "$tmpdir"
+
+cleanup() {
+  echo "--- cleanup"
+  apk --no-cache --purge del git-cinnabar-build-deps 2>/dev/null || true
+  cd /
+  rm -rf "$tmpdir" || true
+}
+
+trap cleanup EXIT
+
+set -x
+
+export GIT_CINNABAR_VERSION=0.6.0
+
+# Build dependencies
+echo "--- git-cinnabar apk build-deps"
+apk add --no-cache \
+  --virtual git-cinnabar-build-deps \
+  cargo \
+  curl-dev \
+  gcc \
+  git \
+  make \
+  musl-dev \
+  python3 \
+  zlib-dev
+
+# Build git-cinnabar
+echo "--- git-cinnabar build"
+cargo install --locked --root "$tmpdir" \
+  --git https://github.com/glandium/git-cinnabar --tag "$GIT_CINNABAR_VERSION"
+
+# git-cinnabar is also the remote helper for hg:: URLs, which git finds as
+# git-remote-hg.
+echo "--- git-cinnabar install"
+mv "$tmpdir"/bin/git-cinnabar /usr/local/bin/git-cinnabar
+ln -s git-cinnabar /usr/local/bin/git-remote-hg
+
+echo "--- git-cinnabar test"
+git cinnabar --version
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -40,8 +41,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/crates"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodproxy"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/npm"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/pypi"
@@ -80,6 +84,9 @@ var (
 func main() {
 	ctx := context.Background()
 
+	archiveConfig := &archiveStoreConfig{}
+	archiveConfig.Load()
+
 	env.Lock()
 	env.HandleHelpFlag()
 	logging.Init()
@@ -104,6 +111,10 @@ func main() {
 
 	logger := log.Scoped("server", "the gitserver service")
 
+	if err := archiveConfig.Validate(); err != nil {
+		logger.Fatal("failed to load archive store configuration", log.Error(err))
+	}
+
 	if reposDir == "" {
 		logger.Fatal("SRC_REPOS_DIR is required")
 	}
@@ -146,6 +157,12 @@ func main() {
 		GetVCSSyncer: func(ctx context.Context, repo api.RepoName) (server.VCSSyncer, error) {
 			return getVCSSyncer(ctx, externalServiceStore, repoStore, depsSvc, repo)
 		},
+		GetLFSConfig: func(ctx context.Context, repo api.RepoName) (*server.LFSConfig, error) {
+			return getLFSConfig(ctx, externalServiceStore, repoStore, repo)
+		},
+		ForkNetwork: func(ctx context.Context, repo api.RepoName) (string, error) {
+			return forkNetwork(ctx, repoStore, repo)
+		},
 		Hostname:                hostname.Get(),
 		DB:                      db,
 		CloneQueue:              server.NewCloneQueue(list.New()),
@@ -159,6 +176,11 @@ func main() {
 	}
 	gitserver.RegisterMetrics(db, observationContext)
 
+	gitserver.ArchiveStore, err = newArchiveStore(ctx, archiveConfig, observationContext)
+	if err != nil {
+		logger.Fatal("failed to create archive store", log.Error(err))
+	}
+
 	if tmpDir, err := gitserver.SetupAndClearTmp(); err != nil {
 		logger.Fatal("failed to setup temporary directory", log.Error(err))
 	} else if err := os.Setenv("TMP_DIR", tmpDir); err != nil {
@@ -512,10 +534,147 @@ func getVCSSyncer(
 		}
 		cli := crates.NewClient(urn, httpcli.ExternalDoer)
 		return server.NewRustPackagesSyncer(&c, depsSvc, cli), nil
+	case extsvc.TypeSubversion:
+		var c schema.SubversionConnection
+		if _, err := extractOptions(&c); err != nil {
+			return nil, err
+		}
+		return server.NewSubversionSyncer(&c), nil
+	case extsvc.TypeMercurial:
+		return &server.MercurialSyncer{}, nil
 	}
 	return &server.GitRepoSyncer{}, nil
 }
 
+// defaultLFSMaxObjectSize is the default of the gitLFS.maxObjectSizeBytes
+// external service option.
+const defaultLFSMaxObjectSize = 50 * 1024 * 1024
+
+// getLFSConfig returns the Git LFS config of the first external service repo
+// belongs to which enables gitLFS. It returns nil if none of them do.
+func getLFSConfig(
+	ctx context.Context,
+	externalServiceStore database.ExternalServiceStore,
+	repoStore database.RepoStore,
+	repo api.RepoName,
+) (*server.LFSConfig, error) {
+	// Like getVCSSyncer we only read the code host configuration, so using an
+	// internal actor is safe.
+	r, err := repoStore.GetByName(actor.WithInternalActor(ctx), repo)
+	if err != nil {
+		return nil, errors.Wrap(err, "get repository")
+	}
+
+	for _, info := range r.Sources {
+		extSvc, err := externalServiceStore.GetByID(ctx, info.ExternalServiceID())
+		if err != nil {
+			return nil, errors.Wrap(err, "get external service")
+		}
+
+		switch extSvc.Kind {
+		case extsvc.KindGitHub, extsvc.KindGitLab, extsvc.KindOther:
+		default:
+			continue
+		}
+
+		rawConfig, err := extSvc.Config.Decrypt(ctx)
+		if err != nil {
+			return nil, err
+		}
+		normalized, err := jsonc.Parse(rawConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "normalize JSON")
+		}
+
+		var lfs struct {
+			Enabled            bool
+			MaxObjectSizeBytes *int
+		}
+		switch extSvc.Kind {
+		case extsvc.KindGitHub:
+			var c schema.GitHubConnection
+			if err := jsoniter.Unmarshal(normalized, &c); err != nil {
+				return nil, errors.Wrap(err, "unmarshal JSON")
+			}
+			if c.GitLFS != nil {
+				lfs.Enabled, lfs.MaxObjectSizeBytes = c.GitLFS.Enabled, c.GitLFS.MaxObjectSizeBytes
+			}
+		case extsvc.KindGitLab:
+			var c schema.GitLabConnection
+			if err := jsoniter.Unmarshal(normalized, &c); err != nil {
+				return nil, errors.Wrap(err, "unmarshal JSON")
+			}
+			if c.GitLFS != nil {
+				lfs.Enabled, lfs.MaxObjectSizeBytes = c.GitLFS.Enabled, c.GitLFS.MaxObjectSizeBytes
+			}
+		case extsvc.KindOther:
+			var c schema.OtherExternalServiceConnection
+			if err := jsoniter.Unmarshal(normalized, &c); err != nil {
+				return nil, errors.Wrap(err, "unmarshal JSON")
+			}
+			if c.GitLFS != nil {
+				lfs.Enabled, lfs.MaxObjectSizeBytes = c.GitLFS.Enabled, c.GitLFS.MaxObjectSizeBytes
+			}
+		}
+
+		if !lfs.Enabled {
+			continue
+		}
+		maxObjectSize := int64(defaultLFSMaxObjectSize)
+		if lfs.MaxObjectSizeBytes != nil {
+			maxObjectSize = int64(*lfs.MaxObjectSizeBytes)
+		}
+		return &server.LFSConfig{MaxObjectSize: maxObjectSize}, nil
+	}
+	return nil, nil
+}
+
+// forkNetwork returns the identity of the fork network of repo on its code
+// host: the ID of the repository a fork was forked from, or the ID of a
+// repository which has forks. Forks of forks therefore don't share the object
+// pool of the upstream. Bitbucket only reports the former, so the upstream of
+// a Bitbucket fork does not share the object pool of its forks.
+//
+// Members of an object pool can read each other's objects, so only public
+// repositories are pooled. An empty string is returned for all others.
+func forkNetwork(ctx context.Context, repoStore database.RepoStore, repo api.RepoName) (string, error) {
+	r, err := repoStore.GetByName(actor.WithInternalActor(ctx), repo)
+	if err != nil {
+		return "", errors.Wrap(err, "get repository")
+	}
+	if r.Private {
+		return "", nil
+	}
+
+	var id string
+	switch m := r.Metadata.(type) {
+	case *github.Repository:
+		if m.Parent != nil {
+			id = m.Parent.ID
+		} else if m.ForkCount > 0 {
+			id = m.ID
+		}
+	case *gitlab.Project:
+		if m.ForkedFromProject != nil {
+			id = strconv.Itoa(m.ForkedFromProject.ID)
+		} else if m.ForksCount > 0 {
+			id = strconv.Itoa(m.ID)
+		}
+	case *bitbucketserver.Repo:
+		if m.Origin != nil {
+			id = strconv.Itoa(m.Origin.ID)
+		}
+	case *bitbucketcloud.Repo:
+		if m.Parent != nil {
+			id = m.Parent.UUID
+		}
+	}
+	if id == "" {
+		return "", nil
+	}
+	return r.ExternalRepo.ServiceType + ":" + r.ExternalRepo.ServiceID + ":" + id, nil
+}
+
 func syncSiteLevelExternalServiceRateLimiters(ctx context.Context, store database.ExternalServiceStore) error {
 	svcs, err := store.List(ctx, database.ExternalServicesListOptions{NoNamespace: true})
 	if err != nil {
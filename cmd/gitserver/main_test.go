@@ -182,3 +182,51 @@ func TestGetVCSSyncer(t *testing.T) {
 		t.Fatalf("Want *server.PerforceDepotSyncer, got %T", s)
 	}
 }
+
+func TestForkNetwork(t *testing.T) {
+	upstream := &github.Repository{ID: "upstream", ForkCount: 1}
+	fork := &github.Repository{ID: "fork", IsFork: true, Parent: &github.ParentRepository{ID: "upstream"}}
+
+	for _, tc := range []struct {
+		name     string
+		private  bool
+		metadata any
+		want     string
+	}{
+		{
+			name:     "upstream",
+			metadata: upstream,
+			want:     "github:https://github.com/:upstream",
+		},
+		{
+			name:     "fork",
+			metadata: fork,
+			want:     "github:https://github.com/:upstream",
+		},
+		{
+			name:     "unrelated",
+			metadata: &github.Repository{ID: "other"},
+		},
+		{
+			name:     "private fork",
+			private:  true,
+			metadata: fork,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repoStore := database.NewMockRepoStore()
+			repoStore.GetByNameFunc.SetDefaultReturn(&types.Repo{
+				Private: tc.private,
+				ExternalRepo: api.ExternalRepoSpec{
+					ServiceType: extsvc.TypeGitHub,
+					ServiceID:   "https://github.com/",
+				},
+				Metadata: tc.metadata,
+			}, nil)
+
+			got, err := forkNetwork(context.Background(), repoStore, "github.com/foo/bar")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
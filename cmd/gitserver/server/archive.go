@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Archived repositories are stored as a git bundle in Server.ArchiveStore
+// instead of on disk. The directory of an archived repository only contains
+// a stub which records where its bundle is stored. The next clone of an
+// archived repository restores it from its bundle and then fetches what
+// changed since it was archived, instead of cloning it from the code host.
+//
+// Repositories are archived by the janitor when they have not been used for
+// SRC_ARCHIVE_IDLE_AFTER, and instead of being removed when disk space is
+// low. Only repositories cloned with GitRepoSyncer are archived, since the
+// bundle doesn't contain the state other syncers need to fetch.
+//
+// Scheduled updates don't restore archived repositories, only clones
+// triggered by accessing them do.
+
+var (
+	archiveIdleAfter  = env.MustGetDuration("SRC_ARCHIVE_IDLE_AFTER", 0, "the time after which repositories which have not been used are archived, if an archive store is configured. Zero archives repositories only when disk space is low.")
+	archiveLimit, _   = strconv.Atoi(env.Get("SRC_ARCHIVE_LIMIT", "10", "the maximum number of idle repos we archive in one janitor run"))
+	archiveStagingDir = env.Get("SRC_ARCHIVE_STAGING_DIR", "", "a directory on another disk than SRC_REPOS_DIR in which bundles are created when repositories are archived because disk space is low. If unset, such repositories are removed instead.")
+)
+
+var (
+	reposArchived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_archived",
+		Help: "number of repos archived to the archive store",
+	})
+	reposRestored = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_restored",
+		Help: "number of archived repos restored from the archive store",
+	})
+)
+
+// archiveStubName is the name of the stub of an archived repository. It is
+// stored next to the .git directory of the repository.
+const archiveStubName = ".sourcegraph-archive"
+
+// lastAccessName is the name of the file in the .git directory of a
+// repository whose modification time is the time the repository was last
+// used.
+const lastAccessName = "sg_last_access"
+
+// lastAccessResolution is how often we update the last access time of a
+// repository which is used continuously.
+const lastAccessResolution = time.Minute
+
+// archiveStub describes the bundle of an archived repository.
+type archiveStub struct {
+	// Key is the key of the bundle in the archive store.
+	Key        string
+	ArchivedAt time.Time
+}
+
+func archiveStubPath(dir GitDir) string {
+	return filepath.Join(filepath.Dir(string(dir)), archiveStubName)
+}
+
+// archiveKey returns the key of the bundle of repo in the archive store.
+func archiveKey(repo api.RepoName) string {
+	sum := sha256.Sum256([]byte(repo))
+	return "repos/" + hex.EncodeToString(sum[:]) + ".bundle"
+}
+
+// readArchiveStub returns the stub of the repository in dir, if it is
+// archived.
+func readArchiveStub(dir GitDir) (*archiveStub, bool) {
+	b, err := os.ReadFile(archiveStubPath(dir))
+	if err != nil {
+		return nil, false
+	}
+	var stub archiveStub
+	if err := json.Unmarshal(b, &stub); err != nil {
+		return nil, false
+	}
+	return &stub, true
+}
+
+// repoArchived returns true if the repository in dir is archived and not
+// cloned.
+func repoArchived(dir GitDir) bool {
+	if repoCloned(dir) {
+		return false
+	}
+	_, ok := readArchiveStub(dir)
+	return ok
+}
+
+// touchLastAccess records that the repository in dir was used. Errors are
+// ignored, since the repository may not be cloned.
+func touchLastAccess(dir GitDir) {
+	path := dir.Path(lastAccessName)
+	fi, err := os.Stat(path)
+	if err == nil && time.Since(fi.ModTime()) < lastAccessResolution {
+		return
+	}
+	if os.IsNotExist(err) {
+		_ = os.WriteFile(path, nil, 0600)
+		return
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// repoLastAccess returns the time the repository in dir was last used. It
+// returns false if no access has been recorded since the repository was
+// cloned.
+func repoLastAccess(dir GitDir) (time.Time, bool) {
+	fi, err := os.Stat(dir.Path(lastAccessName))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return fi.ModTime(), true
+}
+
+// archiveRepo uploads a bundle of the repository in dir to the archive store
+// and replaces the repository with a stub. The bundle is created in
+// stagingDir, or in a temporary directory in ReposDir if stagingDir is empty.
+func (s *Server) archiveRepo(ctx context.Context, dir GitDir, stagingDir string) error {
+	if s.ArchiveStore == nil {
+		return errors.New("no archive store configured")
+	}
+	repo := s.name(dir)
+
+	// Other syncers keep state outside of the refs of the repository, such as
+	// the git-svn metadata, which a restored repository would lack.
+	syncer, err := s.GetVCSSyncer(ctx, repo)
+	if err != nil {
+		return errors.Wrap(err, "get VCS syncer")
+	}
+	if _, ok := syncer.(*GitRepoSyncer); !ok {
+		return errors.Newf("repositories of type %q are not archived", syncer.Type())
+	}
+
+	// The lock prevents clones of the repository while we archive it.
+	lock, ok := s.locker.TryAcquire(dir, "archiving")
+	if !ok {
+		return errors.New("repository is locked")
+	}
+	defer lock.Release()
+
+	var tmp string
+	if stagingDir != "" {
+		tmp, err = os.MkdirTemp(stagingDir, "archive-")
+	} else {
+		tmp, err = s.tempDir("archive-")
+	}
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	bundle := filepath.Join(tmp, "repo.bundle")
+	cmd := exec.CommandContext(ctx, "git", "bundle", "create", bundle, "--all")
+	dir.Set(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to create bundle with output %q", string(out))
+	}
+
+	f, err := os.Open(bundle)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	key := archiveKey(repo)
+	if _, err := s.ArchiveStore.Upload(ctx, key, f); err != nil {
+		return errors.Wrap(err, "upload bundle")
+	}
+
+	b, err := json.Marshal(archiveStub{Key: key, ArchivedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	// The stub is written next to its destination, since the staging
+	// directory may be on another disk.
+	stubTmp := archiveStubPath(dir) + ".tmp"
+	if err := os.WriteFile(stubTmp, b, 0600); err != nil {
+		return err
+	}
+	if err := fileutil.RenameAndSync(stubTmp, archiveStubPath(dir)); err != nil {
+		return errors.Wrap(err, "write archive stub")
+	}
+
+	if err := s.removeRepoDirectory(dir, true); err != nil {
+		return errors.Wrap(err, "remove archived repository")
+	}
+	reposArchived.Inc()
+	return nil
+}
+
+// restoreArchive restores the bundle of an archived repository into the bare
+// repository tmp and fetches what changed since it was archived.
+func (s *Server) restoreArchive(ctx context.Context, stub *archiveStub, tmp GitDir, syncer VCSSyncer, remoteURL *vcs.URL) error {
+	if s.ArchiveStore == nil {
+		return errors.New("no archive store configured")
+	}
+
+	bundleDir, err := s.tempDir("restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(bundleDir)
+
+	rc, err := s.ArchiveStore.Get(ctx, stub.Key)
+	if err != nil {
+		return errors.Wrap(err, "get bundle")
+	}
+	defer rc.Close()
+	bundle := filepath.Join(bundleDir, "repo.bundle")
+	f, err := os.Create(bundle)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, rc)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "download bundle")
+	}
+
+	if err := os.MkdirAll(string(tmp), os.ModePerm); err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	tmp.Set(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to init repository with output %q", string(out))
+	}
+	cmd = exec.CommandContext(ctx, "git", "fetch", "--no-auto-gc", "--quiet", bundle, "+refs/*:refs/*")
+	tmp.Set(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to fetch bundle with output %q", string(out))
+	}
+
+	if err := syncer.Fetch(ctx, remoteURL, tmp, ""); err != nil {
+		return errors.Wrap(err, "fetch")
+	}
+	reposRestored.Inc()
+	return nil
+}
+
+// removeArchive removes the stub of the repository in dir and its bundle.
+func (s *Server) removeArchive(ctx context.Context, dir GitDir) {
+	stub, ok := readArchiveStub(dir)
+	if !ok {
+		return
+	}
+	if err := os.Remove(archiveStubPath(dir)); err != nil && !os.IsNotExist(err) {
+		s.Logger.Warn("failed to remove archive stub", log.String("dir", string(dir)), log.Error(err))
+		return
+	}
+	// The stub may have been the last entry of the repository directory.
+	_ = os.Remove(filepath.Dir(archiveStubPath(dir)))
+
+	if s.ArchiveStore == nil {
+		return
+	}
+	if err := s.ArchiveStore.Delete(ctx, stub.Key); err != nil {
+		s.Logger.Warn("failed to delete archived bundle", log.String("dir", string(dir)), log.Error(err))
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestArchiveRepo(t *testing.T) {
+	ctx := context.Background()
+	remote := t.TempDir()
+	repoName := api.RepoName("example.com/foo/bar")
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	makeSingleCommitRepo(cmd)
+
+	reposDir := t.TempDir()
+	s := makeTestServer(ctx, t, reposDir, remote, nil)
+
+	objects := map[string][]byte{}
+	store := mocks.NewMockStore()
+	store.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		b, err := io.ReadAll(r)
+		objects[key] = b
+		return int64(len(b)), err
+	})
+	store.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
+		b, ok := objects[key]
+		if !ok {
+			return nil, errors.Newf("no object %q", key)
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	})
+	store.DeleteFunc.SetDefaultHook(func(ctx context.Context, key string) error {
+		delete(objects, key)
+		return nil
+	})
+	s.ArchiveStore = store
+
+	if _, err := s.cloneRepo(ctx, repoName, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := s.dir(repoName)
+	if err := s.archiveRepo(ctx, dir, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(string(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected repository to be removed, got %v", err)
+	}
+	if _, ok := objects[archiveKey(repoName)]; !ok {
+		t.Fatal("expected bundle to be uploaded")
+	}
+	if progress := s.repoCloneProgress(repoName); progress.Cloned || !progress.Archived {
+		t.Fatalf("expected repository to be archived, got %+v", progress)
+	}
+
+	// Scheduled updates don't restore the repository.
+	body, err := json.Marshal(protocol.RepoUpdateRequest{Repo: repoName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	s.handleRepoUpdate(rr, httptest.NewRequest("POST", "/repo-update", bytes.NewReader(body)))
+	var resp protocol.RepoUpdateResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Archived || resp.Error != "" {
+		t.Fatalf("expected update of archived repository to be skipped, got %+v", resp)
+	}
+	if len(store.GetFunc.History()) != 0 {
+		t.Fatal("expected scheduled update not to download the bundle")
+	}
+	if progress := s.repoCloneProgress(repoName); progress.Cloned || !progress.Archived {
+		t.Fatalf("expected repository to stay archived, got %+v", progress)
+	}
+
+	// The clone restores the repository from its bundle and fetches the
+	// commits added since it was archived.
+	cmd("sh", "-c", "echo hello again > hello.txt")
+	wantCommit := addCommitToRepo(cmd)
+
+	if _, err := s.cloneRepo(ctx, repoName, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.GetFunc.History()) != 1 {
+		t.Fatal("expected repository to be restored from its bundle")
+	}
+	if got := runCmd(t, filepath.Dir(string(dir)), "git", "rev-parse", "HEAD"); got != wantCommit {
+		t.Fatalf("got HEAD %s, want %s", got, wantCommit)
+	}
+	if progress := s.repoCloneProgress(repoName); !progress.Cloned || progress.Archived {
+		t.Fatalf("expected repository to be cloned, got %+v", progress)
+	}
+	if _, err := os.Stat(archiveStubPath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected archive stub to be removed, got %v", err)
+	}
+	if len(objects) != 0 {
+		t.Fatalf("expected bundle to be deleted, got %d objects", len(objects))
+	}
+}
+
+func TestRepoLastAccess(t *testing.T) {
+	dir := GitDir(t.TempDir())
+
+	if _, ok := repoLastAccess(dir); ok {
+		t.Fatal("expected no recorded access")
+	}
+
+	touchLastAccess(dir)
+	if _, ok := repoLastAccess(dir); !ok {
+		t.Fatal("expected recorded access")
+	}
+
+	// Accesses after lastAccessResolution update the last access time.
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(dir.Path(lastAccessName), old, old); err != nil {
+		t.Fatal(err)
+	}
+	touchLastAccess(dir)
+	if got, _ := repoLastAccess(dir); !got.After(old) {
+		t.Fatalf("expected last access to be updated, got %s", got)
+	}
+}
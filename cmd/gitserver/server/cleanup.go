@@ -348,10 +348,10 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 			}
 		}
 
-		// We believe converting a Perforce depot to a Git repository is generally a
-		// very expensive operation, therefore we do not try to re-clone/redo the
-		// conversion only because it is old or slow to do "git gc".
-		if repoType == "perforce" && reason != maybeCorrupt {
+		// We believe converting a Perforce depot or a Subversion or Mercurial repository
+		// to a Git repository is generally a very expensive operation, therefore we do not
+		// try to re-clone/redo the conversion only because it is old or slow to do "git gc".
+		if (repoType == "perforce" || repoType == "subversion" || repoType == "mercurial") && reason != maybeCorrupt {
 			reason = ""
 		}
 
@@ -450,11 +450,64 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		return false, pruneIfNeeded(dir, looseObjectsLimit)
 	}
 
+	var objectPoolMigrations int
+	maybeMoveToObjectPool := func(dir GitDir) (done bool, err error) {
+		if objectPoolMigrations >= objectPoolMigrateLimit {
+			return false, nil
+		}
+		if _, ok := s.objectPoolOf(dir); ok {
+			return false, nil
+		}
+		name := s.name(dir)
+		key, ok := s.forkNetwork(bCtx, name)
+		if !ok {
+			return false, nil
+		}
+		objectPoolMigrations++
+		return false, s.joinObjectPool(bCtx, name, dir, key)
+	}
+
+	var reposArchivedThisRun int
+	maybeArchive := func(dir GitDir) (done bool, err error) {
+		if reposArchivedThisRun >= archiveLimit {
+			return false, nil
+		}
+		lastUsed, ok := repoLastAccess(dir)
+		if !ok {
+			// Repositories without a recorded access are idle from now on.
+			touchLastAccess(dir)
+			return false, nil
+		}
+		if time.Since(lastUsed) < archiveIdleAfter {
+			return false, nil
+		}
+		if syncer, err := s.GetVCSSyncer(bCtx, s.name(dir)); err != nil {
+			return false, err
+		} else if _, ok := syncer.(*GitRepoSyncer); !ok {
+			return false, nil
+		}
+		reposArchivedThisRun++
+		if err := s.archiveRepo(bCtx, dir, ""); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
 	type cleanupFn struct {
 		Name string
 		Do   func(GitDir) (bool, error)
 	}
+	var lfsBytes int64
+	evictLFS := func(dir GitDir) (done bool, err error) {
+		size, err := evictLFSObjects(dir, lfsCacheMaxSize)
+		lfsBytes += size
+		return false, err
+	}
+
 	cleanups := []cleanupFn{
+		// Evict LFS objects beyond the budget of the repo before its size is
+		// computed. They are fetched again when they are read.
+		{"evict LFS objects", evictLFS},
 		// Compute the amount of space used by the repo
 		{"compute stats and delete wrong shard repos", collectSizeAndMaybeDeleteWrongShardRepos},
 		// Do some sanity checks on the repository.
@@ -476,6 +529,18 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		{"auto gc config", ensureAutoGC},
 	}
 
+	if s.ArchiveStore != nil && archiveIdleAfter > 0 {
+		// Idle repositories are archived before we spend any time on their
+		// maintenance.
+		cleanups = append(cleanups, cleanupFn{"maybe archive idle repo", maybeArchive})
+	}
+
+	if enableObjectPools {
+		// Existing clones of forks and their upstreams only join an object pool
+		// when they are cloned, so we move a limited number of them per run.
+		cleanups = append(cleanups, cleanupFn{"maybe move to object pool", maybeMoveToObjectPool})
+	}
+
 	if gitGCMode == gitGCModeJanitorAutoGC {
 		// Runs a number of housekeeping tasks within the current repository, such as
 		// compressing file revisions (to reduce disk space and increase performance),
@@ -541,6 +606,11 @@ func (s *Server) cleanupRepos(gitServerAddrs gitserver.GitServerAddresses) {
 		logger.Error("error iterating over repositories", log.Error(err))
 	}
 
+	// Object pools are not repositories, but their members depend on them.
+	stats.GitDirBytes += s.cleanupObjectPools(bCtx)
+
+	lfsCacheSizeBytes.Set(float64(lfsBytes))
+
 	if b, err := json.Marshal(stats); err != nil {
 		logger.Error("failed to marshal periodic stats", log.Error(err))
 	} else if err = os.WriteFile(filepath.Join(s.ReposDir, reposStatsName), b, 0666); err != nil {
@@ -727,8 +797,21 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 			return nil
 		}
 		delta := dirSize(d.Path("."))
-		if err := s.removeRepoDirectory(d, true); err != nil {
-			return errors.Wrap(err, "removing repo directory")
+		archived := false
+		if s.ArchiveStore != nil && archiveStagingDir != "" {
+			// Archived repositories are restored without cloning them from
+			// the code host again. The bundle is staged on another disk,
+			// since this one is full.
+			if err := s.archiveRepo(context.Background(), d, archiveStagingDir); err != nil {
+				logger.Warn("failed to archive repo, removing it instead", log.String("repo", string(d)), log.Error(err))
+			} else {
+				archived = true
+			}
+		}
+		if !archived {
+			if err := s.removeRepoDirectory(d, true); err != nil {
+				return errors.Wrap(err, "removing repo directory")
+			}
 		}
 		spaceFreed += delta
 		reposRemovedDiskPressure.Inc()
@@ -1260,7 +1343,8 @@ func needsMaintenance(dir GitDir) (bool, string, error) {
 	if err != nil {
 		return false, "", err
 	}
-	if !hasBm {
+	// Git does not write bitmaps for repositories with alternates.
+	if !hasBm && !hasAlternates(dir) {
 		return true, "bitmap", nil
 	}
 
@@ -1333,6 +1417,11 @@ func hasBitmap(dir GitDir) (bool, error) {
 	return len(bitmaps) > 0, nil
 }
 
+func hasAlternates(dir GitDir) bool {
+	_, err := os.Stat(dir.Path("objects", "info", "alternates"))
+	return err == nil
+}
+
 func hasCommitGraph(dir GitDir) (bool, error) {
 	if _, err := os.Stat(dir.Path("objects", "info", "commit-graph")); err == nil {
 		return true, nil
@@ -95,6 +95,10 @@ func (s *Server) gitServiceHandler() *gitservice.Handler {
 				"protocol": protocol,
 			})
 
+			// Fetches are uses of the repository, which keep it from being
+			// archived.
+			touchLastAccess(s.dir(api.RepoName(repo)))
+
 			return func(err error) {
 				errLabel := strconv.FormatBool(err != nil)
 				metricServiceRunning.WithLabelValues(svc).Dec()
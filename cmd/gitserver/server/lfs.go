@@ -0,0 +1,888 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// LFSConfig configures how gitserver resolves Git LFS pointer files of a
+// repository.
+//
+// We clone with GIT_LFS_SKIP_SMUDGE=1, so the blobs of files tracked by LFS
+// are pointer files. If LFS is enabled for a repository, the output of "git
+// show <commit>:<path>" and "git archive" is rewritten to contain the content
+// of the LFS objects instead. Objects are fetched from the LFS server of the
+// repository on first use and cached inside the GIT_DIR.
+type LFSConfig struct {
+	// MaxObjectSize is the size in bytes of the largest object we fetch.
+	// Pointers to larger objects are returned as is.
+	MaxObjectSize int64
+}
+
+// lfsPointerMaxSize is the size of the largest pointer file. git-lfs does not
+// treat larger files as pointers.
+const lfsPointerMaxSize = 1024
+
+// lfsCacheMaxSize is the budget in bytes for the cached LFS objects of a
+// repository. The least recently used objects are evicted once it is
+// exceeded.
+var lfsCacheMaxSize = int64(env.MustGetInt("SRC_LFS_CACHE_MAX_SIZE_MB", 1024, "the maximum size in MB of the Git LFS objects cached for a repository")) * 1024 * 1024
+
+// lfsBatchSize is the maximum number of objects we request in one LFS batch
+// request, which is the batch size git-lfs uses.
+const lfsBatchSize = 100
+
+// lfsConfigTTL is how long we cache the result of GetLFSConfig. It is
+// consulted for every "git show", so we avoid speaking to the database each
+// time.
+const lfsConfigTTL = time.Minute
+
+// lfsPointer is a parsed Git LFS pointer file. See
+// https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md
+type lfsPointer struct {
+	// OID is the hex encoded sha256 of the object.
+	OID  string
+	Size int64
+}
+
+// parseLFSPointer returns the pointer in data. ok is false if data is not a
+// valid pointer file.
+func parseLFSPointer(data []byte) (p lfsPointer, ok bool) {
+	if len(data) > lfsPointerMaxSize || !bytes.HasPrefix(data, []byte("version ")) {
+		return p, false
+	}
+
+	var version string
+	var hasSize bool
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		key, value, found := strings.Cut(line, " ")
+		if !found {
+			return p, false
+		}
+		switch key {
+		case "version":
+			version = value
+		case "oid":
+			if !strings.HasPrefix(value, "sha256:") {
+				return p, false
+			}
+			oid := strings.TrimPrefix(value, "sha256:")
+			if len(oid) != sha256.Size*2 {
+				return p, false
+			}
+			if _, err := hex.DecodeString(oid); err != nil {
+				return p, false
+			}
+			p.OID = oid
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return p, false
+			}
+			p.Size = size
+			hasSize = true
+		}
+	}
+
+	if version != "https://git-lfs.github.com/spec/v1" && version != "https://hawser.github.com/spec/v1" {
+		return p, false
+	}
+	return p, p.OID != "" && hasSize
+}
+
+// lfsStore fetches and caches the LFS objects of a repository.
+type lfsStore struct {
+	logger log.Logger
+	dir    GitDir
+	config LFSConfig
+	doer   httpcli.Doer
+
+	// cacheMaxSize is the budget in bytes for the objects cached in dir.
+	cacheMaxSize int64
+
+	// tmpDir is where archives are spooled while their pointers are
+	// resolved. The default temporary directory is used if empty.
+	tmpDir string
+
+	// remoteURL returns the remote URL of the repository. The LFS endpoint
+	// is derived from it.
+	remoteURL func(context.Context) (*vcs.URL, error)
+}
+
+// objectPath is where the object oid is cached. It is the same layout
+// git-lfs uses.
+func (s *lfsStore) objectPath(oid string) string {
+	return s.dir.Path("lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+// resolve returns the content of the object data points to. If data is not a
+// pointer or the object is larger than MaxObjectSize, ok is false. Objects
+// which are not cached are only fetched if fetch is true.
+func (s *lfsStore) resolve(ctx context.Context, data []byte, fetch bool) (rc io.ReadCloser, size int64, ok bool, err error) {
+	p, ok := s.pointer(data)
+	if !ok {
+		return nil, 0, false, nil
+	}
+
+	path := s.objectPath(p.OID)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && fetch {
+		if err := s.fetch(ctx, []lfsPointer{p}); err != nil {
+			return nil, 0, false, err
+		}
+		f, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	// The modification time of cached objects is the time they were last
+	// used, which decides which objects are evicted first.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return f, p.Size, true, nil
+}
+
+// pointer returns the pointer in data if it points to an object we resolve.
+func (s *lfsStore) pointer(data []byte) (lfsPointer, bool) {
+	p, ok := parseLFSPointer(data)
+	if !ok || p.Size > s.config.MaxObjectSize {
+		return p, false
+	}
+	return p, true
+}
+
+// resolveOrPointer is like resolve, but returns the pointer file if the object
+// can not be resolved. We prefer returning the pointer over failing the read.
+func (s *lfsStore) resolveOrPointer(ctx context.Context, data []byte, fetch bool) (io.ReadCloser, int64) {
+	rc, size, ok, err := s.resolve(ctx, data, fetch)
+	if err != nil && !os.IsNotExist(err) {
+		lfsFetchFailedCounter.Inc()
+		s.logger.Warn("failed to resolve LFS pointer", log.String("dir", string(s.dir)), log.Error(err))
+	}
+	if !ok || err != nil {
+		return io.NopCloser(bytes.NewReader(data)), int64(len(data))
+	}
+	return rc, size
+}
+
+type lfsBatchRequest struct {
+	Operation string           `json:"operation"`
+	Transfers []string         `json:"transfers"`
+	Objects   []lfsBatchObject `json:"objects"`
+}
+
+type lfsBatchObject struct {
+	OID     string `json:"oid"`
+	Size    int64  `json:"size"`
+	Actions *struct {
+		Download *struct {
+			Href   string            `json:"href"`
+			Header map[string]string `json:"header"`
+		} `json:"download"`
+	} `json:"actions,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type lfsBatchResponse struct {
+	Objects []lfsBatchObject `json:"objects"`
+}
+
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+// fetch downloads the objects the pointers point to via the LFS batch API and
+// stores them at objectPath. Once the objects are stored, the least recently
+// used objects are evicted if the cache exceeds its budget.
+func (s *lfsStore) fetch(ctx context.Context, pointers []lfsPointer) (err error) {
+	start := time.Now()
+	defer func() {
+		lfsFetchDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	remoteURL, err := s.remoteURL(ctx)
+	if err != nil {
+		return err
+	}
+	endpoint, user, err := lfsEndpoint(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	for len(pointers) > 0 {
+		batch := pointers
+		if len(batch) > lfsBatchSize {
+			batch = batch[:lfsBatchSize]
+		}
+		pointers = pointers[len(batch):]
+
+		if batchErr := s.fetchBatch(ctx, endpoint, user, batch); batchErr != nil {
+			err = errors.Append(err, batchErr)
+		}
+	}
+
+	if _, evictErr := evictLFSObjects(s.dir, s.cacheMaxSize); evictErr != nil {
+		err = errors.Append(err, errors.Wrap(evictErr, "evicting LFS objects"))
+	}
+	return err
+}
+
+// fetchBatch fetches the objects of pointers with a single LFS batch request.
+func (s *lfsStore) fetchBatch(ctx context.Context, endpoint *url.URL, user *url.Userinfo, pointers []lfsPointer) error {
+	objects := make([]lfsBatchObject, 0, len(pointers))
+	for _, p := range pointers {
+		objects = append(objects, lfsBatchObject{OID: p.OID, Size: p.Size})
+	}
+	body, err := json.Marshal(lfsBatchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   objects,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String()+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	if user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	resp, err := s.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "LFS batch request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("LFS batch request failed with status %d", resp.StatusCode)
+	}
+
+	var batch lfsBatchResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&batch); err != nil {
+		return errors.Wrap(err, "decoding LFS batch response")
+	}
+
+	returned := make(map[string]lfsBatchObject, len(batch.Objects))
+	for _, obj := range batch.Objects {
+		returned[obj.OID] = obj
+	}
+
+	var errs error
+	for _, p := range pointers {
+		obj, ok := returned[p.OID]
+		switch {
+		case !ok:
+			err = errors.Errorf("LFS object %s missing from batch response", p.OID)
+		case obj.Error != nil:
+			err = errors.Errorf("LFS object %s: %d %s", p.OID, obj.Error.Code, obj.Error.Message)
+		case obj.Actions == nil || obj.Actions.Download == nil:
+			err = errors.Errorf("LFS object %s has no download action", p.OID)
+		default:
+			err = s.download(ctx, endpoint, p, obj.Actions.Download.Href, obj.Actions.Download.Header)
+		}
+		if err != nil {
+			errs = errors.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// download writes the object at href to objectPath, verifying its size and
+// hash.
+func (s *lfsStore) download(ctx context.Context, endpoint *url.URL, p lfsPointer, href string, header map[string]string) error {
+	if err := checkLFSHref(ctx, endpoint, href); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "LFS download")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("LFS download of %s failed with status %d", p.OID, resp.StatusCode)
+	}
+
+	path := s.objectPath(p.OID)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	// We write to a temporary file next to the object so that concurrent
+	// readers never see a partially written object.
+	f, err := os.CreateTemp(filepath.Dir(path), p.OID+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, p.Size+1))
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return errors.Wrap(err, "writing LFS object")
+	}
+	if n != p.Size {
+		return errors.Errorf("LFS object %s has size %d, expected %d", p.OID, n, p.Size)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != p.OID {
+		return errors.Errorf("LFS object %s has hash %s", p.OID, got)
+	}
+
+	lfsFetchedBytes.Add(float64(n))
+	return os.Rename(f.Name(), path)
+}
+
+// checkLFSHref returns an error if we must not download an object from href.
+// The LFS server decides where objects are downloaded from, and they are often
+// stored on another host, such as a blob store. We only allow other hosts if
+// they are public, so that a LFS server can't make gitserver send requests to
+// internal services.
+func checkLFSHref(ctx context.Context, endpoint *url.URL, href string) error {
+	u, err := url.Parse(href)
+	if err != nil {
+		return errors.Wrap(err, "invalid LFS download URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("LFS download URL has unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == endpoint.Hostname() {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return errors.Wrap(err, "resolving LFS download host")
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return errors.Errorf("LFS download host %q resolves to the non-public address %s", u.Hostname(), addr.IP)
+		}
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified())
+}
+
+// evictLFSObjects removes the least recently used LFS objects cached in dir
+// until they take up at most maxSize bytes. It returns the size of the
+// remaining objects.
+func evictLFSObjects(dir GitDir, maxSize int64) (int64, error) {
+	type object struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var (
+		objects []object
+		size    int64
+	)
+	err := filepath.WalkDir(dir.Path("lfs", "objects"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		// Objects which are being downloaded are written to temporary files
+		// next to the object, which we leave alone.
+		if d.IsDir() || strings.Contains(d.Name(), ".tmp-") {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		objects = append(objects, object{path: path, size: fi.Size(), modTime: fi.ModTime()})
+		size += fi.Size()
+		return nil
+	})
+	if err != nil {
+		return size, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].modTime.Before(objects[j].modTime) })
+	for _, o := range objects {
+		if size <= maxSize {
+			break
+		}
+		if err := os.Remove(o.path); err != nil && !os.IsNotExist(err) {
+			return size, err
+		}
+		size -= o.size
+		lfsEvictedCounter.Inc()
+	}
+	return size, nil
+}
+
+// lfsEndpoint returns the LFS server URL of the repository at remoteURL, as
+// well as the credentials of remoteURL. It follows the default git-lfs
+// behaviour of appending "info/lfs" to the clone URL.
+func lfsEndpoint(remoteURL *vcs.URL) (*url.URL, *url.Userinfo, error) {
+	if remoteURL.Scheme != "http" && remoteURL.Scheme != "https" {
+		return nil, nil, errors.Errorf("LFS is only supported for HTTP(S) remotes, got %q", remoteURL.Scheme)
+	}
+
+	u := remoteURL.URL
+	user := u.User
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.Path += "/info/lfs"
+	u.RawPath = ""
+	return &u, user, nil
+}
+
+// lfsConfigCache caches the results of Server.GetLFSConfig. The zero value is
+// usable.
+type lfsConfigCache struct {
+	mu      sync.Mutex
+	entries map[api.RepoName]lfsConfigCacheEntry
+}
+
+type lfsConfigCacheEntry struct {
+	config  *LFSConfig
+	expires time.Time
+}
+
+// lfsConfig returns the LFS config of repo, or nil if LFS is not enabled for
+// repo.
+func (s *Server) lfsConfig(ctx context.Context, repo api.RepoName) *LFSConfig {
+	if s.GetLFSConfig == nil {
+		return nil
+	}
+
+	c := &s.lfsConfigs
+	c.mu.Lock()
+	e, ok := c.entries[repo]
+	c.mu.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.config
+	}
+
+	config, err := s.GetLFSConfig(ctx, repo)
+	if err != nil {
+		s.Logger.Warn("failed to get LFS config", log.String("repo", string(repo)), log.Error(err))
+		// We cache the failure as disabled to avoid hammering the database.
+		config = nil
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[api.RepoName]lfsConfigCacheEntry{}
+	}
+	// Remove expired entries so the cache does not grow with repos which
+	// are no longer read.
+	now := time.Now()
+	for r, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, r)
+		}
+	}
+	c.entries[repo] = lfsConfigCacheEntry{config: config, expires: now.Add(lfsConfigTTL)}
+	c.mu.Unlock()
+
+	return config
+}
+
+// lfsExec returns the arguments to run and the writer for the stdout of the
+// git command args against repo, such that LFS pointers in the output are
+// replaced with their objects. If LFS does not apply to the command, stdout
+// is nil and args are returned unmodified.
+//
+// stdout must be closed once the command has exited.
+func (s *Server) lfsExec(ctx context.Context, repo api.RepoName, dir GitDir, args []string, w io.Writer) (_ []string, stdout io.WriteCloser) {
+	if len(args) == 0 || (args[0] != "show" && args[0] != "archive") {
+		return args, nil
+	}
+
+	config := s.lfsConfig(ctx, repo)
+	if config == nil {
+		return args, nil
+	}
+	store := &lfsStore{
+		logger: s.Logger.Scoped("lfs", "resolves Git LFS pointers"),
+		dir:    dir,
+		config: *config,
+		doer:   httpcli.ExternalDoer,
+
+		cacheMaxSize: lfsCacheMaxSize,
+		tmpDir:       filepath.Join(s.ReposDir, tempDirName),
+
+		remoteURL: func(ctx context.Context) (*vcs.URL, error) {
+			return s.getRemoteURL(ctx, repo)
+		},
+	}
+
+	switch args[0] {
+	case "show":
+		// Only "git show <commit>:<path>" reads a single blob, which is what
+		// ReadFile and NewFileReader run.
+		if len(args) != 2 || !strings.Contains(args[1], ":") {
+			return args, nil
+		}
+		return args, &lfsBlobWriter{ctx: ctx, w: w, store: store}
+
+	case "archive":
+		var zipFormat bool
+		rewritten := make([]string, 0, len(args))
+		for _, arg := range args {
+			switch {
+			case arg == "--format=zip":
+				// We can only rewrite a streamed archive, so we ask git for
+				// a tar and convert it to a zip ourselves.
+				zipFormat = true
+				rewritten = append(rewritten, "--format=tar")
+			case arg == "-0" && zipFormat:
+				// Compression level only applies to zip.
+			case strings.HasPrefix(arg, "--format=") && arg != "--format=tar":
+				return args, nil
+			default:
+				rewritten = append(rewritten, arg)
+			}
+		}
+		return rewritten, newLFSArchiveWriter(ctx, store, w, zipFormat)
+	}
+
+	return args, nil
+}
+
+// lfsBlobWriter buffers the output of "git show" for a blob. If the blob is a
+// pointer file it writes the object instead on Close.
+type lfsBlobWriter struct {
+	ctx   context.Context
+	w     io.Writer
+	store *lfsStore
+
+	buf         []byte
+	passthrough bool
+}
+
+func (w *lfsBlobWriter) Write(p []byte) (int, error) {
+	if w.passthrough {
+		return w.w.Write(p)
+	}
+	if len(w.buf)+len(p) <= lfsPointerMaxSize {
+		w.buf = append(w.buf, p...)
+		return len(p), nil
+	}
+
+	// Too large to be a pointer.
+	w.passthrough = true
+	if _, err := w.w.Write(w.buf); err != nil {
+		return 0, err
+	}
+	w.buf = nil
+	return w.w.Write(p)
+}
+
+func (w *lfsBlobWriter) Close() error {
+	if w.passthrough {
+		return nil
+	}
+	rc, _ := w.store.resolveOrPointer(w.ctx, w.buf, true)
+	defer rc.Close()
+	_, err := io.Copy(w.w, rc)
+	return err
+}
+
+// lfsArchiveWriter rewrites the tar archive written to it, replacing pointer
+// files with their objects.
+type lfsArchiveWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func newLFSArchiveWriter(ctx context.Context, store *lfsStore, w io.Writer, zipFormat bool) *lfsArchiveWriter {
+	pr, pw := io.Pipe()
+	aw := &lfsArchiveWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		var err error
+		if zipFormat {
+			err = lfsRewriteTarToZip(ctx, store, pr, w)
+		} else {
+			err = lfsRewriteTar(ctx, store, pr, w)
+		}
+		// Unblock git if we stopped reading early.
+		_ = pr.CloseWithError(errors.Wrap(err, "rewriting LFS pointers"))
+		aw.done <- err
+	}()
+	return aw
+}
+
+func (w *lfsArchiveWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *lfsArchiveWriter) Close() error {
+	_ = w.pw.Close()
+	return <-w.done
+}
+
+// lfsSpoolArchive copies the tar archive in r to a temporary file and fetches
+// the objects of all its pointer files which are not cached yet, so that we
+// send a few batch requests per archive instead of one per pointer. The
+// returned file is positioned at the start of the archive and must be closed
+// by the caller, which removes it.
+func lfsSpoolArchive(ctx context.Context, store *lfsStore, r io.Reader) (*lfsSpooledArchive, error) {
+	if store.tmpDir != "" {
+		if err := os.MkdirAll(store.tmpDir, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.CreateTemp(store.tmpDir, "lfs-archive-")
+	if err != nil {
+		return nil, err
+	}
+	spooled := &lfsSpooledArchive{File: f}
+
+	var (
+		missing []lfsPointer
+		seen    = map[string]struct{}{}
+	)
+	tr := tar.NewReader(io.TeeReader(r, f))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			spooled.Close()
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > lfsPointerMaxSize {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			spooled.Close()
+			return nil, err
+		}
+		p, ok := store.pointer(data)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[p.OID]; ok {
+			continue
+		}
+		seen[p.OID] = struct{}{}
+		if _, err := os.Stat(store.objectPath(p.OID)); os.IsNotExist(err) {
+			missing = append(missing, p)
+		}
+	}
+	// Copy any trailing padding after the end of the archive.
+	if _, err := io.Copy(f, r); err != nil {
+		spooled.Close()
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		if err := store.fetch(ctx, missing); err != nil {
+			// We return the pointers of objects we could not fetch.
+			lfsFetchFailedCounter.Inc()
+			store.logger.Warn("failed to fetch LFS objects", log.String("dir", string(store.dir)), log.Int("objects", len(missing)), log.Error(err))
+		}
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	return spooled, nil
+}
+
+type lfsSpooledArchive struct {
+	*os.File
+}
+
+func (a *lfsSpooledArchive) Close() error {
+	err := a.File.Close()
+	_ = os.Remove(a.File.Name())
+	return err
+}
+
+// lfsNextEntry reads the next entry of tr. If it is a pointer file to a cached
+// object the returned reader reads the object and hdr.Size is updated
+// accordingly.
+func lfsNextEntry(ctx context.Context, store *lfsStore, tr *tar.Reader) (*tar.Header, io.ReadCloser, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if hdr.Typeflag != tar.TypeReg || hdr.Size > lfsPointerMaxSize {
+		return hdr, io.NopCloser(tr), nil
+	}
+
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, size := store.resolveOrPointer(ctx, data, false)
+	hdr.Size = size
+	return hdr, rc, nil
+}
+
+// lfsRewriteTar copies the tar archive in r to w, replacing pointer files
+// with their objects.
+func lfsRewriteTar(ctx context.Context, store *lfsStore, r io.Reader, w io.Writer) error {
+	spooled, err := lfsSpoolArchive(ctx, store, r)
+	if err != nil {
+		return err
+	}
+	defer spooled.Close()
+
+	tr := tar.NewReader(spooled)
+	tw := tar.NewWriter(w)
+	for {
+		hdr, rc, err := lfsNextEntry(ctx, store, tr)
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+		err = tw.WriteHeader(hdr)
+		if err == nil {
+			_, err = io.Copy(tw, rc)
+		}
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// lfsRewriteTarToZip converts the tar archive in r to an uncompressed zip
+// archive written to w, replacing pointer files with their objects.
+func lfsRewriteTarToZip(ctx context.Context, store *lfsStore, r io.Reader, w io.Writer) error {
+	spooled, err := lfsSpoolArchive(ctx, store, r)
+	if err != nil {
+		return err
+	}
+	defer spooled.Close()
+
+	tr := tar.NewReader(spooled)
+	zw := zip.NewWriter(w)
+	for {
+		hdr, rc, err := lfsNextEntry(ctx, store, tr)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		err = writeZipEntry(zw, hdr, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, hdr *tar.Header, r io.Reader) error {
+	fh := &zip.FileHeader{
+		Name:     hdr.Name,
+		Method:   zip.Store,
+		Modified: hdr.ModTime,
+	}
+	switch hdr.Typeflag {
+	case tar.TypeReg:
+		fh.SetMode(hdr.FileInfo().Mode())
+	case tar.TypeDir:
+		fh.Name = strings.TrimSuffix(fh.Name, "/") + "/"
+		fh.SetMode(hdr.FileInfo().Mode())
+		_, err := zw.CreateHeader(fh)
+		return err
+	case tar.TypeSymlink:
+		fh.SetMode(hdr.FileInfo().Mode())
+		r = strings.NewReader(hdr.Linkname)
+	default:
+		// Skip the pax global header git writes with the commit ID.
+		return nil
+	}
+
+	fw, err := zw.CreateHeader(fh)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+var (
+	lfsFetchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "src_gitserver_lfs_fetch_duration_seconds",
+		Help:    "Time taken to fetch a Git LFS object.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	})
+	lfsFetchFailedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_lfs_fetch_failed",
+		Help: "Incremented each time resolving a Git LFS pointer failed.",
+	})
+	lfsFetchedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_lfs_fetched_bytes",
+		Help: "The total size of the Git LFS objects fetched.",
+	})
+	lfsEvictedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_lfs_evicted",
+		Help: "Incremented each time a cached Git LFS object is evicted.",
+	})
+	lfsCacheSizeBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_lfs_cache_size_bytes",
+		Help: "The total size of the cached Git LFS objects, as of the last janitor run.",
+	})
+)
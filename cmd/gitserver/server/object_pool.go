@@ -0,0 +1,405 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Object pools deduplicate the objects of repositories in the same fork
+// network, i.e. forks and the repositories they were forked from.
+//
+// An object pool is a bare repository below ReposDir/.pools which is shared by
+// all members of a fork network cloned on this shard. Members borrow objects
+// from the pool with an objects/info/alternates link, and the pool keeps a
+// copy of the refs of every member under refs/members/<id>/. Since the pool
+// references everything its members reference, garbage collecting the pool
+// never prunes objects members still need. The git gc and sg maintenance runs
+// of members repack with -l, which drops the objects members borrow.
+//
+// Pools are keyed by the fork network of a repository on its code host, as
+// returned by Server.ForkNetwork, rather than by shared history. Members can
+// read every object of their pool, so unrelated repositories, or
+// repositories readable by different users, must never share a pool.
+
+var (
+	enableObjectPools, _      = strconv.ParseBool(env.Get("SRC_ENABLE_OBJECT_POOLS", "false", "Store forks with an objects/info/alternates link to an object pool shared with the repositories of their fork network"))
+	objectPoolMigrateLimit, _ = strconv.Atoi(env.Get("SRC_OBJECT_POOL_MIGRATE_LIMIT", "10", "the maximum number of existing clones moved to object pools in one janitor run"))
+)
+
+var (
+	objectPoolsTotal = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_object_pools_total",
+		Help: "number of object pools shared by the repositories of fork networks",
+	})
+	objectPoolMembersJoined = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_object_pool_members_joined",
+		Help: "number of repositories which started to borrow objects from an object pool",
+	})
+)
+
+const (
+	// poolsDirName is the name of the directory under ReposDir which contains
+	// the object pools.
+	poolsDirName = ".pools"
+	// objectPoolMembersFile is the file in an object pool which lists the names
+	// of its members, one per line.
+	objectPoolMembersFile = "sourcegraph-members"
+)
+
+// objectPool synchronizes the operations on an object pool.
+type objectPool struct {
+	// gc is held for reading while members write objects or refs which may
+	// depend on the pool, and for writing while the pool is garbage collected.
+	gc sync.RWMutex
+	// mu serializes updates of the pool.
+	mu sync.Mutex
+}
+
+type objectPools struct {
+	mu    sync.Mutex
+	pools map[string]*objectPool
+}
+
+func (p *objectPools) get(key string) *objectPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pools == nil {
+		p.pools = make(map[string]*objectPool)
+	}
+	pool, ok := p.pools[key]
+	if !ok {
+		pool = &objectPool{}
+		p.pools[key] = pool
+	}
+	return pool
+}
+
+func (p *objectPools) delete(key string) {
+	p.mu.Lock()
+	delete(p.pools, key)
+	p.mu.Unlock()
+}
+
+// objectPoolDir returns the directory of the object pool with the given key.
+func (s *Server) objectPoolDir(key string) GitDir {
+	return GitDir(filepath.Join(s.ReposDir, poolsDirName, key))
+}
+
+// objectPoolOf returns the key of the object pool dir borrows objects from.
+func (s *Server) objectPoolOf(dir GitDir) (key string, ok bool) {
+	b, err := os.ReadFile(dir.Path("objects", "info", "alternates"))
+	if err != nil {
+		return "", false
+	}
+	prefix := filepath.Join(s.ReposDir, poolsDirName) + string(filepath.Separator)
+	for _, line := range strings.Split(string(b), "\n") {
+		if rest := strings.TrimPrefix(line, prefix); rest != line {
+			return strings.TrimSuffix(rest, string(filepath.Separator)+"objects"), true
+		}
+	}
+	return "", false
+}
+
+// rlockObjectPool prevents the object pool dir borrows objects from, if any,
+// from being garbage collected until unlock is called.
+func (s *Server) rlockObjectPool(dir GitDir) (key string, unlock func()) {
+	key, ok := s.objectPoolOf(dir)
+	if !ok {
+		return "", func() {}
+	}
+	pool := s.objectPools.get(key)
+	pool.gc.RLock()
+	return key, pool.gc.RUnlock
+}
+
+// forkNetwork returns the key of the object pool of repo, if it should use
+// one.
+func (s *Server) forkNetwork(ctx context.Context, repo api.RepoName) (key string, ok bool) {
+	if !enableObjectPools || s.ForkNetwork == nil {
+		return "", false
+	}
+	network, err := s.ForkNetwork(ctx, repo)
+	if err != nil {
+		s.Logger.Warn("failed to determine the fork network of repository", log.String("repo", string(repo)), log.Error(err))
+		return "", false
+	}
+	if network == "" {
+		return "", false
+	}
+	return objectPoolKey(network), true
+}
+
+// objectPoolKey returns the key of the object pool of the fork network with
+// the given identity, which is safe to use as a directory name.
+func objectPoolKey(network string) string {
+	sum := sha256.Sum256([]byte(network))
+	return hex.EncodeToString(sum[:])
+}
+
+// joinObjectPool makes the repository repo in dir borrow objects from the
+// object pool with key, creating the pool if needed. The objects of repo
+// which are in the pool are removed from dir.
+func (s *Server) joinObjectPool(ctx context.Context, repo api.RepoName, dir GitDir, key string) error {
+	if _, ok := s.objectPoolOf(dir); ok {
+		return nil
+	}
+
+	pool := s.objectPools.get(key)
+	pool.gc.RLock()
+	defer pool.gc.RUnlock()
+
+	// We copy the objects of repo into the pool before dir borrows any, so
+	// that dir is complete at every step.
+	poolDir := s.objectPoolDir(key)
+	if err := s.fetchIntoObjectPool(ctx, pool, poolDir, repo, dir); err != nil {
+		return errors.Wrap(err, "fetch into object pool")
+	}
+	if err := os.MkdirAll(dir.Path("objects", "info"), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(dir.Path("objects", "info", "alternates"), []byte(poolDir.Path("objects")+"\n"), 0600); err != nil {
+		return errors.Wrap(err, "write alternates")
+	}
+	objectPoolMembersJoined.Inc()
+
+	// If we can't lock the repository a gc is already running, which drops
+	// the borrowed objects for us.
+	err, unlock := lockRepoForGC(dir)
+	if err != nil {
+		return nil
+	}
+	defer unlock()
+	cmd := exec.CommandContext(ctx, "git", "repack", "-a", "-d", "-l", "-q")
+	dir.Set(cmd)
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "repack")
+	}
+	return nil
+}
+
+// syncObjectPool copies the refs of the member repo in dir into its object
+// pool. It must be called while the pool with key is read locked.
+func (s *Server) syncObjectPool(ctx context.Context, repo api.RepoName, dir GitDir, key string) error {
+	return s.fetchIntoObjectPool(ctx, s.objectPools.get(key), s.objectPoolDir(key), repo, dir)
+}
+
+// fetchIntoObjectPool adds repo to the members of the pool in poolDir and
+// fetches the objects and refs of dir into it.
+func (s *Server) fetchIntoObjectPool(ctx context.Context, pool *objectPool, poolDir GitDir, repo api.RepoName, dir GitDir) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if _, err := os.Stat(string(poolDir)); os.IsNotExist(err) {
+		if err := os.MkdirAll(string(poolDir), os.ModePerm); err != nil {
+			return err
+		}
+		cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+		poolDir.Set(cmd)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "failed to init object pool with output %q", string(out))
+		}
+		// The pool is garbage collected by cleanupObjectPool.
+		if err := gitConfigSet(poolDir, "gc.auto", "0"); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	members, err := readObjectPoolMembers(poolDir)
+	if err != nil {
+		return err
+	}
+	if !containsRepo(members, repo) {
+		if err := writeObjectPoolMembers(poolDir, append(members, repo)); err != nil {
+			return err
+		}
+	}
+
+	return fetchObjectPoolMember(ctx, poolDir, repo, dir)
+}
+
+// fetchObjectPoolMember fetches the refs of the member repo in dir into the
+// pool in poolDir.
+func fetchObjectPoolMember(ctx context.Context, poolDir GitDir, repo api.RepoName, dir GitDir) error {
+	// We keep the fetched objects packed, since repack -l only drops the
+	// objects of members which are in packs of the pool.
+	cmd := exec.CommandContext(ctx, "git", "-c", "fetch.unpackLimit=1", "fetch", "--no-auto-gc", "--prune", "--no-tags", "--quiet",
+		string(dir), "+refs/*:"+objectPoolMemberRefs(repo)+"*")
+	poolDir.Set(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to fetch %s into object pool with output %q", repo, string(out))
+	}
+	return nil
+}
+
+// cleanupObjectPools garbage collects the object pools of this shard and
+// removes the pools without members. It returns the disk usage of the pools.
+func (s *Server) cleanupObjectPools(ctx context.Context) (size int64) {
+	entries, err := os.ReadDir(filepath.Join(s.ReposDir, poolsDirName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.Logger.Error("failed to list object pools", log.Error(err))
+		}
+		objectPoolsTotal.Set(0)
+		return 0
+	}
+
+	var pools int
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		removed, err := s.cleanupObjectPool(ctx, e.Name())
+		if err != nil {
+			s.Logger.Error("failed to clean up object pool", log.String("pool", e.Name()), log.Error(err))
+		}
+		if !removed {
+			pools++
+			size += dirSize(string(s.objectPoolDir(e.Name())))
+		}
+	}
+	objectPoolsTotal.Set(float64(pools))
+	return size
+}
+
+// cleanupObjectPool garbage collects the object pool with key. Members which
+// no longer borrow objects from the pool are removed from it, and the pool is
+// removed once it has no members.
+func (s *Server) cleanupObjectPool(ctx context.Context, key string) (removed bool, err error) {
+	pool := s.objectPools.get(key)
+	// No member may write objects or refs while we decide what to prune.
+	pool.gc.Lock()
+	defer pool.gc.Unlock()
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	poolDir := s.objectPoolDir(key)
+	members, err := readObjectPoolMembers(poolDir)
+	if err != nil {
+		return false, err
+	}
+
+	var kept []api.RepoName
+	synced := true
+	for _, repo := range members {
+		dir := s.dir(repo)
+		if k, ok := s.objectPoolOf(dir); !ok || k != key {
+			// repo was removed, or recloned without the pool. It does not
+			// borrow any objects, so we can forget its refs.
+			if err := deleteRefs(ctx, poolDir, objectPoolMemberRefs(repo)); err != nil {
+				return false, err
+			}
+			continue
+		}
+		kept = append(kept, repo)
+
+		// The refs of members are usually up to date, since we copy them after
+		// every fetch. We copy them again to cover other writes, such as
+		// reclones.
+		if err := fetchObjectPoolMember(ctx, poolDir, repo, dir); err != nil {
+			s.Logger.Warn("failed to sync object pool member", log.String("pool", key), log.String("repo", string(repo)), log.Error(err))
+			synced = false
+		}
+	}
+
+	if len(kept) == 0 {
+		if err := os.RemoveAll(string(poolDir)); err != nil {
+			return false, err
+		}
+		s.objectPools.delete(key)
+		return true, nil
+	}
+	if err := writeObjectPoolMembers(poolDir, kept); err != nil {
+		return false, err
+	}
+
+	// If we failed to copy the refs of a member, the pool may not reference
+	// all objects the member borrows.
+	if !synced {
+		return false, errors.New("not all members are synced, skipping garbage collection")
+	}
+	return false, gitGC(poolDir)
+}
+
+// objectPoolMemberRefs returns the prefix of the refs of repo in its object
+// pool.
+func objectPoolMemberRefs(repo api.RepoName) string {
+	sum := sha256.Sum256([]byte(repo))
+	return "refs/members/" + hex.EncodeToString(sum[:16]) + "/"
+}
+
+func readObjectPoolMembers(poolDir GitDir) ([]api.RepoName, error) {
+	b, err := os.ReadFile(poolDir.Path(objectPoolMembersFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var members []api.RepoName
+	for _, line := range strings.Split(string(b), "\n") {
+		if line != "" {
+			members = append(members, api.RepoName(line))
+		}
+	}
+	return members, nil
+}
+
+func writeObjectPoolMembers(poolDir GitDir, members []api.RepoName) error {
+	var b bytes.Buffer
+	for _, repo := range members {
+		b.WriteString(string(repo))
+		b.WriteByte('\n')
+	}
+	tmp := poolDir.Path(objectPoolMembersFile + ".tmp")
+	if err := os.WriteFile(tmp, b.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, poolDir.Path(objectPoolMembersFile))
+}
+
+func containsRepo(repos []api.RepoName, repo api.RepoName) bool {
+	for _, r := range repos {
+		if r == repo {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteRefs deletes the refs with the given prefix in dir.
+func deleteRefs(ctx context.Context, dir GitDir, prefix string) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=delete %(refname)", prefix)
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "list refs")
+	}
+	if len(out) == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = bytes.NewReader(out)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to delete refs with output %q", string(out))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestObjectPool(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	reposDir := filepath.Join(root, "repos")
+	upstreamDir := filepath.Join(root, "upstream")
+	if err := os.MkdirAll(upstreamDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, upstreamDir, name, arg...)
+	}
+	makeSingleCommitRepo(cmd)
+
+	s := &Server{
+		Logger:   logtest.Scoped(t),
+		ReposDir: reposDir,
+	}
+
+	clone := func(repo api.RepoName) GitDir {
+		t.Helper()
+		dir := s.dir(repo)
+		runCmd(t, root, "git", "clone", "--bare", "--no-local", upstreamDir, string(dir))
+		return dir
+	}
+	git := func(dir GitDir, arg ...string) string {
+		t.Helper()
+		c := exec.Command("git", arg...)
+		dir.Set(c)
+		out, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %s\nOutput: %s", strings.Join(arg, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	countObjects := func(dir GitDir) string {
+		t.Helper()
+		var counts []string
+		for _, line := range strings.Split(git(dir, "count-objects", "-v"), "\n") {
+			if strings.HasPrefix(line, "count:") || strings.HasPrefix(line, "in-pack:") {
+				counts = append(counts, line)
+			}
+		}
+		return strings.Join(counts, ", ")
+	}
+
+	upstream := clone("example.com/upstream")
+	cmd("sh", "-c", "echo fork >> hello.txt")
+	addCommitToRepo(cmd)
+	fork := clone("example.com/fork")
+
+	network := objectPoolKey("github:https://github.com/:upstream")
+	for _, repo := range []api.RepoName{"example.com/upstream", "example.com/fork"} {
+		if err := s.joinObjectPool(ctx, repo, s.dir(repo), network); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	key, ok := s.objectPoolOf(fork)
+	if !ok {
+		t.Fatal("fork does not borrow objects from an object pool")
+	}
+	if key != network {
+		t.Fatalf("got object pool %q, want %q", key, network)
+	}
+	if k, _ := s.objectPoolOf(upstream); k != key {
+		t.Fatalf("upstream borrows objects from %q, want %q", k, key)
+	}
+	for _, dir := range []GitDir{upstream, fork} {
+		if got, want := countObjects(dir), "count: 0, in-pack: 0"; got != want {
+			t.Errorf("%s: got %q, want %q", dir, got, want)
+		}
+		git(dir, "fsck", "--connectivity-only")
+	}
+
+	// The pool keeps the objects of the fork when the upstream is removed.
+	if err := os.RemoveAll(filepath.Dir(string(upstream))); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := s.cleanupObjectPool(ctx, key); err != nil {
+		t.Fatal(err)
+	} else if removed {
+		t.Fatal("object pool removed while it has members")
+	}
+	poolDir := s.objectPoolDir(key)
+	if got, want := git(poolDir, "for-each-ref", "--format=%(refname)"), objectPoolMemberRefs("example.com/fork")+"heads/master"; got != want {
+		t.Fatalf("got pool refs %q, want %q", got, want)
+	}
+	git(fork, "fsck", "--connectivity-only")
+
+	// The pool is removed with its last member.
+	if err := os.RemoveAll(filepath.Dir(string(fork))); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := s.cleanupObjectPool(ctx, key); err != nil {
+		t.Fatal(err)
+	} else if !removed {
+		t.Fatal("object pool without members was not removed")
+	}
+	if _, err := os.Stat(string(poolDir)); !os.IsNotExist(err) {
+		t.Fatalf("expected object pool to be removed, got %v", err)
+	}
+}
@@ -35,7 +35,8 @@ func (s *Server) handleReposStats(w http.ResponseWriter, r *http.Request) {
 func (s *Server) repoCloneProgress(repo api.RepoName) *protocol.RepoCloneProgress {
 	dir := s.dir(repo)
 	resp := protocol.RepoCloneProgress{
-		Cloned: repoCloned(dir),
+		Cloned:   repoCloned(dir),
+		Archived: repoArchived(dir),
 	}
 	resp.CloneProgress, resp.CloneInProgress = s.locker.Status(dir)
 	if isAlwaysCloningTest(repo) {
@@ -84,10 +85,12 @@ func (s *Server) handleRepoDelete(w http.ResponseWriter, r *http.Request) {
 func (s *Server) deleteRepo(ctx context.Context, repo api.RepoName) error {
 	// The repo may be deleted in the database, in this case we need to get the
 	// original name in order to find it on disk
-	err := s.removeRepoDirectory(s.dir(api.UndeletedRepoName(repo)), true)
+	dir := s.dir(api.UndeletedRepoName(repo))
+	err := s.removeRepoDirectory(dir, true)
 	if err != nil {
 		return errors.Wrap(err, "removing repo directory")
 	}
+	s.removeArchive(ctx, dir)
 	err = s.setCloneStatus(ctx, repo, types.CloneStatusNotCloned)
 	if err != nil {
 		return errors.Wrap(err, "setting clone status after delete")
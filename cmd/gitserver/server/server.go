@@ -58,6 +58,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -298,6 +299,29 @@ type Server struct {
 	// usually set to return a GitRepoSyncer.
 	GetVCSSyncer func(context.Context, api.RepoName) (VCSSyncer, error)
 
+	// GetLFSConfig is a function which returns how Git LFS pointers of a
+	// repository are resolved. A nil config means pointers are returned as
+	// is. If GetLFSConfig is nil LFS is disabled for all repositories.
+	GetLFSConfig func(context.Context, api.RepoName) (*LFSConfig, error)
+
+	// lfsConfigs caches the results of GetLFSConfig.
+	lfsConfigs lfsConfigCache
+
+	// ForkNetwork is a function which returns the identity of the fork
+	// network of a repository on its code host, or an empty string if the
+	// repository must not use an object pool. Repositories of the same fork
+	// network borrow objects from a shared object pool if
+	// SRC_ENABLE_OBJECT_POOLS is set, which allows each of them to read the
+	// objects of the others.
+	ForkNetwork func(context.Context, api.RepoName) (string, error)
+
+	// objectPools synchronizes the operations on object pools.
+	objectPools objectPools
+
+	// ArchiveStore is the store idle repositories are archived to. If
+	// ArchiveStore is nil repositories are never archived.
+	ArchiveStore uploadstore.Store
+
 	// Hostname is how we identify this instance of gitserver. Generally it is the
 	// actual hostname but can also be overridden by the HOSTNAME environment variable.
 	Hostname string
@@ -888,11 +912,12 @@ func (s *Server) tempDir(prefix string) (name string, err error) {
 }
 
 func (s *Server) ignorePath(path string) bool {
-	// We ignore any path which starts with .tmp in ReposDir
+	// We ignore any path which starts with .tmp in ReposDir, and the object
+	// pools in ReposDir/.pools.
 	if filepath.Dir(path) != s.ReposDir {
 		return false
 	}
-	return strings.HasPrefix(filepath.Base(path), tempDirName)
+	return strings.HasPrefix(filepath.Base(path), tempDirName) || filepath.Base(path) == poolsDirName
 }
 
 func (s *Server) handleIsRepoCloneable(w http.ResponseWriter, r *http.Request) {
@@ -966,7 +991,12 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 	defer cancel1()
 	ctx, cancel2 := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
 	defer cancel2()
-	if !repoCloned(dir) && !s.skipCloneForTests {
+	if !repoCloned(dir) && repoArchived(dir) && req.CloneFromShard == "" {
+		// Scheduled updates don't restore archived repositories, otherwise
+		// they would be restored right after being archived. They are
+		// restored once they are accessed.
+		resp.Archived = true
+	} else if !repoCloned(dir) && !s.skipCloneForTests {
 		// We do not need to check if req.CloneFromShard is non-zero here since that has no effect on
 		// the code path at this point. Since the repo is already not cloned at this point, either
 		// this request was received for a repo migration or a regular clone - for both of which we
@@ -1120,6 +1150,7 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		attribute.String("query", args.Query.String()),
 		attribute.Int("limit", args.Limit),
 		attribute.Bool("include_modified_files", args.IncludeModifiedFiles),
+		attribute.Bool("range_diff", args.RangeDiff),
 	)
 
 	searchStart := time.Now()
@@ -1161,6 +1192,7 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		ev.AddField("revisions", args.Revisions)
 		ev.AddField("include_diff", args.IncludeDiff)
 		ev.AddField("include_modified_files", args.IncludeModifiedFiles)
+		ev.AddField("range_diff", args.RangeDiff)
 		ev.AddField("actor", act.UIDString())
 		ev.AddField("query", args.Query.String())
 		ev.AddField("limit", args.Limit)
@@ -1257,6 +1289,7 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, match
 			Query:                mt,
 			IncludeDiff:          args.IncludeDiff,
 			IncludeModifiedFiles: args.IncludeModifiedFiles,
+			RangeDiff:            args.RangeDiff,
 		}
 
 		return searcher.Search(ctx, func(match *protocol.CommitMatch) {
@@ -1663,6 +1696,10 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 		return
 	}
 
+	// Commands and archives are uses of the repository, which keep it from
+	// being archived.
+	touchLastAccess(dir)
+
 	if !conf.Get().DisableAutoGitUpdates {
 		// ensureRevision may kick off a git fetch operation which we don't want if we've
 		// configured DisableAutoGitUpdates.
@@ -1708,13 +1745,25 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	stdoutW := &writeCounter{w: w}
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
+	// If the repository uses Git LFS we may need to replace pointer files in
+	// the output with their content.
+	args, lfsStdout := s.lfsExec(ctx, req.Repo, dir, req.Args, stdoutW)
+
 	cmdStart = time.Now()
-	cmd := exec.CommandContext(ctx, "git", req.Args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
 	dir.Set(cmd)
 	cmd.Stdout = stdoutW
+	if lfsStdout != nil {
+		cmd.Stdout = lfsStdout
+	}
 	cmd.Stderr = stderrW
 
 	exitStatus, execErr = runCommand(ctx, cmd)
+	if lfsStdout != nil {
+		if err := lfsStdout.Close(); err != nil && execErr == nil {
+			execErr = err
+		}
+	}
 
 	status = strconv.Itoa(exitStatus)
 	stdoutN = stdoutW.n
@@ -2144,25 +2193,44 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 		s.setCloneStatusNonFatal(context.Background(), repo, cloneStatus(repoCloned(dir), false))
 	}()
 
-	cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
-	if err != nil {
-		return errors.Wrap(err, "get clone command")
-	}
-	if cmd.Env == nil {
-		cmd.Env = os.Environ()
+	// Archived repositories are restored from their bundle, which only
+	// requires fetching what changed since they were archived.
+	archive, archived := readArchiveStub(dir)
+	restored := false
+	if archived && s.ArchiveStore != nil {
+		lock.SetStatus("restoring archived repository")
+		logger.Info("restoring archived repo", log.String("tmp", tmpPath), log.String("dst", dstPath))
+		if err := s.restoreArchive(ctx, archive, tmp, syncer, remoteURL); err != nil {
+			logger.Warn("failed to restore archived repo, cloning it instead", log.Error(err))
+			if err := os.RemoveAll(tmpPath); err != nil {
+				return err
+			}
+		} else {
+			restored = true
+		}
 	}
 
-	// see issue #7322: skip LFS content in repositories with Git LFS configured
-	cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
-	logger.Info("cloning repo", log.String("tmp", tmpPath), log.String("dst", dstPath))
+	if !restored {
+		cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
+		if err != nil {
+			return errors.Wrap(err, "get clone command")
+		}
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+
+		// see issue #7322: skip LFS content in repositories with Git LFS configured
+		cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
+		logger.Info("cloning repo", log.String("tmp", tmpPath), log.String("dst", dstPath))
 
-	pr, pw := io.Pipe()
-	defer pw.Close()
+		pr, pw := io.Pipe()
+		defer pw.Close()
 
-	go readCloneProgress(logger, newURLRedactor(remoteURL), lock, pr, repo)
+		go readCloneProgress(logger, newURLRedactor(remoteURL), lock, pr, repo)
 
-	if output, err := runWith(ctx, cmd, true, pw); err != nil {
-		return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+		if output, err := runWith(ctx, cmd, true, pw); err != nil {
+			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+		}
 	}
 
 	if testRepoCorrupter != nil {
@@ -2210,12 +2278,26 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 		return err
 	}
 
+	// The clone replaces the archive.
+	if archived {
+		s.removeArchive(ctx, dir)
+	}
+
 	// Successfully updated, best-effort updating of db fetch state based on
 	// disk state.
 	if err := s.setLastFetched(ctx, repo); err != nil {
 		logger.Warn("failed setting last fetch in DB", log.Error(err))
 	}
 
+	// Forks and their upstreams borrow the objects they share from an object
+	// pool. The clone is usable without the pool, so failing to join is not
+	// fatal.
+	if key, ok := s.forkNetwork(ctx, repo); ok {
+		if err := s.joinObjectPool(ctx, repo, dir, key); err != nil {
+			logger.Warn("failed to join object pool", log.Error(err))
+		}
+	}
+
 	// Successfully updated, best-effort calculation of the repo size.
 	if err := s.setRepoSize(ctx, repo); err != nil {
 		logger.Warn("failed setting repo size", log.Error(err))
@@ -2527,10 +2609,20 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 	// when the cleanup happens, just that it does.
 	defer s.cleanTmpFiles(dir)
 
+	// Fetched objects may depend on objects borrowed from an object pool, so
+	// the pool must not be garbage collected before it references them.
+	poolKey, unlockPool := s.rlockObjectPool(dir)
 	err = syncer.Fetch(ctx, remoteURL, dir, revspec)
 	if err != nil {
+		unlockPool()
 		return errors.Wrap(err, "failed to fetch")
 	}
+	if poolKey != "" {
+		if err := s.syncObjectPool(ctx, repo, dir, poolKey); err != nil {
+			logger.Warn("failed to sync object pool", log.String("repo", string(repo)), log.Error(err))
+		}
+	}
+	unlockPool()
 
 	removeBadRefs(ctx, dir)
 
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// MercurialSyncer is a syncer for Mercurial repositories. Repositories are
+// converted to Git with git-cinnabar, which is a Git remote helper for the
+// "hg::" URL scheme and fetches new changesets incrementally.
+//
+// git-cinnabar exposes bookmarks as refs/heads/bookmarks/<name> and the tip of
+// each named branch as refs/heads/branches/<branch>/tip. We fetch them into
+// refs/hg/ and mirror them to refs/heads and refs/tags after each fetch, with
+// the "default" branch becoming "master".
+type MercurialSyncer struct{}
+
+func (s *MercurialSyncer) Type() string {
+	return "mercurial"
+}
+
+// IsCloneable checks to see if the Mercurial repository at remoteURL exists and
+// is accessible.
+func (s *MercurialSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	args := []string{"ls-remote", mercurialRemote(remoteURL)}
+	ctx, cancel := context.WithTimeout(ctx, shortGitCommandTimeout(args))
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append(mercurialConfigArgs(), args...)...)
+	out, err := runWith(ctx, cmd, true, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand initializes a bare Git repository at tmpPath and converts the
+// Mercurial repository at remoteURL into it.
+//
+// Like for Subversion the conversion happens as part of CloneCommand, since
+// the fetched refs have to be mirrored afterwards. The returned command is a
+// no-op.
+func (s *MercurialSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+	dir := GitDir(tmpPath)
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to init repository with output %q", string(output))
+	}
+
+	if err := s.Fetch(ctx, remoteURL, dir, ""); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert repository")
+	}
+
+	// no-op command to satisfy VCSSyncer interface, see docstring for more details.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
+
+// Fetch fetches new changesets of the Mercurial repository and updates the
+// branches and tags of the Git repository.
+func (s *MercurialSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	args := append(mercurialConfigArgs(), "fetch",
+		// We already have janitor jobs that run git gc. We disable git gc here to avoid
+		// a possible corruption of repositories by competing gc processes.
+		"--no-auto-gc",
+		"--prune", mercurialRemote(remoteURL),
+		"+refs/heads/*:refs/hg/heads/*", "+refs/tags/*:refs/hg/tags/*")
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, true, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	return mirrorMercurialRefs(ctx, dir)
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote
+// of a Mercurial repository.
+func (s *MercurialSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (*exec.Cmd, error) {
+	// The refs of the remote are not the refs we mirror. The default branch
+	// of Mercurial repositories is "default", which we mirror as "master".
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// mercurialConfigArgs returns the git arguments configuring git-cinnabar.
+func mercurialConfigArgs() []string {
+	// With several styles git-cinnabar prefixes the refs of each style, see
+	// mercurialRefUpdates.
+	return []string{"-c", "cinnabar.refs=bookmarks,tips"}
+}
+
+// mercurialRemote returns the git-cinnabar remote for remoteURL.
+func mercurialRemote(remoteURL *vcs.URL) string {
+	return "hg::" + remoteURL.String()
+}
+
+// mirrorMercurialRefs updates refs/heads and refs/tags in dir to match the
+// refs git-cinnabar fetched into refs/hg.
+func mirrorMercurialRefs(ctx context.Context, dir GitDir) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)", "refs/hg/", "refs/heads/", "refs/tags/")
+	dir.Set(cmd)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list refs with output %q", string(out))
+	}
+
+	refs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if oid, ref, ok := strings.Cut(line, " "); ok {
+			refs[ref] = oid
+		}
+	}
+
+	updates := mercurialRefUpdates(refs)
+	if len(updates) == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = strings.NewReader(strings.Join(updates, "\n") + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to update refs with output %q", string(out))
+	}
+	return nil
+}
+
+// mercurialRefUpdates returns the "git update-ref --stdin" instructions which
+// make refs/heads and refs/tags in refs mirror the refs fetched from
+// git-cinnabar. refs maps ref names to object IDs.
+//
+// Named branches take precedence over bookmarks of the same name.
+func mercurialRefUpdates(refs map[string]string) []string {
+	want := map[string]string{}
+	bookmarks := map[string]string{}
+	for ref, oid := range refs {
+		switch {
+		case strings.HasPrefix(ref, "refs/hg/heads/branches/") && strings.HasSuffix(ref, "/tip"):
+			branch := strings.TrimSuffix(strings.TrimPrefix(ref, "refs/hg/heads/branches/"), "/tip")
+			if branch == "default" {
+				branch = "master"
+			}
+			want["refs/heads/"+branch] = oid
+		case strings.HasPrefix(ref, "refs/hg/heads/bookmarks/"):
+			bookmark := strings.TrimPrefix(ref, "refs/hg/heads/bookmarks/")
+			// "@" is the bookmark checked out by default. In Git it is a
+			// shorthand for HEAD, so we leave it out.
+			if bookmark != "@" {
+				bookmarks["refs/heads/"+bookmark] = oid
+			}
+		case strings.HasPrefix(ref, "refs/hg/tags/"):
+			want["refs/tags/"+strings.TrimPrefix(ref, "refs/hg/tags/")] = oid
+		}
+	}
+	for ref, oid := range bookmarks {
+		if _, ok := want[ref]; !ok {
+			want[ref] = oid
+		}
+	}
+
+	var updates []string
+	for ref, oid := range want {
+		if refs[ref] != oid {
+			updates = append(updates, "update "+ref+" "+oid)
+		}
+	}
+	for ref := range refs {
+		if strings.HasPrefix(ref, "refs/hg/") {
+			continue
+		}
+		if _, ok := want[ref]; !ok {
+			updates = append(updates, "delete "+ref)
+		}
+	}
+	sort.Strings(updates)
+	return updates
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestMercurialRefUpdates(t *testing.T) {
+	refs := map[string]string{
+		"refs/hg/heads/branches/default/tip": "a",
+		"refs/hg/heads/branches/stable/tip":  "b",
+		"refs/hg/heads/bookmarks/feature":    "c",
+		"refs/hg/heads/bookmarks/stable":     "d",
+		"refs/hg/heads/bookmarks/@":          "a",
+		"refs/hg/tags/v1":                    "e",
+		"refs/heads/master":                  "0",
+		"refs/heads/stable":                  "b",
+		"refs/heads/deleted":                 "f",
+		"refs/tags/v0":                       "g",
+	}
+	want := []string{
+		"delete refs/heads/deleted",
+		"delete refs/tags/v0",
+		"update refs/heads/feature c",
+		"update refs/heads/master a",
+		"update refs/tags/v1 e",
+	}
+	if diff := cmp.Diff(want, mercurialRefUpdates(refs)); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMercurialSyncer(t *testing.T) {
+	if _, err := exec.LookPath("hg"); err != nil {
+		t.Skip("hg not found in $PATH")
+	}
+	if err := exec.Command("git", "cinnabar", "--version").Run(); err != nil {
+		t.Skip("git-cinnabar is not installed")
+	}
+
+	ctx := context.Background()
+	root := t.TempDir()
+	hgDir := filepath.Join(root, "hg")
+
+	hg := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("hg", append([]string{"--config", "ui.username=Jane Doe <jane@example.com>"}, args...)...)
+		cmd.Dir = hgDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("hg %v: %s\n%s", args, err, out)
+		}
+	}
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(hgDir, "README"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(hgDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	hg("init")
+	writeFile("hello\n")
+	hg("commit", "-A", "-m", "add README")
+	hg("tag", "v1")
+	hg("bookmark", "feature")
+	hg("branch", "stable")
+	writeFile("stable\n")
+	hg("commit", "-m", "stable")
+	hg("update", "default")
+
+	remoteURL, err := vcs.ParseURL("file://" + hgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &MercurialSyncer{}
+
+	if err := s.IsCloneable(ctx, remoteURL); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := GitDir(filepath.Join(root, "repo", ".git"))
+	cmd, err := s.CloneCommand(ctx, remoteURL, string(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		dir.Set(cmd)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	refs := func() string {
+		return git("for-each-ref", "--format=%(refname)", "refs/heads/", "refs/tags/")
+	}
+
+	if diff := cmp.Diff("refs/heads/feature\nrefs/heads/master\nrefs/heads/stable\nrefs/tags/v1", refs()); diff != "" {
+		t.Fatalf("refs mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := git("log", "-1", "--format=%an <%ae>", "stable"), "Jane Doe <jane@example.com>"; got != want {
+		t.Fatalf("author: got %q, want %q", got, want)
+	}
+
+	// Fetch picks up new changesets and deleted bookmarks.
+	writeFile("hello world\n")
+	hg("commit", "-m", "update README")
+	hg("bookmark", "--delete", "feature")
+
+	if err := s.Fetch(ctx, remoteURL, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("refs/heads/master\nrefs/heads/stable\nrefs/tags/v1", refs()); diff != "" {
+		t.Fatalf("refs mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := git("log", "-1", "--format=%s", "master"), "update README"; got != want {
+		t.Fatalf("subject: got %q, want %q", got, want)
+	}
+}
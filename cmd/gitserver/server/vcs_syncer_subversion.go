@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// SubversionSyncer is a syncer for Subversion repositories. Repositories are
+// converted to Git with git-svn, which fetches new revisions incrementally.
+//
+// git-svn writes the branches it imports to refs/remotes/svn/. After each
+// fetch we mirror them to refs/heads and refs/tags, with the trunk becoming
+// "master".
+type SubversionSyncer struct {
+	// Trunk, Branches and Tags are the paths of the trunk, the branches and
+	// the tags relative to the repository URL. If all of them are empty,
+	// the whole repository is imported as "master".
+	Trunk    string
+	Branches string
+	Tags     string
+
+	// Authors maps Subversion usernames to Git authors of the form "Name
+	// <email>".
+	Authors map[string]string
+}
+
+// NewSubversionSyncer returns a SubversionSyncer for the repositories of the
+// connection c. If c has no layout, the standard layout (trunk, branches,
+// tags) is used.
+func NewSubversionSyncer(c *schema.SubversionConnection) *SubversionSyncer {
+	s := &SubversionSyncer{
+		Trunk:    "trunk",
+		Branches: "branches",
+		Tags:     "tags",
+		Authors:  c.Authors,
+	}
+	if c.Layout != nil {
+		s.Trunk = c.Layout.Trunk
+		s.Branches = c.Layout.Branches
+		s.Tags = c.Layout.Tags
+	}
+	return s
+}
+
+func (s *SubversionSyncer) Type() string {
+	return "subversion"
+}
+
+// IsCloneable checks to see if the Subversion repository at remoteURL exists
+// and is accessible.
+func (s *SubversionSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	u, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	args := []string{"info", "--non-interactive", "--no-auth-cache"}
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+	if password != "" {
+		args = append(args, "--password-from-stdin")
+	}
+	args = append(args, "--", u)
+
+	cmd := exec.CommandContext(ctx, "svn", args...)
+	cmd.Stdin = strings.NewReader(password + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		return errors.Wrapf(err, "svn info failed with output %q", newURLRedactor(remoteURL).redact(string(out)))
+	}
+	return nil
+}
+
+// CloneCommand initializes a bare Git repository at tmpPath configured to
+// track the Subversion repository at remoteURL, and converts it.
+//
+// Like vcsPackagesSyncer the conversion happens as part of CloneCommand, since
+// it is made of several commands. The returned command is a no-op.
+func (s *SubversionSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+	dir := GitDir(tmpPath)
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to init repository with output %q", string(output))
+	}
+
+	u, _, _ := decomposeSubversionRemoteURL(remoteURL)
+	// --no-minimize-url keeps git-svn from connecting to the server to find
+	// the repository root. We only want to track the repository at u.
+	args := []string{"svn", "init", "--prefix=svn/", "--no-minimize-url"}
+	if s.Trunk != "" {
+		args = append(args, "--trunk="+s.Trunk)
+	}
+	if s.Branches != "" {
+		args = append(args, "--branches="+s.Branches)
+	}
+	if s.Tags != "" {
+		args = append(args, "--tags="+s.Tags)
+	}
+	args = append(args, "--", u)
+
+	cmd = exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_ASKPASS=true", "GIT_TERMINAL_PROMPT=0")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to init git-svn with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	if err := s.Fetch(ctx, remoteURL, dir, ""); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert repository")
+	}
+
+	// no-op command to satisfy VCSSyncer interface, see docstring for more details.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
+
+// Fetch fetches new revisions of the Subversion repository and updates the
+// branches and tags of the Git repository.
+func (s *SubversionSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	_, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	// We rewrite these files on every fetch since the configuration may have
+	// changed since the last one. Authors only apply to new revisions.
+	authorsFile, authorsProg, askPass := dir.Path("svn-authors"), dir.Path("svn-authors-prog"), dir.Path("svn-askpass")
+	if err := os.WriteFile(authorsFile, subversionAuthorsFile(s.Authors), 0600); err != nil {
+		return errors.Wrap(err, "write authors file")
+	}
+	// git-svn calls authors-prog for users missing from the authors file.
+	if err := os.WriteFile(authorsProg, []byte("#!/bin/sh\nprintf '%s <%s>\\n' \"$1\" \"$1\"\n"), 0700); err != nil {
+		return errors.Wrap(err, "write authors program")
+	}
+	// git-svn asks for passwords with GIT_ASKPASS. The password is passed in
+	// the environment to keep it off the disk and the command line.
+	if err := os.WriteFile(askPass, []byte("#!/bin/sh\nprintf '%s\\n' \"$SRC_SVN_PASSWORD\"\n"), 0700); err != nil {
+		return errors.Wrap(err, "write askpass program")
+	}
+
+	args := []string{"svn", "fetch", "--authors-file=" + authorsFile, "--authors-prog=" + authorsProg}
+	if username != "" {
+		args = append(args, "--username="+username)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if password != "" {
+		cmd.Env = append(cmd.Env, "GIT_ASKPASS="+askPass, "SRC_SVN_PASSWORD="+password)
+	} else {
+		cmd.Env = append(cmd.Env, "GIT_ASKPASS=true")
+	}
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+
+	return mirrorSubversionRefs(ctx, dir)
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote
+// of a Subversion repository.
+func (s *SubversionSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (*exec.Cmd, error) {
+	// Like for Perforce there is no remote. The default branch is "master".
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// decomposeSubversionRemoteURL returns remoteURL without credentials, as well
+// as the credentials.
+func decomposeSubversionRemoteURL(remoteURL *vcs.URL) (u, username, password string) {
+	stripped := remoteURL.URL
+	if stripped.User != nil {
+		username = stripped.User.Username()
+		password, _ = stripped.User.Password()
+	}
+	stripped.User = nil
+	return stripped.String(), username, password
+}
+
+// subversionAuthorsFile returns the content of a git-svn authors file for
+// authors.
+func subversionAuthorsFile(authors map[string]string) []byte {
+	users := make([]string, 0, len(authors))
+	for user := range authors {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var b bytes.Buffer
+	for _, user := range users {
+		fmt.Fprintf(&b, "%s = %s\n", user, authors[user])
+	}
+	return b.Bytes()
+}
+
+// mirrorSubversionRefs updates refs/heads and refs/tags in dir to match the
+// refs git-svn imported into refs/remotes/svn.
+func mirrorSubversionRefs(ctx context.Context, dir GitDir) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)", "refs/remotes/svn/", "refs/heads/", "refs/tags/")
+	dir.Set(cmd)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list refs with output %q", string(out))
+	}
+
+	refs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if oid, ref, ok := strings.Cut(line, " "); ok {
+			refs[ref] = oid
+		}
+	}
+
+	updates := subversionRefUpdates(refs)
+	if len(updates) == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	dir.Set(cmd)
+	cmd.Stdin = strings.NewReader(strings.Join(updates, "\n") + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to update refs with output %q", string(out))
+	}
+	return nil
+}
+
+// subversionRefUpdates returns the "git update-ref --stdin" instructions which
+// make refs/heads and refs/tags in refs mirror the refs imported by git-svn.
+// refs maps ref names to object IDs.
+func subversionRefUpdates(refs map[string]string) []string {
+	want := map[string]string{}
+	for ref, oid := range refs {
+		name := strings.TrimPrefix(ref, "refs/remotes/svn/")
+		if name == ref {
+			continue
+		}
+		switch {
+		case strings.Contains(name, "@"):
+			// git-svn keeps the history of replaced or deleted branches
+			// in refs named branch@revision.
+		case name == "trunk" || name == "git-svn":
+			// git-svn is the ref used without a layout.
+			want["refs/heads/master"] = oid
+		case strings.HasPrefix(name, "tags/"):
+			want["refs/tags/"+strings.TrimPrefix(name, "tags/")] = oid
+		default:
+			want["refs/heads/"+name] = oid
+		}
+	}
+
+	var updates []string
+	for ref, oid := range want {
+		if refs[ref] != oid {
+			updates = append(updates, "update "+ref+" "+oid)
+		}
+	}
+	for ref := range refs {
+		if strings.HasPrefix(ref, "refs/remotes/svn/") {
+			continue
+		}
+		if _, ok := want[ref]; !ok {
+			updates = append(updates, "delete "+ref)
+		}
+	}
+	sort.Strings(updates)
+	return updates
+}
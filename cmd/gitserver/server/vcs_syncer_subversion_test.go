@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestSubversionRefUpdates(t *testing.T) {
+	refs := map[string]string{
+		"refs/remotes/svn/trunk":         "a",
+		"refs/remotes/svn/feature":       "b",
+		"refs/remotes/svn/feature@12":    "c",
+		"refs/remotes/svn/tags/v1":       "d",
+		"refs/remotes/svn/tags/v2@30":    "g",
+		"refs/remotes/svn/release/1.0.x": "h",
+		"refs/heads/master":              "0",
+		"refs/heads/feature":             "b",
+		"refs/heads/deleted":             "e",
+		"refs/tags/v0":                   "f",
+	}
+	want := []string{
+		"delete refs/heads/deleted",
+		"delete refs/tags/v0",
+		"update refs/heads/master a",
+		"update refs/heads/release/1.0.x h",
+		"update refs/tags/v1 d",
+	}
+	if diff := cmp.Diff(want, subversionRefUpdates(refs)); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without a layout git-svn imports the repository as "git-svn".
+	got := subversionRefUpdates(map[string]string{"refs/remotes/svn/git-svn": "a"})
+	if diff := cmp.Diff([]string{"update refs/heads/master a"}, got); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSubversionAuthorsFile(t *testing.T) {
+	got := string(subversionAuthorsFile(map[string]string{
+		"zoe":  "Zoe <zoe@example.com>",
+		"jdoe": "Jane Doe <jane@example.com>",
+	}))
+	want := "jdoe = Jane Doe <jane@example.com>\nzoe = Zoe <zoe@example.com>\n"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestSubversionSyncer(t *testing.T) {
+	for _, bin := range []string{"svn", "svnadmin"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s not found in $PATH", bin)
+		}
+	}
+	if err := exec.Command("git", "svn", "--version").Run(); err != nil {
+		t.Skip("git-svn is not installed")
+	}
+
+	ctx := context.Background()
+	root := t.TempDir()
+	svnDir := filepath.Join(root, "svn")
+	checkout := filepath.Join(root, "checkout")
+	svnURL := "file://" + svnDir
+
+	svn := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("svn", append([]string{"--non-interactive"}, args...)...)
+		cmd.Dir = checkout
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("svn %v: %s\n%s", args, err, out)
+		}
+	}
+
+	if out, err := exec.Command("svnadmin", "create", svnDir).CombinedOutput(); err != nil {
+		t.Fatalf("svnadmin create: %s\n%s", err, out)
+	}
+	if err := os.Mkdir(checkout, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	svn("mkdir", "--parents", "-m", "layout", "--username", "jdoe", svnURL+"/trunk", svnURL+"/branches", svnURL+"/tags")
+	svn("checkout", svnURL+"/trunk", ".")
+	if err := os.WriteFile(filepath.Join(checkout, "README"), []byte("hello\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	svn("add", "README")
+	svn("commit", "-m", "add README", "--username", "jdoe")
+	svn("copy", "-m", "branch", "--username", "jdoe", svnURL+"/trunk", svnURL+"/branches/feature")
+	svn("copy", "-m", "tag", "--username", "jdoe", svnURL+"/trunk", svnURL+"/tags/v1")
+
+	remoteURL, err := vcs.ParseURL(svnURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &SubversionSyncer{
+		Trunk:    "trunk",
+		Branches: "branches",
+		Tags:     "tags",
+		Authors:  map[string]string{"jdoe": "Jane Doe <jane@example.com>"},
+	}
+
+	if err := s.IsCloneable(ctx, remoteURL); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := GitDir(filepath.Join(root, "repo", ".git"))
+	cmd, err := s.CloneCommand(ctx, remoteURL, string(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		dir.Set(cmd)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	refs := func() string {
+		return git("for-each-ref", "--format=%(refname)", "refs/heads/", "refs/tags/")
+	}
+
+	if diff := cmp.Diff("refs/heads/feature\nrefs/heads/master\nrefs/tags/v1", refs()); diff != "" {
+		t.Fatalf("refs mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := git("log", "-1", "--format=%an <%ae>", "master"), "Jane Doe <jane@example.com>"; got != want {
+		t.Fatalf("author: got %q, want %q", got, want)
+	}
+
+	// Fetch picks up new revisions and deleted branches.
+	if err := os.WriteFile(filepath.Join(checkout, "README"), []byte("hello world\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	svn("commit", "-m", "update README", "--username", "bob")
+	svn("delete", "-m", "delete branch", "--username", "jdoe", svnURL+"/branches/feature")
+
+	if err := s.Fetch(ctx, remoteURL, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("refs/heads/master\nrefs/tags/v1", refs()); diff != "" {
+		t.Fatalf("refs mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := git("log", "-1", "--format=%s", "master"), "update README"; got != want {
+		t.Fatalf("subject: got %q, want %q", got, want)
+	}
+	// bob is not in the authors file.
+	if got, want := git("log", "-1", "--format=%an <%ae>", "master"), "bob <bob>"; got != want {
+		t.Fatalf("author: got %q, want %q", got, want)
+	}
+}
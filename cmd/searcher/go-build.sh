@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+
+# This script builds the searcher go binary.
+# Requires a single argument which is the path to the target bindir.
+#
+# To test you can run
+#
+#   VERSION=test ./cmd/searcher/go-build.sh /tmp
+
+cd "$(dirname "${BASH_SOURCE[0]}")/../.."
+set -eu
+
+OUTPUT="${1:?no output path provided}"
+
+echo "--- docker searcher build"
+
+# Required due to use of RUN --mount=type=cache in Dockerfile.
+export DOCKER_BUILDKIT=1
+
+docker build -f cmd/searcher/Dockerfile -t searcher-build "$(pwd)" \
+  --target=searcher-build \
+  --progress=plain \
+  --build-arg VERSION \
+  --build-arg PKG="${PKG:-github.com/sourcegraph/sourcegraph/cmd/searcher}"
+
+docker cp "$(docker create --rm searcher-build)":/searcher "$OUTPUT/searcher"
@@ -0,0 +1,399 @@
+package search
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/zoekt"
+	zoektquery "github.com/sourcegraph/zoekt/query"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ephemeralShards builds and serves zoekt shards for repo@commits which are
+// not indexed by zoekt but are searched frequently, such as long-lived
+// branches. Once a repo@commit has been searched EphemeralShardThreshold
+// times we build a shard from its archive in the background and answer
+// subsequent searches with it.
+//
+// Shards are stored in a diskcache next to the archives and evicted with the
+// same LRU strategy. Like the archives they can be lost at any time, so we
+// only serve shards built by this process and clear out the directory on
+// startup.
+type ephemeralShards struct {
+	threshold int
+
+	// cache is the disk backed cache of shards.
+	cache diskcache.Store
+
+	// buildLimiter limits the number of concurrent shard builds.
+	buildLimiter chan struct{}
+
+	mu sync.Mutex
+	// counts is the number of searches per archive key which have not been
+	// served by a shard. It is bounded so we only remember recent searches.
+	counts *lru.Cache
+	// building is the set of archive keys we are building a shard for.
+	building map[string]struct{}
+	// shards maps archive keys to the shards we can search.
+	shards map[string]*ephemeralShard
+}
+
+// ephemeralShard is a zoekt shard on disk.
+type ephemeralShard struct {
+	zoekt.Searcher
+	path string
+	wg   sync.WaitGroup // ensures the shard is not closed while in use
+}
+
+// Close signals that the caller is done searching the shard. It does not
+// close the underlying searcher, that only happens on eviction.
+func (sh *ephemeralShard) Close() {
+	sh.wg.Done()
+}
+
+// maxEphemeralShardCounts is the number of repo@commits we track searches
+// for.
+const maxEphemeralShardCounts = 10000
+
+func newEphemeralShards(dir string, threshold int, observationContext *observation.Context) *ephemeralShards {
+	counts, err := lru.New(maxEphemeralShardCounts)
+	if err != nil {
+		// Only returns an error for a non-positive size.
+		panic(err)
+	}
+
+	s := &ephemeralShards{
+		threshold:    threshold,
+		buildLimiter: make(chan struct{}, 2),
+		counts:       counts,
+		building:     map[string]struct{}{},
+		shards:       map[string]*ephemeralShard{},
+	}
+
+	// Shards from a previous run are not in shards, so would only use up
+	// disk until they are evicted.
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0700)
+
+	s.cache = diskcache.NewStore(dir, "ephemeral-shards",
+		diskcache.WithBeforeEvict(s.delete),
+		diskcache.WithObservationContext(observationContext),
+	)
+
+	return s
+}
+
+// get returns the shard for key, or nil if it has not been built. The
+// returned shard MUST be Closed when it is no longer needed.
+func (s *ephemeralShards) get(key string) *ephemeralShard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sh, ok := s.shards[key]
+	if !ok {
+		return nil
+	}
+	sh.wg.Add(1)
+
+	// Update modified time so the shard is evicted in LRU order.
+	now := time.Now()
+	_ = os.Chtimes(sh.path, now, now)
+
+	return sh
+}
+
+// record notes a search for key which was not served by a shard. It returns
+// true if the caller should build a shard for key.
+func (s *ephemeralShards) record(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.building[key]; ok {
+		return false
+	}
+	if _, ok := s.shards[key]; ok {
+		return false
+	}
+
+	count := 1
+	if v, ok := s.counts.Get(key); ok {
+		count = v.(int) + 1
+	}
+	if count < s.threshold {
+		s.counts.Add(key, count)
+		return false
+	}
+
+	s.counts.Remove(key)
+	s.building[key] = struct{}{}
+	return true
+}
+
+// add makes sh available for searches of key.
+func (s *ephemeralShards) add(key string, sh *ephemeralShard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.building, key)
+	if sh != nil {
+		s.shards[key] = sh
+	}
+}
+
+// delete is called by the diskcache before it removes the shard at path.
+func (s *ephemeralShards) delete(path string, _ observation.TraceLogger) {
+	var evicted *ephemeralShard
+	s.mu.Lock()
+	for key, sh := range s.shards {
+		if sh.path == path {
+			evicted = sh
+			delete(s.shards, key)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if evicted == nil {
+		return
+	}
+
+	// Once removed from shards no new searches can start on the shard. We
+	// wait for the running ones without holding mu so that other searches
+	// are not blocked by eviction.
+	evicted.wg.Wait()
+	evicted.Searcher.Close()
+}
+
+// buildEphemeralShard builds the shard for the archive of repo at commit
+// stored under key. It is meant to be run in the background after record
+// returned true for key.
+func (s *Store) buildEphemeralShard(repo api.RepoName, commit api.CommitID, key string) {
+	var sh *ephemeralShard
+	defer func() { s.shards.add(key, sh) }()
+
+	s.shards.buildLimiter <- struct{}{}
+	defer func() { <-s.shards.buildLimiter }()
+
+	logger := s.Log.With(log.String("repo", string(repo)), log.String("commit", string(commit)))
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	sh, err := s.openEphemeralShard(ctx, repo, commit, key)
+	if err != nil {
+		metricEphemeralShardBuildFailed.Inc()
+		logger.Error("failed to build ephemeral shard", log.Error(err))
+		return
+	}
+
+	metricEphemeralShardBuildDuration.Observe(time.Since(start).Seconds())
+	logger.Debug("built ephemeral shard", log.String("path", sh.path), log.Duration("duration", time.Since(start)))
+}
+
+func (s *Store) openEphemeralShard(ctx context.Context, repo api.RepoName, commit api.CommitID, key string) (*ephemeralShard, error) {
+	zipPath, err := s.PrepareZip(ctx, repo, commit)
+	if err != nil {
+		return nil, err
+	}
+	zf, err := s.zipCache.Get(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zf.Close()
+
+	f, err := s.shards.cache.OpenWithPath(ctx, []string{key}, func(ctx context.Context, path string) error {
+		w, err := os.OpenFile(path, os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		err = writeEphemeralShard(w, zf, repo, commit)
+		if err1 := w.Close(); err == nil {
+			err = err1
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write shard")
+	}
+
+	// NewIndexFile takes ownership of f.File.
+	indexFile, err := zoekt.NewIndexFile(f.File)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open shard")
+	}
+	searcher, err := zoekt.NewSearcher(indexFile)
+	if err != nil {
+		indexFile.Close()
+		return nil, errors.Wrap(err, "failed to load shard")
+	}
+
+	return &ephemeralShard{Searcher: searcher, path: f.Path}, nil
+}
+
+// writeEphemeralShard writes a zoekt shard containing the files of zf to w.
+func writeEphemeralShard(w io.Writer, zf *zipFile, repo api.RepoName, commit api.CommitID) error {
+	b, err := zoekt.NewIndexBuilder(&zoekt.Repository{
+		Name:     string(repo),
+		Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: string(commit)}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range zf.Files {
+		f := &zf.Files[i]
+		if err := b.Add(zoekt.Document{
+			Name:     f.Name,
+			Content:  zf.DataFor(f),
+			Branches: []string{"HEAD"},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return b.Write(w)
+}
+
+// watchAndEvictEphemeralShards is like watchAndEvict, but for the ephemeral
+// shards.
+func (s *Store) watchAndEvictEphemeralShards() {
+	if s.MaxEphemeralShardCacheSizeBytes == 0 {
+		return
+	}
+
+	for {
+		time.Sleep(10 * time.Second)
+
+		stats, err := s.shards.cache.Evict(s.MaxEphemeralShardCacheSizeBytes)
+		if err != nil {
+			s.Log.Error("failed to Evict ephemeral shards", log.Error(err))
+			continue
+		}
+		metricEphemeralShardCacheSizeBytes.Set(float64(stats.CacheSize))
+		metricEphemeralShardEvictions.Add(float64(stats.Evicted))
+	}
+}
+
+// ephemeralShardSearch searches p on the ephemeral shard of p.Repo at
+// p.Commit. If ok is false the search was not run and the caller should
+// search the archive instead.
+func (s *Service) ephemeralShardSearch(ctx context.Context, p *protocol.Request, sender matchSender) (ok bool, err error) {
+	// Ensure we have initialized
+	s.Store.Start()
+
+	if s.Store.shards == nil || p.IsStructuralPat || p.IsNegated || p.Pattern == "" || !p.PatternMatchesContent {
+		return false, nil
+	}
+
+	q, err := ephemeralShardQuery(p)
+	if err != nil {
+		// We fallback to searching the archive, which will report errors
+		// in the pattern.
+		return false, nil
+	}
+
+	filter := newSearchableFilter(&conf.Get().SiteConfiguration)
+	key := archiveKey(p.Repo, p.Commit, filter, nil)
+
+	sh := s.Store.shards.get(key)
+	if sh == nil {
+		if s.Store.shards.record(key) {
+			go s.Store.buildEphemeralShard(p.Repo, p.Commit, key)
+		}
+		return false, nil
+	}
+	defer sh.Close()
+
+	k := zoektutil.ResultCountFactor(1, int32(p.Limit), false)
+	opts := zoektutil.SearchOpts(ctx, k, int32(p.Limit), nil)
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.MaxWallTime = time.Until(deadline) - 100*time.Millisecond
+	}
+
+	start := time.Now()
+	res, err := sh.Search(ctx, q, &opts)
+	if err != nil {
+		return false, errors.Wrap(err, "ephemeral shard search failed")
+	}
+	metricEphemeralShardSearches.Inc()
+
+	for _, fm := range res.Files {
+		sender.Send(protocol.FileMatch{
+			Path:         fm.FileName,
+			ChunkMatches: zoektChunkMatches(fm),
+		})
+	}
+
+	// Zoekt stops searching once it found enough matches or ran out of time,
+	// which the archive search reports as hitting the limit.
+	timedOut := ctx.Err() != nil || (opts.MaxWallTime > 0 && time.Since(start) >= opts.MaxWallTime)
+	if res.Stats.FilesSkipped+res.Stats.ShardsSkipped > 0 || timedOut {
+		sender.SetLimitHit()
+	}
+
+	return true, nil
+}
+
+// ephemeralShardQuery returns the zoekt query to run against the ephemeral
+// shard for p.
+func ephemeralShardQuery(p *protocol.Request) (zoektquery.Q, error) {
+	q, err := zoektCompile(&p.PatternInfo)
+	if err != nil {
+		return nil, err
+	}
+	if !p.PatternMatchesPath {
+		return q, nil
+	}
+
+	// A regexp which is neither restricted to content nor file names matches
+	// both, which is the behaviour of PatternMatchesPath. zoektChunkMatches
+	// drops the file name matches, so a file which only matches on its path
+	// is sent without chunk matches like regexSearch does.
+	return zoektquery.Map(q, func(q zoektquery.Q) zoektquery.Q {
+		re, ok := q.(*zoektquery.Regexp)
+		if !ok || !re.Content {
+			return q
+		}
+		cp := *re
+		cp.Content = false
+		return &cp
+	}), nil
+}
+
+var (
+	metricEphemeralShardSearches = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "searcher_ephemeral_shard_searches_total",
+		Help: "The total number of searches served by an ephemeral zoekt shard.",
+	})
+	metricEphemeralShardBuildFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "searcher_ephemeral_shard_build_failed",
+		Help: "The total number of ephemeral zoekt shard builds that failed.",
+	})
+	metricEphemeralShardBuildDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "searcher_ephemeral_shard_build_duration_seconds",
+		Help:    "Observes the duration to build an ephemeral zoekt shard.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 12),
+	})
+	metricEphemeralShardCacheSizeBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "searcher_ephemeral_shard_cache_size_bytes",
+		Help: "The total size of ephemeral zoekt shards on disk.",
+	})
+	metricEphemeralShardEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "searcher_ephemeral_shard_evictions",
+		Help: "The total number of ephemeral zoekt shards evicted from the cache.",
+	})
+)
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestEphemeralShardSearch(t *testing.T) {
+	files := map[string]string{
+		"README.md": "# Hello World\n\nHello world example in go\n",
+		"main.go":   "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello world\")\n}\n",
+		"hello.txt": "nothing to see here\n",
+	}
+
+	s := tmpStore(t)
+	s.FetchTar = func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+		return tarFiles(t, files), nil
+	}
+	s.EphemeralShardThreshold = 2
+	s.EphemeralShardPath = t.TempDir()
+
+	svc := &Service{Store: s, Log: logtest.Scoped(t)}
+
+	p := protocol.Request{
+		Repo:   "foo",
+		Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+		PatternInfo: protocol.PatternInfo{
+			Pattern:               "world",
+			PatternMatchesContent: true,
+			PatternMatchesPath:    true,
+		},
+		FetchTimeout: "10s",
+	}
+
+	search := func() []protocol.FileMatch {
+		ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 100)
+		defer cancel()
+		if err := svc.search(ctx, &p, sender); err != nil {
+			t.Fatal(err)
+		}
+		sort.Slice(sender.collected, func(i, j int) bool {
+			return sender.collected[i].Path < sender.collected[j].Path
+		})
+		return sender.collected
+	}
+
+	// The first two searches scan the archive, the second one triggers
+	// building the shard.
+	want := search()
+	if got := search(); !cmp.Equal(want, got) {
+		t.Fatalf("mismatch (-want, +got):\n%s", cmp.Diff(want, got))
+	}
+
+	key := archiveKey(p.Repo, p.Commit, newSearchableFilter(&schema.SiteConfiguration{}), nil)
+	built := false
+	for i := 0; i < 500; i++ {
+		if sh := s.shards.get(key); sh != nil {
+			sh.Close()
+			built = true
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !built {
+		t.Fatal("expected ephemeral shard to be built")
+	}
+
+	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 100)
+	defer cancel()
+	ok, err := svc.ephemeralShardSearch(ctx, &p, sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected search to be served by the ephemeral shard")
+	}
+
+	got := search()
+	if paths := matchPaths(got); !cmp.Equal(matchPaths(want), paths) {
+		t.Fatalf("mismatch (-want, +got):\n%s", cmp.Diff(matchPaths(want), paths))
+	}
+}
+
+func TestEphemeralShardsRecord(t *testing.T) {
+	s := newEphemeralShards(t.TempDir(), 3, &observation.TestContext)
+
+	for i, want := range []bool{false, false, true, false, false} {
+		if got := s.record("a"); got != want {
+			t.Fatalf("record %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	// Once the build finished without a shard we start counting again.
+	s.add("a", nil)
+	for i, want := range []bool{false, false, true} {
+		if got := s.record("a"); got != want {
+			t.Fatalf("record after failed build %d: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func matchPaths(fms []protocol.FileMatch) []string {
+	var paths []string
+	for _, fm := range fms {
+		paths = append(paths, fm.Path)
+	}
+	return paths
+}
+
+func tarFiles(t *testing.T, files map[string]string) io.ReadCloser {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for name, body := range files {
+		if err := w.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0600,
+			Size: int64(len(body)),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return io.NopCloser(&buf)
+}
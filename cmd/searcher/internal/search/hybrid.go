@@ -137,71 +137,9 @@ func zoektSearchIgnorePaths(ctx context.Context, client zoekt.Streamer, p *proto
 			return false, nil
 		}
 
-		cms := make([]protocol.ChunkMatch, 0, len(fm.ChunkMatches))
-		for _, l := range fm.LineMatches {
-			if l.FileName {
-				continue
-			}
-
-			for _, m := range l.LineFragments {
-				runeOffset := utf8.RuneCount(l.Line[:m.LineOffset])
-				runeLength := utf8.RuneCount(l.Line[m.LineOffset : m.LineOffset+m.MatchLength])
-
-				cms = append(cms, protocol.ChunkMatch{
-					Content: string(l.Line),
-					// zoekt line numbers are 1-based rather than 0-based so subtract 1
-					ContentStart: protocol.Location{
-						Offset: int32(l.LineStart),
-						Line:   int32(l.LineNumber - 1),
-						Column: 0,
-					},
-					Ranges: []protocol.Range{{
-						Start: protocol.Location{
-							Offset: int32(m.Offset),
-							Line:   int32(l.LineNumber - 1),
-							Column: int32(runeOffset),
-						},
-						End: protocol.Location{
-							Offset: int32(m.Offset) + int32(m.MatchLength),
-							Line:   int32(l.LineNumber - 1),
-							Column: int32(runeOffset + runeLength),
-						},
-					}},
-				})
-			}
-		}
-
-		for _, cm := range fm.ChunkMatches {
-			ranges := make([]protocol.Range, 0, len(cm.Ranges))
-			for _, r := range cm.Ranges {
-				ranges = append(ranges, protocol.Range{
-					Start: protocol.Location{
-						Offset: int32(r.Start.ByteOffset),
-						Line:   int32(r.Start.LineNumber - 1),
-						Column: int32(r.Start.Column - 1),
-					},
-					End: protocol.Location{
-						Offset: int32(r.End.ByteOffset),
-						Line:   int32(r.End.LineNumber - 1),
-						Column: int32(r.End.Column - 1),
-					},
-				})
-			}
-
-			cms = append(cms, protocol.ChunkMatch{
-				Content: string(cm.Content),
-				ContentStart: protocol.Location{
-					Offset: int32(cm.ContentStart.ByteOffset),
-					Line:   int32(cm.ContentStart.LineNumber) - 1,
-					Column: int32(cm.ContentStart.Column) - 1,
-				},
-				Ranges: ranges,
-			})
-		}
-
 		sender.Send(protocol.FileMatch{
 			Path:         fm.FileName,
-			ChunkMatches: cms,
+			ChunkMatches: zoektChunkMatches(fm),
 		})
 	}
 
@@ -222,6 +160,74 @@ func zoektSearchIgnorePaths(ctx context.Context, client zoekt.Streamer, p *proto
 	return true, nil
 }
 
+// zoektChunkMatches converts the matches zoekt found in fm into chunk matches.
+// Matches on the file name are dropped.
+func zoektChunkMatches(fm zoekt.FileMatch) []protocol.ChunkMatch {
+	cms := make([]protocol.ChunkMatch, 0, len(fm.ChunkMatches))
+	for _, l := range fm.LineMatches {
+		if l.FileName {
+			continue
+		}
+
+		for _, m := range l.LineFragments {
+			runeOffset := utf8.RuneCount(l.Line[:m.LineOffset])
+			runeLength := utf8.RuneCount(l.Line[m.LineOffset : m.LineOffset+m.MatchLength])
+
+			cms = append(cms, protocol.ChunkMatch{
+				Content: string(l.Line),
+				// zoekt line numbers are 1-based rather than 0-based so subtract 1
+				ContentStart: protocol.Location{
+					Offset: int32(l.LineStart),
+					Line:   int32(l.LineNumber - 1),
+					Column: 0,
+				},
+				Ranges: []protocol.Range{{
+					Start: protocol.Location{
+						Offset: int32(m.Offset),
+						Line:   int32(l.LineNumber - 1),
+						Column: int32(runeOffset),
+					},
+					End: protocol.Location{
+						Offset: int32(m.Offset) + int32(m.MatchLength),
+						Line:   int32(l.LineNumber - 1),
+						Column: int32(runeOffset + runeLength),
+					},
+				}},
+			})
+		}
+	}
+
+	for _, cm := range fm.ChunkMatches {
+		ranges := make([]protocol.Range, 0, len(cm.Ranges))
+		for _, r := range cm.Ranges {
+			ranges = append(ranges, protocol.Range{
+				Start: protocol.Location{
+					Offset: int32(r.Start.ByteOffset),
+					Line:   int32(r.Start.LineNumber - 1),
+					Column: int32(r.Start.Column - 1),
+				},
+				End: protocol.Location{
+					Offset: int32(r.End.ByteOffset),
+					Line:   int32(r.End.LineNumber - 1),
+					Column: int32(r.End.Column - 1),
+				},
+			})
+		}
+
+		cms = append(cms, protocol.ChunkMatch{
+			Content: string(cm.Content),
+			ContentStart: protocol.Location{
+				Offset: int32(cm.ContentStart.ByteOffset),
+				Line:   int32(cm.ContentStart.LineNumber) - 1,
+				Column: int32(cm.ContentStart.Column) - 1,
+			},
+			Ranges: ranges,
+		})
+	}
+
+	return cms
+}
+
 // zoektCompile builds a text search zoekt query for p.
 //
 // This function should support the same features as the "compile" function,
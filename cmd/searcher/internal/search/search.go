@@ -214,6 +214,12 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender matchS
 		}
 	}
 
+	if ok, err := s.ephemeralShardSearch(ctx, p, sender); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
 	if p.FetchTimeout == "" {
 		p.FetchTimeout = "500ms"
 	}
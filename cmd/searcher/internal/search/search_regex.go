@@ -61,6 +61,10 @@ type readerGrep struct {
 	// re. It is the output of the longestLiteral function. It is only set if
 	// the regex has an empty LiteralPrefix.
 	literalSubstring []byte
+
+	// trigrams are the trigrams guaranteed to appear in any match found by
+	// re. They are used to skip files using the trigram index of a zipFile.
+	trigrams []uint32
 }
 
 // compile returns a readerGrep for matching p.
@@ -68,6 +72,7 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 	var (
 		re               *regexp.Regexp
 		literalSubstring []byte
+		trigrams         []uint32
 	)
 	if p.Pattern != "" {
 		expr := p.Pattern
@@ -110,16 +115,18 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 			return nil, err
 		}
 
+		ast, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		ast = ast.Simplify()
+
 		// Only use literalSubstring optimization if the regex engine doesn't
 		// have a prefix to use.
 		if pre, _ := re.LiteralPrefix(); pre == "" {
-			ast, err := syntax.Parse(expr, syntax.Perl)
-			if err != nil {
-				return nil, err
-			}
-			ast = ast.Simplify()
 			literalSubstring = []byte(longestLiteral(ast))
 		}
+		trigrams = queryTrigrams(ast)
 	}
 
 	pathOptions := pathmatch.CompileOptions{
@@ -136,6 +143,7 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		ignoreCase:       !p.IsCaseSensitive,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
+		trigrams:         trigrams,
 	}, nil
 }
 
@@ -147,6 +155,7 @@ func (rg *readerGrep) Copy() *readerGrep {
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
+		trigrams:         rg.trigrams,
 	}
 }
 
@@ -321,8 +330,17 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *zipFile, patternMatche
 		lastFileIdx   = atomic.NewInt32(-1)
 		filesSkipped  atomic.Uint32
 		filesSearched atomic.Uint32
+		filesPruned   atomic.Uint32
 	)
 
+	// The trigram index lets us skip files whose contents cannot match
+	// without reading them. It is nil while it is being built, in which case
+	// we search all files.
+	var trigrams *trigramIndex
+	if len(rg.trigrams) > 0 {
+		trigrams = zf.trigrams()
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	contextCanceled := atomic.NewBool(false)
@@ -354,9 +372,15 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *zipFile, patternMatche
 				filesSearched.Inc()
 
 				// process
-				fm, err := rg.FindZip(zf, f, sender.Remaining())
-				if err != nil {
-					return err
+				fm := protocol.FileMatch{Path: f.Name}
+				if trigrams == nil || trigrams.mayContain(idx, rg.trigrams) {
+					var err error
+					fm, err = rg.FindZip(zf, f, sender.Remaining())
+					if err != nil {
+						return err
+					}
+				} else {
+					filesPruned.Inc()
 				}
 				match := len(fm.ChunkMatches) > 0
 				if !match && patternMatchesPaths {
@@ -383,6 +407,7 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *zipFile, patternMatche
 	span.LogFields(
 		otlog.Int("filesSkipped", int(filesSkipped.Load())),
 		otlog.Int("filesSearched", int(filesSearched.Load())),
+		otlog.Int("filesPruned", int(filesPruned.Load())),
 	)
 
 	return err
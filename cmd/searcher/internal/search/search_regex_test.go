@@ -222,13 +222,51 @@ func benchSearchRegex(b *testing.B, p *protocol.Request) {
 	}
 	defer zf.Close()
 
+	// Compare searching with the trigram index against searching all files.
+	bruteForce := rg.Copy()
+	bruteForce.trigrams = nil
+	zf.trigramBuilding.Store(true)
+	zf.trigramIdx.Store(buildTrigramIndex(zf, nil))
+
+	for _, bench := range []struct {
+		name string
+		rg   *readerGrep
+	}{{"trigrams", rg}, {"bruteforce", bruteForce}} {
+		rg := bench.rg
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _, err := regexSearchBatch(ctx, rg, zf, 99999999, p.PatternMatchesContent, p.PatternMatchesPath, p.IsNegated)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBuildTrigramIndex(b *testing.B) {
+	if testing.Short() {
+		b.Skip("")
+	}
+	b.ReportAllocs()
+
+	path, err := githubStore.PrepareZip(context.Background(), "github.com/golang/go", "0ebaca6ba27534add5930a95acffa9acff182e2b")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var zc zipCache
+	zf, err := zc.Get(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer zf.Close()
+
 	b.ResetTimer()
 
 	for n := 0; n < b.N; n++ {
-		_, _, err := regexSearchBatch(ctx, rg, zf, 99999999, p.PatternMatchesContent, p.PatternMatchesPath, p.IsNegated)
-		if err != nil {
-			b.Fatal(err)
-		}
+		_ = buildTrigramIndex(zf, nil)
 	}
 }
 
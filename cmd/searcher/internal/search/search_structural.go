@@ -27,6 +27,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -300,6 +301,7 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 		IsRegExp:                     p.IsRegExp,
 		IsStructuralPat:              p.IsStructuralPat,
 		CombyRule:                    p.CombyRule,
+		StructuralEngine:             p.StructuralEngine,
 		IsWordMatch:                  p.IsWordMatch,
 		IsCaseSensitive:              p.IsCaseSensitive,
 		FileMatchLimit:               int32(p.Limit),
@@ -323,7 +325,8 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 	return nil
 }
 
-// filteredStructuralSearch filters the list of files with a regex search before passing the zip to comby
+// filteredStructuralSearch filters the list of files with a regex search before passing the zip to comby,
+// or the tree-sitter engine if the search selects it.
 func filteredStructuralSearch(ctx context.Context, zipPath string, zf *zipFile, p *protocol.PatternInfo, repo api.RepoName, sender matchSender) error {
 	// Make a copy of the pattern info to modify it to work for a regex search
 	rp := *p
@@ -353,6 +356,9 @@ func filteredStructuralSearch(ctx context.Context, zipPath string, zf *zipFile,
 		extensionHint = filepath.Ext(matchedPaths[0])
 	}
 
+	if p.StructuralEngine == query.StructuralEngineTreeSitter {
+		return treeSitterSearch(ctx, comby.ZipPath(zipPath), subset(matchedPaths), p.Pattern, p.CombyRule, p.Languages, repo, sender)
+	}
 	return structuralSearch(ctx, comby.ZipPath(zipPath), subset(matchedPaths), extensionHint, p.Pattern, p.CombyRule, p.Languages, repo, sender)
 }
 
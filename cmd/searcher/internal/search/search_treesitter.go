@@ -0,0 +1,473 @@
+package search
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/regexp"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// treeSitterSearch is the structural search engine selected with
+// engine:tree-sitter. Unlike comby it runs in process: it parses files with
+// the tree-sitter grammars of squirrel and matches the template against their
+// syntax trees. Files in languages without a grammar are skipped.
+//
+// It accepts the same inputs as structuralSearch and returns the same chunk
+// matches.
+func treeSitterSearch(ctx context.Context, inputType comby.Input, paths filePatterns, pattern, rule string, languages []string, repo api.RepoName, sender matchSender) (err error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "TreeSitterStructuralSearch")
+	span.SetTag("repo", repo)
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
+
+	if tar, ok := inputType.(comby.Tar); ok {
+		// The sender of the tar input blocks until we consume all of it,
+		// including when we return early.
+		defer func() {
+			for range tar.TarInputEventC {
+			}
+		}()
+	}
+
+	if rule != "" {
+		return errors.New("rules are not supported by the tree-sitter structural search engine")
+	}
+
+	t, err := parseTemplate(pattern)
+	if err != nil {
+		return err
+	}
+
+	parser, err := newTreeSitterParser(languages)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+
+	searchFile := func(path string, content []byte) error {
+		f, err := parser.parse(ctx, path, content)
+		if err != nil || f == nil {
+			return err
+		}
+		ranges, exhausted := t.match(f)
+		if exhausted {
+			// Matches may have been missed, so results are incomplete.
+			sender.SetLimitHit()
+		}
+		if len(ranges) == 0 {
+			return nil
+		}
+		sender.Send(protocol.FileMatch{
+			Path:         path,
+			ChunkMatches: chunksToMatches(content, chunkRanges(ranges, 0)),
+		})
+		return nil
+	}
+
+	switch input := inputType.(type) {
+	case comby.Tar:
+		for tb := range input.TarInputEventC {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := searchFile(tb.Header.Name, tb.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case comby.ZipPath:
+		zipReader, err := zip.OpenReader(string(input))
+		if err != nil {
+			return err
+		}
+		defer zipReader.Close()
+
+		include := func(string) bool { return true }
+		if v, ok := paths.(subset); ok {
+			set := make(map[string]struct{}, len(v))
+			for _, path := range v {
+				set[path] = struct{}{}
+			}
+			include = func(path string) bool {
+				_, ok := set[path]
+				return ok
+			}
+		}
+
+		searched := 0
+		for _, file := range zipReader.File {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if file.FileInfo().IsDir() || !include(file.Name) {
+				continue
+			}
+			content, err := readZipEntry(file)
+			if err != nil {
+				return err
+			}
+			if err := searchFile(file.Name, content); err != nil {
+				return err
+			}
+			searched++
+		}
+		span.LogFields(otlog.Int("files", searched))
+		return nil
+	}
+
+	return errors.New("input must be either a tar stream or a zip file for structural search")
+}
+
+func readZipEntry(file *zip.File) ([]byte, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// template is a parsed structural search pattern. It is a sequence of literal
+// text and holes, such as "foo(" :[args] ")".
+type template struct {
+	items []templateItem
+}
+
+// templateItem is either literal text or a hole.
+type templateItem struct {
+	// literal is the text of the item with whitespace normalized, see
+	// normalizeSpace. It is empty for holes.
+	literal string
+	hole    *hole
+}
+
+// hole matches a range of the syntax tree.
+type hole struct {
+	// name is the name of the hole. Holes with the same name must match the
+	// same text, except for the hole "_".
+	name string
+	// word is true for holes like :[[name]], which match a single identifier
+	// or keyword.
+	word bool
+	// re, if not nil, must match all of the text of the hole, for holes like
+	// :[name~regexp].
+	re *regexp.Regexp
+}
+
+var wordHoleRegexp = regexp.MustCompile(`^\w+$`)
+
+// parseTemplate parses pattern, which uses the hole syntax of comby: :[name],
+// :[_], :[[name]], :[name~regexp] and :[~regexp].
+func parseTemplate(pattern string) (*template, error) {
+	t := &template{}
+	addLiteral := func(s string) {
+		if s = normalizeSpace(s); s != "" {
+			t.items = append(t.items, templateItem{literal: s})
+		}
+	}
+
+	for {
+		i := strings.Index(pattern, ":[")
+		if i < 0 {
+			addLiteral(pattern)
+			return t, nil
+		}
+		addLiteral(pattern[:i])
+		h, n, err := parseHole(pattern[i:])
+		if err != nil {
+			return nil, err
+		}
+		t.items = append(t.items, templateItem{hole: h})
+		pattern = pattern[i+n:]
+	}
+}
+
+// parseHole parses the hole at the start of s and returns it along with its
+// length in s.
+func parseHole(s string) (*hole, int, error) {
+	// holeText is used in errors.
+	holeText := s
+	if i := strings.IndexByte(s, ']'); i >= 0 {
+		holeText = s[:i+1]
+	}
+
+	if strings.HasPrefix(s, ":[[") {
+		end := strings.Index(s, "]]")
+		if end < 0 || !isHoleName(s[3:end]) {
+			return nil, 0, errors.Errorf("invalid hole %q in structural search pattern", holeText)
+		}
+		return &hole{name: s[3:end], word: true}, end + 2, nil
+	}
+
+	i := len(":[")
+	for i < len(s) && isHoleNameByte(s[i]) {
+		i++
+	}
+	name := s[len(":["):i]
+
+	switch {
+	case i < len(s) && s[i] == ']' && name != "":
+		return &hole{name: name}, i + 1, nil
+
+	case i < len(s) && s[i] == '~':
+		if name == "" {
+			// Anonymous regexp holes like :[~regexp].
+			name = "_"
+		}
+		// The regexp ends at the first ] which is not part of the
+		// regexp itself.
+		depth := 0
+		for j := i + 1; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				j++
+			case '[':
+				depth++
+			case ']':
+				if depth > 0 {
+					depth--
+					continue
+				}
+				re, err := regexp.Compile("^(?:" + s[i+1:j] + ")$")
+				if err != nil {
+					return nil, 0, errors.Wrapf(err, "invalid regular expression of hole %q", name)
+				}
+				return &hole{name: name, re: re}, j + 1, nil
+			}
+		}
+	}
+
+	return nil, 0, errors.Errorf("unsupported hole %q in structural search pattern, supported holes are :[name], :[[name]] and :[name~regexp]", holeText)
+}
+
+func isHoleName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isHoleNameByte(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isHoleNameByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// normalizeSpace replaces runs of whitespace in s with a single space and
+// trims it, since whitespace between tokens is not significant.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// syntaxFile is the syntax tree of a file, reduced to what the matcher needs:
+// its leaves in order, without comments and whitespace, and for every node the
+// range of leaves it spans.
+type syntaxFile struct {
+	content []byte
+	leaves  []syntaxLeaf
+}
+
+type syntaxLeaf struct {
+	// start and end are the byte offsets of the leaf in the file, startLine
+	// and endLine its 0-based lines.
+	start, end         int
+	startLine, endLine int
+
+	// text is the text of the leaf with whitespace normalized.
+	text string
+
+	node *syntaxNode
+}
+
+type syntaxNode struct {
+	parent   *syntaxNode
+	children []*syntaxNode
+	// first and end are the range of leaves the node spans.
+	first, end int
+}
+
+// holeEnds returns the leaves a hole which starts at leaf pos may end at,
+// in increasing order. Holes match a sequence of sibling nodes, so that
+// they do not match unbalanced code like the "a) + (b" of "(a) + (b)".
+func (f *syntaxFile) holeEnds(pos int) []int {
+	seen := map[int]struct{}{}
+	var ends []int
+	for n := f.leaves[pos].node; n != nil && n.first == pos; n = n.parent {
+		if n.parent == nil {
+			ends = append(ends, n.end)
+			continue
+		}
+		i := 0
+		for n.parent.children[i] != n {
+			i++
+		}
+		for _, sibling := range n.parent.children[i:] {
+			if sibling.first == sibling.end {
+				continue // Only comments or whitespace.
+			}
+			if _, ok := seen[sibling.end]; !ok {
+				seen[sibling.end] = struct{}{}
+				ends = append(ends, sibling.end)
+			}
+		}
+	}
+	sort.Ints(ends)
+	return ends
+}
+
+// text returns the text of the leaves [start, end) with whitespace normalized.
+func (f *syntaxFile) text(start, end int) string {
+	if start == end {
+		return ""
+	}
+	return normalizeSpace(string(f.content[f.leaves[start].start:f.leaves[end-1].end]))
+}
+
+// matchLiteral matches literal against the leaves starting at pos and returns
+// the position after them.
+func (f *syntaxFile) matchLiteral(literal string, pos int) (int, bool) {
+	for {
+		literal = strings.TrimPrefix(literal, " ")
+		if literal == "" {
+			return pos, true
+		}
+		if pos == len(f.leaves) || !strings.HasPrefix(literal, f.leaves[pos].text) {
+			return 0, false
+		}
+		literal = literal[len(f.leaves[pos].text):]
+		pos++
+	}
+}
+
+// maxMatchSteps bounds the backtracking of a single match attempt, so that
+// templates with many holes cannot stall the search. An attempt which runs out
+// of steps fails, even though it might have matched.
+const maxMatchSteps = 10000
+
+// match returns the ranges of the leftmost, non-overlapping matches of t in f.
+// exhausted is true if a match attempt ran out of steps, in which case matches
+// may be missing.
+func (t *template) match(f *syntaxFile) (ranges []protocol.Range, exhausted bool) {
+	for start := 0; start < len(f.leaves); {
+		m := &matcher{file: f, items: len(t.items), env: map[string]string{}}
+		end, ok := m.match(t.items, start)
+		exhausted = exhausted || m.steps > maxMatchSteps
+		if !ok || end == start {
+			start++
+			continue
+		}
+		ranges = append(ranges, protocol.Range{
+			Start: f.location(f.leaves[start].start, f.leaves[start].startLine),
+			End:   f.location(f.leaves[end-1].end, f.leaves[end-1].endLine),
+		})
+		start = end
+	}
+	return ranges, exhausted
+}
+
+func (f *syntaxFile) location(offset, line int) protocol.Location {
+	lineStart := offset
+	for lineStart > 0 && f.content[lineStart-1] != '\n' {
+		lineStart--
+	}
+	return protocol.Location{
+		Offset: int32(offset),
+		Line:   int32(line),
+		Column: int32(utf8.RuneCount(f.content[lineStart:offset])),
+	}
+}
+
+// matcher holds the state of a single match attempt.
+type matcher struct {
+	file *syntaxFile
+	// items is the number of items of the template.
+	items int
+	// env maps the names of holes to the text they matched.
+	env   map[string]string
+	steps int
+}
+
+// match matches items against the leaves starting at pos and returns the
+// position after the match. Holes match as few leaves as possible, except for
+// a hole at the end of the template, which matches as many as possible. Holes
+// at the start or end of the template match at least one leaf, so that matches
+// do not start or end with an empty hole.
+func (m *matcher) match(items []templateItem, pos int) (int, bool) {
+	if m.steps++; m.steps > maxMatchSteps {
+		return 0, false
+	}
+	if len(items) == 0 {
+		return pos, true
+	}
+
+	item := items[0]
+	if item.hole == nil {
+		next, ok := m.file.matchLiteral(item.literal, pos)
+		if !ok {
+			return 0, false
+		}
+		return m.match(items[1:], next)
+	}
+
+	ends := []int{pos}
+	if pos < len(m.file.leaves) {
+		ends = append(ends, m.file.holeEnds(pos)...)
+	}
+	if len(items) == 1 {
+		for i, j := 0, len(ends)-1; i < j; i, j = i+1, j-1 {
+			ends[i], ends[j] = ends[j], ends[i]
+		}
+	}
+	atEdge := len(items) == m.items || len(items) == 1
+
+	h := item.hole
+	for _, end := range ends {
+		if atEdge && end == pos {
+			continue
+		}
+		if h.word && (end != pos+1 || !wordHoleRegexp.MatchString(m.file.leaves[pos].text)) {
+			continue
+		}
+		text := m.file.text(pos, end)
+		if h.re != nil && !h.re.MatchString(text) {
+			continue
+		}
+		if h.name == "_" {
+			if next, ok := m.match(items[1:], end); ok {
+				return next, true
+			}
+			continue
+		}
+
+		bound, wasBound := m.env[h.name]
+		if wasBound && bound != text {
+			continue
+		}
+		m.env[h.name] = text
+		if next, ok := m.match(items[1:], end); ok {
+			return next, true
+		}
+		if !wasBound {
+			delete(m.env, h.name)
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,94 @@
+//go:build cgo
+
+package search
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/squirrel"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// treeSitterParser parses the files of a tree-sitter structural search. It is
+// not safe for concurrent use.
+type treeSitterParser struct {
+	parser *sitter.Parser
+	// language is the grammar of the lang: filter of the search, if any.
+	// Otherwise the grammar of each file is inferred from its path.
+	language *sitter.Language
+}
+
+func newTreeSitterParser(languages []string) (*treeSitterParser, error) {
+	var language *sitter.Language
+	if len(languages) > 0 {
+		// Like comby, we only apply the grammar of the first language.
+		language = squirrel.LanguageForName(languages[0])
+		if language == nil {
+			return nil, errors.Errorf("language %q is not supported by the tree-sitter structural search engine", languages[0])
+		}
+	}
+	return &treeSitterParser{parser: sitter.NewParser(), language: language}, nil
+}
+
+func (p *treeSitterParser) Close() {
+	p.parser.Close()
+}
+
+// parse returns the syntax tree of the file at path, or nil if there is no
+// grammar for it.
+func (p *treeSitterParser) parse(ctx context.Context, path string, content []byte) (*syntaxFile, error) {
+	language := p.language
+	if language == nil {
+		language = squirrel.LanguageForPath(path)
+	}
+	if language == nil {
+		return nil, nil
+	}
+
+	p.parser.SetLanguage(language)
+	tree, err := p.parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	defer tree.Close()
+
+	f := &syntaxFile{content: content}
+	f.add(tree.RootNode(), nil)
+	return f, nil
+}
+
+// add adds n and its descendants to f. Comments are dropped, and strings are
+// kept as a single leaf so that whitespace in them stays significant.
+func (f *syntaxFile) add(n *sitter.Node, parent *syntaxNode) {
+	typ := n.Type()
+	if strings.Contains(typ, "comment") {
+		return
+	}
+
+	node := &syntaxNode{parent: parent, first: len(f.leaves)}
+	if parent != nil {
+		parent.children = append(parent.children, node)
+	}
+
+	if n.ChildCount() == 0 || strings.Contains(typ, "string") {
+		start, end := int(n.StartByte()), int(n.EndByte())
+		if text := normalizeSpace(string(f.content[start:end])); text != "" {
+			f.leaves = append(f.leaves, syntaxLeaf{
+				start:     start,
+				end:       end,
+				startLine: int(n.StartPoint().Row),
+				endLine:   int(n.EndPoint().Row),
+				text:      text,
+				node:      node,
+			})
+		}
+	} else {
+		for i := 0; i < int(n.ChildCount()); i++ {
+			f.add(n.Child(i), node)
+		}
+	}
+	node.end = len(f.leaves)
+}
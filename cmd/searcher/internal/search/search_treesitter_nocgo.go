@@ -0,0 +1,23 @@
+//go:build !cgo
+
+package search
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// treeSitterParser is unavailable without cgo, since tree-sitter is written in
+// C. See search_treesitter_cgo.go.
+type treeSitterParser struct{}
+
+func newTreeSitterParser([]string) (*treeSitterParser, error) {
+	return nil, errors.New("the tree-sitter structural search engine is not available, searcher was built without cgo")
+}
+
+func (p *treeSitterParser) Close() {}
+
+func (p *treeSitterParser) parse(context.Context, string, []byte) (*syntaxFile, error) {
+	return nil, nil
+}
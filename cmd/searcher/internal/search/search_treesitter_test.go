@@ -0,0 +1,248 @@
+//go:build cgo
+
+package search
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/comby"
+)
+
+func TestTreeSitterSearch(t *testing.T) {
+	input := map[string]string{
+		"main.go": `package main
+
+func main() {
+	// foo(commented)
+	foo(a, b)
+	foo(bar(x),
+		y)
+	foo()
+	fmt.Println("foo(in a string)")
+}
+`,
+		"equal.go": `package main
+
+func f(a, b int) {
+	if a == a {
+	}
+	if a == b {
+	}
+}
+`,
+		"print.go": `package main
+
+func main() {
+	fmt.Println("a")
+	fmt.Sprintf("%d", 1)
+}
+`,
+		"script.py": `def f():
+    # foo(commented)
+    return foo(1,
+               2)
+`,
+		"rules":     `foo(name = "bar")`,
+		"README.md": `foo(unsupported)`,
+	}
+
+	zipData, err := createZip(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf := tempZipFileOnDisk(t, zipData)
+
+	cases := []struct {
+		name      string
+		pattern   string
+		paths     filePatterns
+		languages []string
+		want      []string
+	}{{
+		name:    "holes match balanced code across lines",
+		pattern: "foo(:[args])",
+		want: []string{
+			"main.go: foo(a, b)",
+			"main.go: foo(bar(x),\n\t\ty)",
+			"main.go: foo()",
+			"script.py: foo(1,\n               2)",
+		},
+	}, {
+		name:    "repeated holes match the same text",
+		pattern: "if :[x] == :[x] {",
+		want:    []string{"equal.go: if a == a {"},
+	}, {
+		name:    "regexp holes",
+		pattern: `fmt.:[fn~Sprint\w*](:[_])`,
+		want:    []string{`print.go: fmt.Sprintf("%d", 1)`},
+	}, {
+		name:    "word holes",
+		pattern: "fmt.:[[fn]](:[_])",
+		want: []string{
+			`main.go: fmt.Println("foo(in a string)")`,
+			`print.go: fmt.Println("a")`,
+			`print.go: fmt.Sprintf("%d", 1)`,
+		},
+	}, {
+		name:      "lang: selects the grammar",
+		pattern:   "foo(:[args])",
+		paths:     subset{"rules"},
+		languages: []string{"bazel"},
+		want:      []string{`rules: foo(name = "bar")`},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			paths := tc.paths
+			if paths == nil {
+				paths = all
+			}
+			ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
+			defer cancel()
+			err := treeSitterSearch(ctx, comby.ZipPath(zf), paths, tc.pattern, "", tc.languages, "repo_foo", sender)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, fm := range sender.collected {
+				for _, cm := range fm.ChunkMatches {
+					for _, m := range cm.MatchedContent() {
+						got = append(got, fm.Path+": "+m)
+					}
+				}
+			}
+			sort.Strings(got)
+			sort.Strings(tc.want)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTreeSitterSearchStepLimit(t *testing.T) {
+	// Matching the holes against the arguments backtracks through far more
+	// than maxMatchSteps combinations before the template fails to match.
+	var args []string
+	for i := 0; i < 100; i++ {
+		args = append(args, fmt.Sprintf("x%d", i))
+	}
+	content := "package main\n\nfunc main() {\n\tfoo(" + strings.Join(args, ", ") + ")\n}\n"
+
+	tarInputEventC := make(chan comby.TarInputEvent, 1)
+	tarInputEventC <- comby.TarInputEvent{
+		Header:  tar.Header{Name: "main.go", Mode: 0600, Size: int64(len(content))},
+		Content: []byte(content),
+	}
+	close(tarInputEventC)
+
+	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
+	defer cancel()
+	err := treeSitterSearch(ctx, comby.Tar{TarInputEventC: tarInputEventC}, all, "foo(:[a], :[b], :[c], :[d], missing)", "", nil, "repo_foo", sender)
+	require.NoError(t, err)
+	require.Empty(t, sender.collected)
+	require.True(t, sender.LimitHit(), "searches which may have missed matches report hitting the limit")
+}
+
+// TestTreeSitterSearchTarInput is TestTarInput for the tree-sitter engine,
+// which must return the same matches as comby.
+func TestTreeSitterSearchTarInput(t *testing.T) {
+	content := `
+func foo() {
+    fmt.Println("foo")
+}
+
+func bar() {
+    fmt.Println("bar")
+}
+`
+
+	tarInputEventC := make(chan comby.TarInputEvent, 1)
+	tarInputEventC <- comby.TarInputEvent{
+		Header: tar.Header{
+			Name: "main.go",
+			Mode: 0600,
+			Size: int64(len(content)),
+		},
+		Content: []byte(content),
+	}
+	close(tarInputEventC)
+
+	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
+	defer cancel()
+	err := treeSitterSearch(ctx, comby.Tar{TarInputEventC: tarInputEventC}, all, "{:[body]}", "", nil, "repo_foo", sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []protocol.FileMatch{{
+		Path: "main.go",
+		ChunkMatches: []protocol.ChunkMatch{{
+			Content:      "func foo() {\n    fmt.Println(\"foo\")\n}",
+			ContentStart: protocol.Location{Offset: 1, Line: 1},
+			Ranges: []protocol.Range{{
+				Start: protocol.Location{Offset: 12, Line: 1, Column: 11},
+				End:   protocol.Location{Offset: 38, Line: 3, Column: 1},
+			}},
+		}, {
+			Content:      "func bar() {\n    fmt.Println(\"bar\")\n}",
+			ContentStart: protocol.Location{Offset: 40, Line: 5},
+			Ranges: []protocol.Range{{
+				Start: protocol.Location{Offset: 51, Line: 5, Column: 11},
+				End:   protocol.Location{Offset: 77, Line: 7, Column: 1},
+			}},
+		}},
+	}}
+	require.Equal(t, expected, sender.collected)
+}
+
+func TestParseTemplate(t *testing.T) {
+	got, err := parseTemplate("foo(:[a],\n  :[[b]], :[_], :[c~\\w+\\]?], :[~x])")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []string
+	for _, item := range got.items {
+		switch {
+		case item.hole == nil:
+			items = append(items, "literal "+item.literal)
+		case item.hole.word:
+			items = append(items, "word "+item.hole.name)
+		case item.hole.re != nil:
+			items = append(items, "regexp "+item.hole.name+" "+item.hole.re.String())
+		default:
+			items = append(items, "hole "+item.hole.name)
+		}
+	}
+	want := []string{
+		"literal foo(",
+		"hole a",
+		"literal ,",
+		"word b",
+		"literal ,",
+		"hole _",
+		"literal ,",
+		`regexp c ^(?:\w+\]?)$`,
+		"literal ,",
+		"regexp _ ^(?:x)$",
+		"literal )",
+	}
+	if diff := cmp.Diff(want, items); diff != "" {
+		t.Fatalf("unexpected template (-want +got):\n%s", diff)
+	}
+
+	for _, pattern := range []string{"foo(:[])", "foo(:[x.])", "foo(:[ x])", "foo(:[x~(])", "foo(:[[x])"} {
+		if _, err := parseTemplate(pattern); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
@@ -14,6 +14,10 @@ type matchSender interface {
 	SentCount() int
 	Remaining() int
 	LimitHit() bool
+
+	// SetLimitHit records that matches were left out by a search which
+	// stopped early, even though the limit of the stream was not reached.
+	SetLimitHit()
 }
 
 type limitedStream struct {
@@ -87,6 +91,10 @@ func (m *limitedStream) LimitHit() bool {
 	return m.limitHit.Load()
 }
 
+func (m *limitedStream) SetLimitHit() {
+	m.limitHit.Store(true)
+}
+
 type limitedStreamCollector struct {
 	collected []protocol.FileMatch
 	mux       sync.Mutex
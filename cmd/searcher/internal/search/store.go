@@ -78,6 +78,26 @@ type Store struct {
 	// MaxCacheSizeBytes.
 	MaxCacheSizeBytes int64
 
+	// MaxTrigramCacheSizeBytes is the maximum size in bytes of the in-memory
+	// trigram indexes of the cached archives. The least recently used indexes
+	// are dropped to stay below it. If zero, no trigram indexes are built.
+	MaxTrigramCacheSizeBytes int64
+
+	// EphemeralShardThreshold is the number of searches of a repo@commit
+	// after which we build an ephemeral zoekt shard for it. Subsequent
+	// searches of the repo@commit are then answered by the shard rather than
+	// by scanning the archive. If zero, ephemeral shards are disabled.
+	EphemeralShardThreshold int
+
+	// EphemeralShardPath is the directory to store ephemeral zoekt shards.
+	// It must not be inside of Path.
+	EphemeralShardPath string
+
+	// MaxEphemeralShardCacheSizeBytes is the maximum size of the ephemeral
+	// shards on disk in bytes. Like MaxCacheSizeBytes we can temporarily be
+	// larger than it.
+	MaxEphemeralShardCacheSizeBytes int64
+
 	// Log is the Logger to use.
 	Log log.Logger
 
@@ -96,6 +116,10 @@ type Store struct {
 	// zipCache provides efficient access to repo zip files.
 	zipCache zipCache
 
+	// shards tracks the ephemeral zoekt shards. It is nil if
+	// EphemeralShardThreshold is zero.
+	shards *ephemeralShards
+
 	// DB is a connection to frontend database
 	DB database.DB
 }
@@ -111,6 +135,7 @@ type FilterFunc func(hdr *tar.Header) bool
 func (s *Store) Start() {
 	s.once.Do(func() {
 		s.fetchLimiter = mutablelimiter.New(15)
+		s.zipCache.trigrams.maxBytes = s.MaxTrigramCacheSizeBytes
 		s.cache = diskcache.NewStore(s.Path, "store",
 			diskcache.WithBackgroundTimeout(10*time.Minute),
 			diskcache.WithBeforeEvict(s.zipCache.delete),
@@ -120,6 +145,11 @@ func (s *Store) Start() {
 		metrics.MustRegisterDiskMonitor(s.Path)
 		go s.watchAndEvict()
 		go s.watchConfig()
+
+		if s.EphemeralShardThreshold > 0 {
+			s.shards = newEphemeralShards(s.EphemeralShardPath, s.EphemeralShardThreshold, s.ObservationContext)
+			go s.watchAndEvictEphemeralShards()
+		}
 	})
 }
 
@@ -159,16 +189,7 @@ func (s *Store) PrepareZipPaths(ctx context.Context, repo api.RepoName, commit a
 
 	filter := newSearchableFilter(&conf.Get().SiteConfiguration)
 
-	// key is a sha256 hash since we want to use it for the disk name
-	h := sha256.New()
-	_, _ = fmt.Fprintf(h, "%q %q", repo, commit)
-	filter.HashKey(h)
-	_, _ = io.WriteString(h, "\x00Paths")
-	for _, p := range paths {
-		_, _ = h.Write([]byte{0})
-		_, _ = io.WriteString(h, p)
-	}
-	key := hex.EncodeToString(h.Sum(nil))
+	key := archiveKey(repo, commit, filter, paths)
 	span.LogKV("key", key)
 
 	// Our fetch can take a long time, and the frontend aggressively cancels
@@ -215,6 +236,21 @@ func (s *Store) PrepareZipPaths(ctx context.Context, repo api.RepoName, commit a
 	}
 }
 
+// archiveKey returns the key used to cache the archive of repo at commit
+// containing paths (or all files if paths is empty).
+func archiveKey(repo api.RepoName, commit api.CommitID, filter *searchableFilter, paths []string) string {
+	// key is a sha256 hash since we want to use it for the disk name
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%q %q", repo, commit)
+	filter.HashKey(h)
+	_, _ = io.WriteString(h, "\x00Paths")
+	for _, p := range paths {
+		_, _ = h.Write([]byte{0})
+		_, _ = io.WriteString(h, p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // fetch fetches an archive from the network and stores it on disk. It does
 // not populate the in-memory cache. You should probably be calling
 // prepareZip.
@@ -451,6 +487,14 @@ var (
 		Name: "searcher_store_cache_size_bytes",
 		Help: "The total size of items in the on disk cache.",
 	})
+	metricTrigramIndexBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "searcher_store_trigram_index_bytes",
+		Help: "The total size of the in-memory trigram indexes of the cached zip files.",
+	})
+	metricTrigramIndexEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "searcher_store_trigram_index_evictions",
+		Help: "The total number of trigram indexes evicted from memory.",
+	})
 	metricEvictions = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "searcher_store_evictions",
 		Help: "The total number of items evicted from the cache.",
@@ -0,0 +1,261 @@
+package search
+
+import (
+	"container/list"
+	"regexp/syntax"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// trigramIndex is a compact index of the trigrams contained in each file of a
+// zipFile. It is used to skip files which cannot contain a match of a pattern
+// without reading their contents.
+//
+// Every file has a bloom filter of the trigrams of its contents. Trigrams are
+// lowercased (ASCII only) so that the same index serves case-sensitive and
+// case-insensitive searches. A filter can report trigrams which are not in the
+// file, but never misses one, so skipping files based on it is always safe.
+type trigramIndex struct {
+	// filters[offsets[i]:offsets[i+1]] is the bloom filter of the i-th file
+	// of the zipFile. It is empty for files with less than 3 bytes.
+	offsets []uint32
+	filters []uint64
+}
+
+// The bloom filter of a single file is between 32 bytes and 16KiB. Filters
+// of larger files may report more false positives.
+const (
+	minFilterWords = 4
+	maxFilterWords = 2048
+)
+
+// filterWords returns the number of 64 bit words of the bloom filter of a file
+// of the given size. We use a power of two close to one bit per byte of
+// content. Files usually contain far fewer distinct trigrams than bytes, so
+// this keeps false positives rare while the index stays around an eighth of
+// the size of the archive.
+func filterWords(size int) int {
+	if size < 3 {
+		return 0
+	}
+	words := minFilterWords
+	for words*64 < size && words < maxFilterWords {
+		words *= 2
+	}
+	return words
+}
+
+// buildTrigramIndex reads all files of zf and returns their trigram index. It
+// returns nil if cancel is closed before the index is built.
+func buildTrigramIndex(zf *zipFile, cancel <-chan struct{}) *trigramIndex {
+	idx := &trigramIndex{offsets: make([]uint32, len(zf.Files)+1)}
+	total := 0
+	for i := range zf.Files {
+		total += filterWords(int(zf.Files[i].Len))
+		idx.offsets[i+1] = uint32(total)
+	}
+	idx.filters = make([]uint64, total)
+
+	for i := range zf.Files {
+		select {
+		case <-cancel:
+			return nil
+		default:
+		}
+
+		filter := idx.filter(i)
+		if len(filter) == 0 {
+			continue
+		}
+		data := zf.DataFor(&zf.Files[i])
+		t := uint32(lowerASCII(data[0]))<<8 | uint32(lowerASCII(data[1]))
+		for _, b := range data[2:] {
+			t = (t<<8 | uint32(lowerASCII(b))) & 0xffffff
+			h1, h2 := trigramHashes(t)
+			setBit(filter, h1)
+			setBit(filter, h2)
+		}
+	}
+
+	return idx
+}
+
+// size returns the size of idx in memory in bytes.
+func (idx *trigramIndex) size() int64 {
+	return int64(len(idx.offsets))*4 + int64(len(idx.filters))*8
+}
+
+// trigramCache bounds the memory used by the trigram indexes of a zipCache. It
+// keeps the indexes in least recently used order, and drops the least recently
+// used ones once their total size exceeds maxBytes. A dropped index is rebuilt
+// the next time its zipFile is searched.
+//
+// The zero value is usable, and does not keep any indexes.
+type trigramCache struct {
+	// maxBytes is the maximum total size of the indexes in bytes. If zero,
+	// no indexes are built.
+	maxBytes int64
+
+	mu    sync.Mutex
+	lru   list.List // *zipFile, most recently used at the front
+	bytes int64
+}
+
+// add stores idx as the trigram index of zf, and drops the least recently used
+// indexes until the cache fits into maxBytes again.
+func (c *trigramCache) add(zf *zipFile, idx *trigramIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if idx.size() > c.maxBytes {
+		// The index alone does not fit, so keep searching zf without it. We
+		// leave zf.trigramBuilding set so that we do not build it again.
+		return
+	}
+
+	zf.trigramIdx.Store(idx)
+	zf.trigramElem = c.lru.PushFront(zf)
+	c.bytes += idx.size()
+
+	for c.bytes > c.maxBytes {
+		lru := c.lru.Back().Value.(*zipFile)
+		c.removeLocked(lru)
+		metricTrigramIndexEvictions.Inc()
+	}
+	metricTrigramIndexBytes.Set(float64(c.bytes))
+}
+
+// touch marks the trigram index of zf as used.
+func (c *trigramCache) touch(zf *zipFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if zf.trigramElem != nil {
+		c.lru.MoveToFront(zf.trigramElem)
+	}
+}
+
+// remove drops the trigram index of zf, if it has one.
+func (c *trigramCache) remove(zf *zipFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(zf)
+	metricTrigramIndexBytes.Set(float64(c.bytes))
+}
+
+func (c *trigramCache) removeLocked(zf *zipFile) {
+	if zf.trigramElem == nil {
+		return
+	}
+	c.lru.Remove(zf.trigramElem)
+	zf.trigramElem = nil
+	if idx := zf.trigramIdx.Swap(nil); idx != nil {
+		c.bytes -= idx.size()
+	}
+	zf.trigramBuilding.Store(false)
+}
+
+func (idx *trigramIndex) filter(i int) []uint64 {
+	return idx.filters[idx.offsets[i]:idx.offsets[i+1]]
+}
+
+// mayContain returns false if the i-th file of the zipFile does not contain
+// all of the given trigrams, as returned by queryTrigrams.
+func (idx *trigramIndex) mayContain(i int, trigrams []uint32) bool {
+	filter := idx.filter(i)
+	if len(filter) == 0 {
+		// The file is too small to contain any trigram.
+		return len(trigrams) == 0
+	}
+	for _, t := range trigrams {
+		h1, h2 := trigramHashes(t)
+		if !hasBit(filter, h1) || !hasBit(filter, h2) {
+			return false
+		}
+	}
+	return true
+}
+
+// trigramHashes returns the two hashes of trigram t used by the bloom filters.
+func trigramHashes(t uint32) (uint32, uint32) {
+	return uint32((uint64(t) * 0x9e3779b97f4a7c15) >> 32), uint32((uint64(t) * 0xc2b2ae3d27d4eb4f) >> 32)
+}
+
+// setBit and hasBit address bit h modulo the size of filter, whose length is a
+// power of two.
+func setBit(filter []uint64, h uint32) {
+	h &= uint32(len(filter)*64 - 1)
+	filter[h/64] |= 1 << (h % 64)
+}
+
+func hasBit(filter []uint64, h uint32) bool {
+	h &= uint32(len(filter)*64 - 1)
+	return filter[h/64]&(1<<(h%64)) != 0
+}
+
+func lowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// queryTrigrams returns the distinct trigrams, lowercased like the contents of
+// a trigramIndex, which must appear in any match of re.
+func queryTrigrams(re *syntax.Regexp) []uint32 {
+	seen := map[uint32]struct{}{}
+	var trigrams []uint32
+	for _, lit := range requiredLiterals(re) {
+		for i := 0; i+3 <= len(lit); i++ {
+			t := uint32(lowerASCII(lit[i]))<<16 | uint32(lowerASCII(lit[i+1]))<<8 | uint32(lowerASCII(lit[i+2]))
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			trigrams = append(trigrams, t)
+		}
+	}
+	return trigrams
+}
+
+// requiredLiterals returns literal strings which are guaranteed to appear in a
+// match of re. Like longestLiteral, it does not look into alternations.
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 && !foldsToASCII(re.Rune) {
+			// Case folding may match bytes we do not index as the same
+			// trigram, e.g. (?i)k matches the Kelvin sign.
+			return nil
+		}
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min >= 1 {
+			return requiredLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var literals []string
+		for _, sub := range re.Sub {
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+		return literals
+	}
+	return nil
+}
+
+// foldsToASCII returns true if all runes and their case foldings are ASCII.
+func foldsToASCII(runes []rune) bool {
+	for _, r := range runes {
+		if r >= utf8.RuneSelf {
+			return false
+		}
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package search
+
+import (
+	"context"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+)
+
+func TestTrigramIndex(t *testing.T) {
+	files := map[string]string{
+		"empty":  "",
+		"short":  "ab",
+		"hello":  "Hello World",
+		"kelvin": "Kelvin",
+		"large":  strings.Repeat("0123456789", 1000) + "needle",
+		"errors": "if err != nil {\n\treturn errors.Wrap(err, \"oops\")\n}\n",
+	}
+	zipData, err := createZip(files)
+	require.NoError(t, err)
+	zf, err := mockZipFile(zipData)
+	require.NoError(t, err)
+
+	idx := buildTrigramIndex(zf, nil)
+
+	canceled := make(chan struct{})
+	close(canceled)
+	require.Nil(t, buildTrigramIndex(zf, canceled), "canceled builds return no index")
+
+	cases := []struct {
+		pattern protocol.PatternInfo
+		want    []string
+	}{{
+		pattern: protocol.PatternInfo{Pattern: "world"},
+		want:    []string{"hello"},
+	}, {
+		pattern: protocol.PatternInfo{Pattern: "World", IsCaseSensitive: true},
+		want:    []string{"hello"},
+	}, {
+		pattern: protocol.PatternInfo{Pattern: "needle"},
+		want:    []string{"large"},
+	}, {
+		pattern: protocol.PatternInfo{Pattern: `errors\.\w+\(err`, IsRegExp: true},
+		want:    []string{"errors"},
+	}, {
+		pattern: protocol.PatternInfo{Pattern: "(?i)kelvin", IsRegExp: true, IsCaseSensitive: true},
+		// The pattern has no trigrams which are safe to use.
+		want: []string{"empty", "errors", "hello", "kelvin", "large", "short"},
+	}, {
+		pattern: protocol.PatternInfo{Pattern: "hello|world", IsRegExp: true},
+		// Alternations are not used to prune files.
+		want: []string{"empty", "errors", "hello", "kelvin", "large", "short"},
+	}, {
+		pattern: protocol.PatternInfo{Pattern: "ab"},
+		// Patterns shorter than a trigram do not prune files.
+		want: []string{"empty", "errors", "hello", "kelvin", "large", "short"},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern.Pattern, func(t *testing.T) {
+			rg, err := compile(&tc.pattern)
+			require.NoError(t, err)
+
+			var got []string
+			for i, f := range zf.Files {
+				if idx.mayContain(i, rg.trigrams) {
+					got = append(got, f.Name)
+				}
+			}
+			sort.Strings(got)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTrigramCache(t *testing.T) {
+	zipData, err := createZip(map[string]string{"main.go": strings.Repeat("package main\n", 100)})
+	require.NoError(t, err)
+
+	var zfs []*zipFile
+	for i := 0; i < 3; i++ {
+		zf, err := mockZipFile(zipData)
+		require.NoError(t, err)
+		zfs = append(zfs, zf)
+	}
+	size := buildTrigramIndex(zfs[0], nil).size()
+
+	c := &trigramCache{maxBytes: 2 * size}
+	for _, zf := range zfs {
+		zf.trigramCache = c
+		zf.trigramBuilding.Store(true)
+	}
+
+	c.add(zfs[0], buildTrigramIndex(zfs[0], nil))
+	c.add(zfs[1], buildTrigramIndex(zfs[1], nil))
+	require.Equal(t, 2*size, c.bytes)
+
+	// zfs[0] is now the most recently used index, so adding a third index
+	// evicts the one of zfs[1].
+	require.NotNil(t, zfs[0].trigrams())
+	c.add(zfs[2], buildTrigramIndex(zfs[2], nil))
+	require.Equal(t, 2*size, c.bytes)
+	require.NotNil(t, zfs[0].trigramIdx.Load())
+	require.Nil(t, zfs[1].trigramIdx.Load())
+	require.NotNil(t, zfs[2].trigramIdx.Load())
+	require.False(t, zfs[1].trigramBuilding.Load(), "evicted indexes are rebuilt on the next search")
+
+	c.remove(zfs[0])
+	require.Equal(t, size, c.bytes)
+	require.Nil(t, zfs[0].trigramIdx.Load())
+
+	// Indexes which do not fit into the cache on their own are not kept.
+	small := &trigramCache{maxBytes: size - 1}
+	zfs[1].trigramCache = small
+	zfs[1].trigramBuilding.Store(true)
+	small.add(zfs[1], buildTrigramIndex(zfs[1], nil))
+	require.Nil(t, zfs[1].trigramIdx.Load())
+	require.Zero(t, small.bytes)
+}
+
+func TestRequiredLiterals(t *testing.T) {
+	cases := map[string][]string{
+		"foo":                   {"foo"},
+		`foo\dbar`:              {"foo", "bar"},
+		`(foo\dbar)+`:           {"foo", "bar"},
+		`(foo\dbar)*`:           nil,
+		"(foo|bar)":             nil,
+		`\wfoo(\dlongest\wbam)`: {"foo", "longest", "bam"},
+		"(?i)foo":               {"foo"},
+		"(?i)kelvin":            nil,
+	}
+
+	for expr, want := range cases {
+		re, err := syntax.Parse(expr, syntax.Perl)
+		require.NoError(t, err)
+		require.Equal(t, want, requiredLiterals(re.Simplify()), expr)
+	}
+}
+
+func TestRegexSearchTrigramIndex(t *testing.T) {
+	zipData, err := createZip(map[string]string{
+		"a.go":    "package a\n\nfunc Foo() {}\n",
+		"b.go":    "package b\n\nfunc Bar() {}\n",
+		"foo.txt": "nothing to see here\n",
+	})
+	require.NoError(t, err)
+
+	search := func(p protocol.PatternInfo, withIndex bool) []string {
+		zf, err := mockZipFile(zipData)
+		require.NoError(t, err)
+		if withIndex {
+			zf.trigramBuilding.Store(true)
+			zf.trigramIdx.Store(buildTrigramIndex(zf, nil))
+		}
+
+		rg, err := compile(&p)
+		require.NoError(t, err)
+		fileMatches, _, err := regexSearchBatch(context.Background(), rg, zf, 10, p.PatternMatchesContent, p.PatternMatchesPath, p.IsNegated)
+		require.NoError(t, err)
+
+		var got []string
+		for _, fm := range fileMatches {
+			got = append(got, fm.Path)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	cases := []protocol.PatternInfo{
+		{Pattern: "func foo"},
+		{Pattern: "func Foo", IsCaseSensitive: true},
+		{Pattern: "foo", PatternMatchesContent: true, PatternMatchesPath: true},
+		{Pattern: "package", IsNegated: true},
+		{Pattern: "func Foo", IsNegated: true},
+	}
+	for _, p := range cases {
+		t.Run(p.Pattern, func(t *testing.T) {
+			require.Equal(t, search(p, false), search(p, true))
+		})
+	}
+}
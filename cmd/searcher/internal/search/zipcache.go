@@ -2,6 +2,7 @@ package search
 
 import (
 	"archive/zip"
+	"container/list"
 	"fmt"
 	"hash/fnv"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"sync"
 	"syscall"
 
+	"go.uber.org/atomic"
 	"golang.org/x/sys/unix"
 
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -29,6 +31,10 @@ type zipCache struct {
 	// occurs when a file is being deleted, and files are deleted
 	// when no one has used them for a long time. Nevertheless, take care.)
 	shards [64]zipCacheShard
+
+	// trigrams bounds the memory used by the trigram indexes of the cached
+	// zip files.
+	trigrams trigramCache
 }
 
 type zipCacheShard struct {
@@ -63,6 +69,7 @@ func (c *zipCache) Get(path string) (*zipFile, error) {
 	if err != nil {
 		return nil, err
 	}
+	zf.trigramCache = &c.trigrams
 	shard.m[path] = zf
 	zf.wg.Add(1)
 	return zf, nil
@@ -77,8 +84,13 @@ func (c *zipCache) delete(path string, trace observation.TraceLogger) {
 		// already deleted?!
 		return
 	}
+	// Cancel building the trigram index, which holds wg while it runs.
+	if zf.evicted != nil {
+		close(zf.evicted)
+	}
 	// Wait for all clients using this zipFile to complete their work.
 	zf.wg.Wait()
+	c.trigrams.remove(zf)
 	// Mock zipFiles have nil f. Only try to munmap and close f if it is non-nil.
 	if zf.f != nil {
 		// For now, only log errors here.
@@ -103,6 +115,16 @@ type zipFile struct {
 	Data   []byte
 	f      *os.File
 	wg     sync.WaitGroup // ensures underlying file is not munmap'd or closed while in use
+
+	// trigramIdx is built lazily by trigrams. It is kept in trigramCache
+	// until it is evicted from it, or until the zip file is evicted from disk.
+	trigramIdx      atomic.Pointer[trigramIndex]
+	trigramBuilding atomic.Bool
+	trigramCache    *trigramCache
+	trigramElem     *list.Element // guarded by trigramCache.mu
+	// evicted is closed when the zipFile is evicted, which cancels building
+	// its trigram index.
+	evicted chan struct{}
 }
 
 func readZipFile(path string) (*zipFile, error) {
@@ -121,7 +143,7 @@ func readZipFile(path string) (*zipFile, error) {
 	}
 
 	// Create at populate ZipFile from contents.
-	zf := &zipFile{f: f}
+	zf := &zipFile{f: f, evicted: make(chan struct{})}
 	if err := zf.PopulateFiles(r); err != nil {
 		return nil, err
 	}
@@ -175,6 +197,52 @@ func (f *zipFile) Close() {
 	f.wg.Done()
 }
 
+// maxConcurrentTrigramBuilds is the maximum number of trigram indexes we build
+// concurrently. Building an index reads the whole zip file, so unbounded builds
+// would compete with searches for CPU and IO.
+const maxConcurrentTrigramBuilds = 2
+
+var trigramBuildSem = make(chan struct{}, maxConcurrentTrigramBuilds)
+
+// trigrams returns the trigram index of f, or nil if it is not built yet. The
+// first call starts building the index in the background, so that the search
+// which triggered it does not have to wait for it. The build waits for one of
+// maxConcurrentTrigramBuilds slots, and is canceled if f is evicted. If the
+// index is dropped from trigramCache, the next call builds it again.
+//
+// It MUST only be called between retrieving f with get and calling Close.
+func (f *zipFile) trigrams() *trigramIndex {
+	if idx := f.trigramIdx.Load(); idx != nil {
+		if f.trigramCache != nil {
+			f.trigramCache.touch(f)
+		}
+		return idx
+	}
+	if f.trigramCache == nil || f.trigramCache.maxBytes == 0 || !f.trigramBuilding.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	// Ensures f is not munmap'd while we read it.
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		select {
+		case trigramBuildSem <- struct{}{}:
+			defer func() { <-trigramBuildSem }()
+		case <-f.evicted:
+			return
+		}
+
+		idx := buildTrigramIndex(f, f.evicted)
+		if idx == nil {
+			return
+		}
+		f.trigramCache.add(f, idx)
+	}()
+	return nil
+}
+
 // A srcFile is a single file inside a ZipFile.
 type srcFile struct {
 	// Take care with the size of this struct.
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -130,7 +131,12 @@ func zoektSearch(ctx context.Context, args *search.TextPatternInfo, branchRepos
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := structuralSearch(ctx, comby.Tar{TarInputEventC: tarInputEventC}, all, extensionHint, args.Pattern, args.CombyRule, args.Languages, repo, sender)
+		var err error
+		if args.StructuralEngine == query.StructuralEngineTreeSitter {
+			err = treeSitterSearch(ctx, comby.Tar{TarInputEventC: tarInputEventC}, all, args.Pattern, args.CombyRule, args.Languages, repo, sender)
+		} else {
+			err = structuralSearch(ctx, comby.Tar{TarInputEventC: tarInputEventC}, all, extensionHint, args.Pattern, args.CombyRule, args.Languages, repo, sender)
+		}
 		if err != nil {
 			log.NamedError("structural search error", err)
 		}
@@ -50,6 +50,11 @@ var (
 	cacheDir    = env.Get("CACHE_DIR", "/tmp", "directory to store cached archives.")
 	cacheSizeMB = env.Get("SEARCHER_CACHE_SIZE_MB", "100000", "maximum size of the on disk cache in megabytes")
 
+	trigramCacheSizeMB = env.Get("SEARCHER_TRIGRAM_CACHE_SIZE_MB", "1000", "maximum size of the in-memory trigram indexes of cached archives in megabytes. 0 disables trigram indexes.")
+
+	ephemeralShardThresholdRaw = env.Get("SEARCHER_EPHEMERAL_SHARD_THRESHOLD", "0", "number of searches of an unindexed repo@commit after which a temporary zoekt shard is built for it. 0 disables ephemeral shards.")
+	ephemeralShardCacheSizeMB  = env.Get("SEARCHER_EPHEMERAL_SHARD_CACHE_SIZE_MB", "10000", "maximum size of the on disk cache of ephemeral zoekt shards in megabytes")
+
 	maxTotalPathsLengthRaw = env.Get("MAX_TOTAL_PATHS_LENGTH", "100000", "maximum sum of lengths of all paths in a single call to git archive")
 )
 
@@ -130,6 +135,25 @@ func run(logger log.Logger) error {
 		cacheSizeBytes = i * 1000 * 1000
 	}
 
+	var trigramCacheSizeBytes int64
+	if i, err := strconv.ParseInt(trigramCacheSizeMB, 10, 64); err != nil {
+		return errors.Wrapf(err, "invalid int %q for SEARCHER_TRIGRAM_CACHE_SIZE_MB", trigramCacheSizeMB)
+	} else {
+		trigramCacheSizeBytes = i * 1000 * 1000
+	}
+
+	ephemeralShardThreshold, err := strconv.Atoi(ephemeralShardThresholdRaw)
+	if err != nil {
+		return errors.Wrapf(err, "invalid int %q for SEARCHER_EPHEMERAL_SHARD_THRESHOLD", ephemeralShardThresholdRaw)
+	}
+
+	var ephemeralShardCacheSizeBytes int64
+	if i, err := strconv.ParseInt(ephemeralShardCacheSizeMB, 10, 64); err != nil {
+		return errors.Wrapf(err, "invalid int %q for SEARCHER_EPHEMERAL_SHARD_CACHE_SIZE_MB", ephemeralShardCacheSizeMB)
+	} else {
+		ephemeralShardCacheSizeBytes = i * 1000 * 1000
+	}
+
 	maxTotalPathsLength, err := strconv.Atoi(maxTotalPathsLengthRaw)
 	if err != nil {
 		return errors.Wrapf(err, "invalid int %q for MAX_TOTAL_PATHS_LENGTH", maxTotalPathsLengthRaw)
@@ -175,12 +199,17 @@ func run(logger log.Logger) error {
 					Pathspecs: pathspecs,
 				})
 			},
-			FilterTar:          search.NewFilter,
-			Path:               filepath.Join(cacheDir, "searcher-archives"),
-			MaxCacheSizeBytes:  cacheSizeBytes,
-			Log:                storeObservationContext.Logger,
-			ObservationContext: storeObservationContext,
-			DB:                 db,
+			FilterTar:                search.NewFilter,
+			Path:                     filepath.Join(cacheDir, "searcher-archives"),
+			MaxCacheSizeBytes:        cacheSizeBytes,
+			MaxTrigramCacheSizeBytes: trigramCacheSizeBytes,
+			Log:                      storeObservationContext.Logger,
+			ObservationContext:       storeObservationContext,
+			DB:                       db,
+
+			EphemeralShardThreshold:         ephemeralShardThreshold,
+			EphemeralShardPath:              filepath.Join(cacheDir, "searcher-shards"),
+			MaxEphemeralShardCacheSizeBytes: ephemeralShardCacheSizeBytes,
 		},
 
 		GitDiffSymbols:      git.DiffSymbols,
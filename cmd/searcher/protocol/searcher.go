@@ -127,6 +127,11 @@ type PatternInfo struct {
 	// file list in the frontend and passes it to searcher.
 	CombyRule string
 
+	// StructuralEngine is the engine which matches structural patterns,
+	// "comby" or "tree-sitter". It only applies when IsStructuralPat is true
+	// and defaults to "comby" if empty.
+	StructuralEngine string
+
 	// Select is the value of the the select field in the query. It is not necessary to
 	// use it since selection is done after the query completes, but exposing it can enable
 	// optimizations.
@@ -144,6 +149,9 @@ func (p *PatternInfo) String() string {
 		} else {
 			args = append(args, "comby")
 		}
+		if p.StructuralEngine != "" {
+			args = append(args, fmt.Sprintf("engine:%s", p.StructuralEngine))
+		}
 	}
 	if p.IsWordMatch {
 		args = append(args, "word")
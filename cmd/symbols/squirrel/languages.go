@@ -4,6 +4,8 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/grafana/regexp"
 	sitter "github.com/smacker/go-tree-sitter"
@@ -44,6 +46,62 @@ var extToLang = func() map[string]string {
 	return m
 }()
 
+// Returns the language specification of the file at the given path.
+func langSpecForPath(path string) (LangSpec, error) {
+	ext := filepath.Base(path)
+	if strings.Index(ext, ".") >= 0 {
+		ext = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	langName, ok := extToLang[ext]
+	if !ok {
+		return LangSpec{}, unrecognizedFileExtensionError
+	}
+
+	langSpec, ok := langToLangSpec[langName]
+	if !ok {
+		return LangSpec{}, unsupportedLanguageError
+	}
+
+	return langSpec, nil
+}
+
+// LanguageForPath returns the tree-sitter grammar of the file at the given path, or nil if
+// there is none.
+func LanguageForPath(path string) *sitter.Language {
+	langSpec, err := langSpecForPath(path)
+	if err != nil {
+		return nil
+	}
+	return langSpec.language
+}
+
+// Mapping from aliases of language names in lang: filters to language names.
+var langAliases = map[string]string{
+	"c":      "cpp",
+	"c++":    "cpp",
+	"c#":     "csharp",
+	"js":     "javascript",
+	"ts":     "typescript",
+	"tsx":    "typescript",
+	"bazel":  "starlark",
+	"golang": "go",
+}
+
+// LanguageForName returns the tree-sitter grammar of the language with the given name, as
+// used in lang: filters, or nil if there is none.
+func LanguageForName(name string) *sitter.Language {
+	name = strings.ToLower(name)
+	if alias, ok := langAliases[name]; ok {
+		name = alias
+	}
+	langSpec, ok := langToLangSpec[name]
+	if !ok {
+		return nil
+	}
+	return langSpec.language
+}
+
 // Info about a language.
 type LangSpec struct {
 	name         string
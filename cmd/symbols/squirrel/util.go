@@ -3,7 +3,6 @@ package squirrel
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -254,19 +253,9 @@ var unsupportedLanguageError = errors.New("unsupported language")
 
 // Parses a file and returns info about it.
 func (s *SquirrelService) parse(ctx context.Context, repoCommitPath types.RepoCommitPath) (*Node, error) {
-	ext := filepath.Base(repoCommitPath.Path)
-	if strings.Index(ext, ".") >= 0 {
-		ext = strings.TrimPrefix(filepath.Ext(repoCommitPath.Path), ".")
-	}
-
-	langName, ok := extToLang[ext]
-	if !ok {
-		return nil, unrecognizedFileExtensionError
-	}
-
-	langSpec, ok := langToLangSpec[langName]
-	if !ok {
-		return nil, unsupportedLanguageError
+	langSpec, err := langSpecForPath(repoCommitPath.Path)
+	if err != nil {
+		return nil, err
 	}
 
 	s.parser.SetLanguage(langSpec.language)
@@ -26,14 +26,14 @@ func TestModeAvailability(t *testing.T) {
 		}
 	})
 
-	t.Run("returns repo path capture group", func(t *testing.T) {
+	t.Run("returns repo path capture group owner", func(t *testing.T) {
 		query := `(\w)\s\*testing.T`
 		availabilities, err := client.ModeAvailability(query, "regexp")
 		if err != nil {
 			t.Fatal(err)
 		}
 		for mode, response := range availabilities {
-			if mode == "REPO" || mode == "PATH" || mode == "CAPTURE_GROUP" {
+			if mode == "REPO" || mode == "PATH" || mode == "CAPTURE_GROUP" || mode == "OWNER" {
 				if response.Available != true {
 					t.Errorf("expected mode %v to be available for query %q", response.Mode, query)
 				}
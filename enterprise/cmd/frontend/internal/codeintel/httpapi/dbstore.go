@@ -14,6 +14,7 @@ type UploadMetadata struct {
 	Indexer           string
 	IndexerVersion    string
 	AssociatedIndexID int
+	ContentType       string
 }
 
 type DBStoreShim struct {
@@ -48,6 +49,7 @@ func (s *DBStoreShim) InsertUpload(ctx context.Context, upload uploadhandler.Upl
 		Indexer:           upload.Metadata.Indexer,
 		IndexerVersion:    upload.Metadata.IndexerVersion,
 		AssociatedIndexID: associatedIndexID,
+		ContentType:       upload.Metadata.ContentType,
 	})
 }
 
@@ -73,6 +75,7 @@ func (s *DBStoreShim) GetUploadByID(ctx context.Context, uploadID int) (uploadha
 			Root:           upload.Root,
 			Indexer:        upload.Indexer,
 			IndexerVersion: upload.IndexerVersion,
+			ContentType:    upload.ContentType,
 		},
 	}
 
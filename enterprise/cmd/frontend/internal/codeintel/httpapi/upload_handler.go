@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/uploadhandler"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/upload"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -55,6 +56,7 @@ func NewUploadHandler(
 			Indexer:           getQuery(r, "indexerName"),
 			IndexerVersion:    getQuery(r, "indexerVersion"),
 			AssociatedIndexID: getQueryInt(r, "associatedIndexId"),
+			ContentType:       getContentType(r),
 		}, 0, nil
 	}
 
@@ -75,6 +77,17 @@ func NewUploadHandler(
 	return handler
 }
 
+// getContentType returns the content type of the index file described by the given request.
+// Clients that predate SCIP support do not reliably send a content type, so anything that is
+// not explicitly a SCIP index is assumed to be LSIF.
+func getContentType(r *http.Request) string {
+	if contentType := r.Header.Get("Content-Type"); contentType == upload.SCIPContentType {
+		return contentType
+	}
+
+	return upload.LSIFContentType
+}
+
 func ensureRepoAndCommitExist(ctx context.Context, logger log.Logger, db database.DB, repoName, commit string) (int, int, error) {
 	// 🚨 SECURITY: Bypass authz here; we've already determined that the current request is
 	// authorized to view the target repository; they are either a site admin or the code
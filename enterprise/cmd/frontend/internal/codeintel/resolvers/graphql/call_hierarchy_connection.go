@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+
+	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+)
+
+type CallHierarchyCallConnectionResolver struct {
+	calls            []shared.CallHierarchyCall
+	cursor           *string
+	locationResolver *CachedLocationResolver
+}
+
+func NewCallHierarchyCallConnectionResolver(calls []shared.CallHierarchyCall, cursor *string, locationResolver *CachedLocationResolver) gql.CallHierarchyCallConnectionResolver {
+	return &CallHierarchyCallConnectionResolver{
+		calls:            calls,
+		cursor:           cursor,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *CallHierarchyCallConnectionResolver) Nodes(ctx context.Context) ([]gql.CallHierarchyCallResolver, error) {
+	resolvers := make([]gql.CallHierarchyCallResolver, 0, len(r.calls))
+	for _, call := range r.calls {
+		definition, err := resolveLocation(ctx, r.locationResolver, uploadLocationToAdjustedLocations([]shared.UploadLocation{call.Definition})[0])
+		if err != nil {
+			return nil, err
+		}
+		if definition == nil {
+			// The commit of the definition is not known by gitserver
+			continue
+		}
+
+		callSites, err := resolveLocations(ctx, r.locationResolver, uploadLocationToAdjustedLocations(call.CallSites))
+		if err != nil {
+			return nil, err
+		}
+
+		resolvers = append(resolvers, &callHierarchyCallResolver{definition: definition, callSites: callSites})
+	}
+
+	return resolvers, nil
+}
+
+func (r *CallHierarchyCallConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	return graphqlutil.EncodeCursor(r.cursor), nil
+}
+
+type callHierarchyCallResolver struct {
+	definition gql.LocationResolver
+	callSites  []gql.LocationResolver
+}
+
+func (r *callHierarchyCallResolver) Definition(ctx context.Context) (gql.LocationResolver, error) {
+	return r.definition, nil
+}
+
+func (r *callHierarchyCallResolver) CallSites(ctx context.Context) ([]gql.LocationResolver, error) {
+	return r.callSites, nil
+}
@@ -121,6 +121,29 @@ func (r *QueryResolver) Definitions(ctx context.Context, args *gql.LSIFQueryPosi
 	return NewLocationConnectionResolver(lct, nil, r.locationResolver), nil
 }
 
+func (r *QueryResolver) TypeDefinitions(ctx context.Context, args *gql.LSIFQueryPositionArgs) (_ gql.LocationConnectionResolver, err error) {
+	defer r.errTracer.Collect(&err, log.String("queryResolver.field", "typeDefinitions"))
+
+	locations, err := r.gitBlobLSIFDataResolver.TypeDefinitions(ctx, int(args.Line), int(args.Character))
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Filter != nil && *args.Filter != "" {
+		filtered := locations[:0]
+		for _, loc := range locations {
+			if strings.Contains(loc.Path, *args.Filter) {
+				filtered = append(filtered, loc)
+			}
+		}
+		locations = filtered
+	}
+
+	lct := uploadLocationToAdjustedLocations(locations)
+
+	return NewLocationConnectionResolver(lct, nil, r.locationResolver), nil
+}
+
 func (r *QueryResolver) References(ctx context.Context, args *gql.LSIFPagedQueryPositionArgs) (_ gql.LocationConnectionResolver, err error) {
 	defer r.errTracer.Collect(&err, log.String("queryResolver.field", "references"))
 
@@ -187,6 +210,48 @@ func (r *QueryResolver) Implementations(ctx context.Context, args *gql.LSIFPaged
 	return NewLocationConnectionResolver(lct, strPtr(cursor), r.locationResolver), nil
 }
 
+func (r *QueryResolver) IncomingCalls(ctx context.Context, args *gql.LSIFPagedQueryPositionArgs) (_ gql.CallHierarchyCallConnectionResolver, err error) {
+	defer r.errTracer.Collect(&err, log.String("queryResolver.field", "incomingCalls"))
+
+	limit := derefInt32(args.First, DefaultReferencesPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	cursor, err := graphqlutil.DecodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, cursor, err := r.gitBlobLSIFDataResolver.IncomingCalls(ctx, int(args.Line), int(args.Character), limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCallHierarchyCallConnectionResolver(calls, strPtr(cursor), r.locationResolver), nil
+}
+
+func (r *QueryResolver) OutgoingCalls(ctx context.Context, args *gql.LSIFPagedQueryPositionArgs) (_ gql.CallHierarchyCallConnectionResolver, err error) {
+	defer r.errTracer.Collect(&err, log.String("queryResolver.field", "outgoingCalls"))
+
+	limit := derefInt32(args.First, DefaultReferencesPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	cursor, err := graphqlutil.DecodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, cursor, err := r.gitBlobLSIFDataResolver.OutgoingCalls(ctx, int(args.Line), int(args.Character), limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCallHierarchyCallConnectionResolver(calls, strPtr(cursor), r.locationResolver), nil
+}
+
 func (r *QueryResolver) Hover(ctx context.Context, args *gql.LSIFQueryPositionArgs) (_ gql.HoverResolver, err error) {
 	defer r.errTracer.Collect(&err, log.String("queryResolver.field", "hover"))
 
@@ -62,6 +62,29 @@ func TestDefinitions(t *testing.T) {
 	}
 }
 
+func TestTypeDefinitions(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, nil)
+
+	mockGitBlobResolver := transportmocks.NewMockGitBlobLSIFDataResolver()
+	resolver := NewQueryResolver(nil, mockGitBlobResolver, nil, NewCachedLocationResolver(db), nil)
+
+	args := &gql.LSIFQueryPositionArgs{Line: 10, Character: 15}
+	if _, err := resolver.TypeDefinitions(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockGitBlobResolver.TypeDefinitionsFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockGitBlobResolver.TypeDefinitionsFunc.History()))
+	}
+	if val := mockGitBlobResolver.TypeDefinitionsFunc.History()[0].Arg1; val != 10 {
+		t.Fatalf("unexpected line. want=%d have=%d", 10, val)
+	}
+	if val := mockGitBlobResolver.TypeDefinitionsFunc.History()[0].Arg2; val != 15 {
+		t.Fatalf("unexpected character. want=%d have=%d", 15, val)
+	}
+}
+
 func TestReferences(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := database.NewDB(logger, nil)
@@ -150,6 +173,128 @@ func TestReferencesDefaultIllegalLimit(t *testing.T) {
 	}
 }
 
+func TestIncomingCalls(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, nil)
+
+	mockGitBlobResolver := transportmocks.NewMockGitBlobLSIFDataResolver()
+	resolver := NewQueryResolver(nil, mockGitBlobResolver, nil, NewCachedLocationResolver(db), nil)
+
+	offset := int32(25)
+	cursor := base64.StdEncoding.EncodeToString([]byte("test-cursor"))
+
+	args := &gql.LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: gql.LSIFQueryPositionArgs{
+			Line:      10,
+			Character: 15,
+		},
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
+		After:          &cursor,
+	}
+
+	if _, err := resolver.IncomingCalls(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockGitBlobResolver.IncomingCallsFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockGitBlobResolver.IncomingCallsFunc.History()))
+	}
+	if val := mockGitBlobResolver.IncomingCallsFunc.History()[0].Arg1; val != 10 {
+		t.Fatalf("unexpected line. want=%d have=%d", 10, val)
+	}
+	if val := mockGitBlobResolver.IncomingCallsFunc.History()[0].Arg2; val != 15 {
+		t.Fatalf("unexpected character. want=%d have=%d", 15, val)
+	}
+	if val := mockGitBlobResolver.IncomingCallsFunc.History()[0].Arg3; val != 25 {
+		t.Fatalf("unexpected limit. want=%d have=%d", 25, val)
+	}
+	if val := mockGitBlobResolver.IncomingCallsFunc.History()[0].Arg4; val != "test-cursor" {
+		t.Fatalf("unexpected cursor. want=%s have=%s", "test-cursor", val)
+	}
+}
+
+func TestIncomingCallsDefaultIllegalLimit(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, nil)
+
+	mockGitBlobResolver := transportmocks.NewMockGitBlobLSIFDataResolver()
+	resolver := NewQueryResolver(nil, mockGitBlobResolver, nil, NewCachedLocationResolver(db), observation.NewErrorCollector())
+
+	offset := int32(-1)
+	args := &gql.LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: gql.LSIFQueryPositionArgs{
+			Line:      10,
+			Character: 15,
+		},
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
+	}
+
+	if _, err := resolver.IncomingCalls(context.Background(), args); err != ErrIllegalLimit {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrIllegalLimit, err)
+	}
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, nil)
+
+	mockGitBlobResolver := transportmocks.NewMockGitBlobLSIFDataResolver()
+	resolver := NewQueryResolver(nil, mockGitBlobResolver, nil, NewCachedLocationResolver(db), nil)
+
+	offset := int32(25)
+	cursor := base64.StdEncoding.EncodeToString([]byte("test-cursor"))
+
+	args := &gql.LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: gql.LSIFQueryPositionArgs{
+			Line:      10,
+			Character: 15,
+		},
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
+		After:          &cursor,
+	}
+
+	if _, err := resolver.OutgoingCalls(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockGitBlobResolver.OutgoingCallsFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockGitBlobResolver.OutgoingCallsFunc.History()))
+	}
+	if val := mockGitBlobResolver.OutgoingCallsFunc.History()[0].Arg1; val != 10 {
+		t.Fatalf("unexpected line. want=%d have=%d", 10, val)
+	}
+	if val := mockGitBlobResolver.OutgoingCallsFunc.History()[0].Arg2; val != 15 {
+		t.Fatalf("unexpected character. want=%d have=%d", 15, val)
+	}
+	if val := mockGitBlobResolver.OutgoingCallsFunc.History()[0].Arg3; val != 25 {
+		t.Fatalf("unexpected limit. want=%d have=%d", 25, val)
+	}
+	if val := mockGitBlobResolver.OutgoingCallsFunc.History()[0].Arg4; val != "test-cursor" {
+		t.Fatalf("unexpected cursor. want=%s have=%s", "test-cursor", val)
+	}
+}
+
+func TestOutgoingCallsDefaultIllegalLimit(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, nil)
+
+	mockGitBlobResolver := transportmocks.NewMockGitBlobLSIFDataResolver()
+	resolver := NewQueryResolver(nil, mockGitBlobResolver, nil, NewCachedLocationResolver(db), observation.NewErrorCollector())
+
+	offset := int32(-1)
+	args := &gql.LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: gql.LSIFQueryPositionArgs{
+			Line:      10,
+			Character: 15,
+		},
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
+	}
+
+	if _, err := resolver.OutgoingCalls(context.Background(), args); err != ErrIllegalLimit {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrIllegalLimit, err)
+	}
+}
+
 func TestHover(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := database.NewDB(logger, nil)
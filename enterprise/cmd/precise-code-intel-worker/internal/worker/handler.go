@@ -1,8 +1,10 @@
 package worker
 
 import (
+	"bufio"
 	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"sync/atomic"
@@ -12,8 +14,11 @@ import (
 	"github.com/keegancsmith/sqlf"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -26,7 +31,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/pathexistence"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	codeintelupload "github.com/sourcegraph/sourcegraph/lib/codeintel/upload"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -146,9 +153,9 @@ func (h *handler) handle(ctx context.Context, logger log.Logger, upload store.Up
 	}
 
 	return false, withUploadData(ctx, logger, h.uploadStore, upload.ID, trace, func(r io.Reader) (err error) {
-		groupedBundleData, err := conversion.Correlate(ctx, r, upload.Root, getChildren)
+		groupedBundleData, err := correlate(ctx, r, upload, getChildren)
 		if err != nil {
-			return errors.Wrap(err, "conversion.Correlate")
+			return err
 		}
 
 		// Note: this is writing to a different database than the block below, so we need to use a
@@ -283,8 +290,9 @@ func requeueIfCloningOrCommitUnknown(ctx context.Context, logger log.Logger, db
 }
 
 // withUploadData will invoke the given function with a reader of the upload's raw data. The
-// consumer should expect raw newline-delimited JSON content. If the function returns without
-// an error, the upload file will be deleted.
+// consumer should expect raw newline-delimited JSON content or a protobuf-encoded SCIP index,
+// depending on the upload's content type. If the function returns without an error, the upload
+// file will be deleted.
 func withUploadData(ctx context.Context, logger log.Logger, uploadStore uploadstore.Store, id int, trace observation.TraceLogger, fn func(r io.Reader) error) error {
 	uploadFilename := fmt.Sprintf("upload-%d.lsif.gz", id)
 
@@ -316,6 +324,101 @@ func withUploadData(ctx context.Context, logger log.Logger, uploadStore uploadst
 	return nil
 }
 
+// correlate converts the raw upload data into the grouped bundle format written to the LSIF store.
+// SCIP indexes are decoded and translated into an equivalent LSIF graph first so that both formats
+// are correlated, canonicalized, and pruned identically.
+func correlate(ctx context.Context, r io.Reader, upload store.Upload, getChildren pathexistence.GetChildrenFunc) (*precise.GroupedBundleDataChans, error) {
+	if upload.ContentType != codeintelupload.SCIPContentType {
+		groupedBundleData, err := conversion.Correlate(ctx, r, upload.Root, getChildren)
+		if err != nil {
+			return nil, errors.Wrap(err, "conversion.Correlate")
+		}
+
+		return groupedBundleData, nil
+	}
+
+	index, err := readSCIPIndex(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "readSCIPIndex")
+	}
+
+	elements, err := scip.ConvertSCIPToLSIF(index)
+	if err != nil {
+		return nil, errors.Wrap(err, "scip.ConvertSCIPToLSIF")
+	}
+
+	groupedBundleData, err := conversion.CorrelateElements(ctx, elements, upload.Root, getChildren)
+	if err != nil {
+		return nil, errors.Wrap(err, "conversion.CorrelateElements")
+	}
+
+	return groupedBundleData, nil
+}
+
+// maxSCIPMessageSize is the maximum size of a single message of a raw SCIP index, such as a
+// document. Like reader.LineBufferSize for LSIF indexes, it bounds the memory needed to decode
+// a message, no matter the size of the whole index.
+const maxSCIPMessageSize = 1e8
+
+// readSCIPIndex decodes the given protobuf-encoded SCIP index. Rather than reading the whole
+// index into memory before decoding it, the top-level fields of the index (its metadata, each
+// document, and each external symbol) are read and decoded one at a time.
+func readSCIPIndex(r io.Reader) (*scip.Index, error) {
+	br := bufio.NewReader(r)
+	index := &scip.Index{}
+
+	var buf []byte
+	for {
+		tag, err := binary.ReadUvarint(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return index, nil
+			}
+			return nil, errors.Wrap(err, "reading field tag")
+		}
+		field, wireType := protowire.DecodeTag(tag)
+		if wireType != protowire.BytesType {
+			return nil, errors.Newf("unexpected wire type %d of field %d", wireType, field)
+		}
+
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading field size")
+		}
+		if size > maxSCIPMessageSize {
+			return nil, errors.Newf("field %d of %d bytes exceeds the maximum size of %d bytes", field, size, int64(maxSCIPMessageSize))
+		}
+		if uint64(cap(buf)) < size {
+			buf = make([]byte, size)
+		}
+		buf = buf[:size]
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, errors.Wrapf(err, "reading field %d", field)
+		}
+
+		var message proto.Message
+		switch field {
+		case 1:
+			index.Metadata = &scip.Metadata{}
+			message = index.Metadata
+		case 2:
+			document := &scip.Document{}
+			index.Documents = append(index.Documents, document)
+			message = document
+		case 3:
+			symbol := &scip.SymbolInformation{}
+			index.ExternalSymbols = append(index.ExternalSymbols, symbol)
+			message = symbol
+		default:
+			// Unknown fields are skipped, like proto.Unmarshal does.
+			continue
+		}
+		if err := proto.Unmarshal(buf, message); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling field %d", field)
+		}
+	}
+}
+
 // writeData transactionally writes the given grouped bundle data into the given LSIF store.
 func writeData(ctx context.Context, lsifStore LSIFStore, upload store.Upload, repo *types.Repo, isDefaultBranch bool, groupedBundleData *precise.GroupedBundleDataChans, trace observation.TraceLogger) (err error) {
 	tx, err := lsifStore.Transact(ctx)
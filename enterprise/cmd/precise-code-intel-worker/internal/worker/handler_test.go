@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"os"
@@ -9,8 +10,11 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/sourcegraph/log/logtest"
+	"github.com/sourcegraph/scip/bindings/go/scip"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -20,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	uploadstoremocks "github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	codeintelupload "github.com/sourcegraph/sourcegraph/lib/codeintel/upload"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -263,6 +268,122 @@ func TestHandleCloneInProgress(t *testing.T) {
 	}
 }
 
+func TestCorrelateSCIP(t *testing.T) {
+	symbol := "scip-go gomod github.com/test/root v1.2.3 `github.com/test/root`/Func()."
+
+	index := &scip.Index{
+		Metadata: &scip.Metadata{
+			ToolInfo:             &scip.ToolInfo{Name: "scip-go", Version: "0.1.0"},
+			ProjectRoot:          "file:///test/root/",
+			TextDocumentEncoding: scip.TextEncoding_UTF8,
+		},
+		Documents: []*scip.Document{
+			{
+				RelativePath: "foo.go",
+				Occurrences: []*scip.Occurrence{
+					{Range: []int32{1, 5, 9}, Symbol: symbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+					{Range: []int32{3, 1, 5}, Symbol: symbol},
+				},
+				Symbols: []*scip.SymbolInformation{
+					{Symbol: symbol, Documentation: []string{"```go\nfunc Func()\n```"}},
+				},
+			},
+		},
+	}
+
+	content, err := proto.Marshal(index)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling index: %s", err)
+	}
+
+	upload := dbstore.Upload{
+		ID:          42,
+		ContentType: codeintelupload.SCIPContentType,
+	}
+	getChildren := func(ctx context.Context, dirnames []string) (map[string][]string, error) {
+		return map[string][]string{"": {"foo.go"}}, nil
+	}
+
+	groupedBundleData, err := correlate(context.Background(), bytes.NewReader(content), upload, getChildren)
+	if err != nil {
+		t.Fatalf("unexpected error correlating index: %s", err)
+	}
+
+	var paths []string
+	for document := range groupedBundleData.Documents {
+		paths = append(paths, document.Path)
+
+		if len(document.Document.Ranges) != 2 {
+			t.Errorf("unexpected number of ranges. want=%d have=%d", 2, len(document.Document.Ranges))
+		}
+	}
+
+	// Drain remaining channels so that the producers can exit
+	for range groupedBundleData.ResultChunks {
+	}
+	for range groupedBundleData.Definitions {
+	}
+	for range groupedBundleData.References {
+	}
+	for range groupedBundleData.Implementations {
+	}
+
+	if diff := cmp.Diff([]string{"foo.go"}, paths); diff != "" {
+		t.Errorf("unexpected document paths (-want +got):\n%s", diff)
+	}
+
+	expectedPackages := []precise.Package{
+		{
+			Scheme:  "scip-go",
+			Name:    "github.com/test/root",
+			Version: "v1.2.3",
+		},
+	}
+	if diff := cmp.Diff(expectedPackages, groupedBundleData.Packages); diff != "" {
+		t.Errorf("unexpected packages (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadSCIPIndex(t *testing.T) {
+	index := &scip.Index{
+		Metadata: &scip.Metadata{
+			ToolInfo:    &scip.ToolInfo{Name: "scip-go", Version: "0.1.0"},
+			ProjectRoot: "file:///test/root/",
+		},
+		Documents: []*scip.Document{
+			{RelativePath: "foo.go"},
+			{RelativePath: "bar.go"},
+		},
+		ExternalSymbols: []*scip.SymbolInformation{
+			{Symbol: "scip-go gomod github.com/test/dep v1.0.0 `github.com/test/dep`/Func()."},
+		},
+	}
+
+	content, err := proto.Marshal(index)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling index: %s", err)
+	}
+
+	decoded, err := readSCIPIndex(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error reading index: %s", err)
+	}
+	if !proto.Equal(index, decoded) {
+		t.Errorf("unexpected index. want=%v have=%v", index, decoded)
+	}
+
+	// A document larger than the maximum message size is rejected before it is read
+	tooLarge := protowire.AppendTag(nil, 2, protowire.BytesType)
+	tooLarge = protowire.AppendVarint(tooLarge, maxSCIPMessageSize+1)
+	if _, err := readSCIPIndex(bytes.NewReader(tooLarge)); err == nil {
+		t.Fatalf("expected error reading index with too large document")
+	}
+
+	if _, err := readSCIPIndex(bytes.NewReader(content[:len(content)-1])); err == nil {
+		t.Fatalf("expected error reading truncated index")
+	}
+}
+
 //
 //
 
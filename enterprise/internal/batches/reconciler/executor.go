@@ -91,7 +91,7 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 			err = e.importChangeset(ctx)
 
 		case btypes.ReconcilerOperationPush:
-			err = e.pushChangesetPatch(ctx)
+			err = e.pushChangesetPatch(ctx, plan.Ops.Contains(btypes.ReconcilerOperationPublishDraft))
 
 		case btypes.ReconcilerOperationPublish:
 			err = e.publishChangeset(ctx, false)
@@ -150,7 +150,8 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 var errCannotPushToArchivedRepo = errcode.MakeNonRetryable(errors.New("cannot push to an archived repo"))
 
 // pushChangesetPatch creates the commits for the changeset on its codehost.
-func (e *executor) pushChangesetPatch(ctx context.Context) (err error) {
+// asDraft is true if the changeset is published as a draft after the push.
+func (e *executor) pushChangesetPatch(ctx context.Context, asDraft bool) (err error) {
 	existingSameBranch, err := e.tx.GetChangeset(ctx, store.GetChangesetOpts{
 		ExternalServiceType: e.ch.ExternalServiceType,
 		RepoID:              e.ch.RepoID,
@@ -193,6 +194,28 @@ func (e *executor) pushChangesetPatch(ctx context.Context) (err error) {
 		return err
 	}
 
+	// Some code hosts create and update changesets from the pushed commit
+	// itself, so the source may need to adjust the commit before it is pushed.
+	if ccss, ok := css.(sources.CommitDecoratingChangesetSource); ok {
+		body, err := e.decorateChangesetBody(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "decorating body for changeset %d", e.ch.ID)
+		}
+
+		cs := &sources.Changeset{
+			Title:      e.spec.Title,
+			Body:       body,
+			BaseRef:    e.spec.BaseRef,
+			HeadRef:    e.spec.HeadRef,
+			RemoteRepo: remoteRepo,
+			TargetRepo: e.targetRepo,
+			Changeset:  e.ch,
+		}
+		if err := ccss.DecorateCommitOpts(cs, asDraft, &opts); err != nil {
+			return errors.Wrap(err, "decorating commit")
+		}
+	}
+
 	err = e.pushCommit(ctx, opts)
 	var pce pushCommitError
 	if errors.As(err, &pce) {
@@ -50,6 +50,16 @@ func (ops Operations) Equal(b Operations) bool {
 	return true
 }
 
+// Contains returns true if ops contains op.
+func (ops Operations) Contains(op btypes.ReconcilerOperation) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
 func (ops Operations) String() string {
 	if ops.IsNone() {
 		return "No operations required"
@@ -0,0 +1,311 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awscredentials "github.com/aws/aws-sdk-go-v2/credentials"
+	"golang.org/x/net/http2"
+
+	awscodecommitbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// AWSCodeCommitSource is a ChangesetSource for AWS CodeCommit pull requests.
+// The API is only accessible with the access keys of the code host connection,
+// so the authenticator of the source is only used to push commits, which AWS
+// CodeCommit authenticates with separate Git credentials.
+type AWSCodeCommitSource struct {
+	client *awscodecommit.Client
+	region string
+	au     auth.Authenticator
+}
+
+func NewAWSCodeCommitSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*AWSCodeCommitSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.AWSCodeCommitConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	// Use the same transport the AWS SDK would use, like the repo syncer does.
+	cli, err := cf.Doer(func(c *http.Client) error {
+		tr := awshttp.NewBuildableClient().GetTransport()
+		if err := http2.ConfigureTransport(tr); err != nil {
+			return err
+		}
+		c.Transport = tr
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating external client")
+	}
+
+	awsConfig, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(c.Region),
+		config.WithCredentialsProvider(
+			awscredentials.StaticCredentialsProvider{
+				Value: aws.Credentials{
+					AccessKeyID:     c.AccessKeyID,
+					SecretAccessKey: c.SecretAccessKey,
+					Source:          "sourcegraph-site-configuration",
+				},
+			},
+		),
+		config.WithHTTPClient(cli),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AWS config")
+	}
+
+	return &AWSCodeCommitSource{
+		client: awscodecommit.NewClient(awsConfig),
+		region: c.Region,
+		au: &auth.BasicAuth{
+			Username: c.GitCredentials.Username,
+			Password: c.GitCredentials.Password,
+		},
+	}, nil
+}
+
+// GitserverPushConfig returns an authenticated push config used for pushing
+// commits to the code host.
+func (s AWSCodeCommitSource) GitserverPushConfig(repo *types.Repo) (*protocol.PushConfig, error) {
+	return GitserverPushConfig(repo, s.au)
+}
+
+// WithAuthenticator returns a copy of the original Source configured to use the
+// given authenticator, provided that authenticator type is supported by the
+// code host.
+func (s AWSCodeCommitSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("AWSCodeCommitSource", a)
+	}
+
+	return &AWSCodeCommitSource{client: s.client, region: s.region, au: a}, nil
+}
+
+// ValidateAuthenticator validates the currently set authenticator is usable.
+// Returns an error, when validating the Authenticator yielded an error.
+//
+// Git credentials for AWS CodeCommit can't be used with the API, so they can
+// only be validated when commits are pushed.
+func (s AWSCodeCommitSource) ValidateAuthenticator(ctx context.Context) error {
+	return nil
+}
+
+// LoadChangeset loads the given Changeset from the source and updates it. If
+// the Changeset could not be found on the source, a ChangesetNotFoundError is
+// returned.
+func (s AWSCodeCommitSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	pr, err := s.client.GetPullRequest(ctx, cs.ExternalID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, pr, cs)
+}
+
+// CreateChangeset will create the Changeset on the source. If it already
+// exists, *Changeset will be populated and the return value will be true.
+func (s AWSCodeCommitSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	return s.createPullRequest(ctx, cs, fmt.Sprintf("sourcegraph-changeset-%d", cs.ID))
+}
+
+// CloseChangeset will close the Changeset on the source, where "close"
+// means the appropriate final state on the codehost (e.g. "closed" on
+// AWS CodeCommit).
+func (s AWSCodeCommitSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	pr := cs.Metadata.(*awscodecommitbatches.AnnotatedPullRequest)
+
+	updated, err := s.client.ClosePullRequest(ctx, pr.ID)
+	if err != nil {
+		return errors.Wrap(err, "closing pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, updated, cs)
+}
+
+// UpdateChangeset can update Changesets. AWS CodeCommit doesn't allow the
+// destination branch of a pull request to be changed, so if the base ref
+// changed the pull request is closed and replaced by a new one for the new
+// destination branch. Otherwise only the title and description are updated.
+func (s AWSCodeCommitSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	pr := cs.Metadata.(*awscodecommitbatches.AnnotatedPullRequest)
+
+	if gitdomain.EnsureRefPrefix(cs.BaseRef) != pr.Target().DestinationReference {
+		if pr.Status == awscodecommit.PullRequestStatusOpen {
+			if _, err := s.client.ClosePullRequest(ctx, pr.ID); err != nil {
+				return errors.Wrap(err, "closing pull request")
+			}
+		}
+
+		// As when reopening, the request token includes the ID of the
+		// replaced pull request so that it doesn't match the token the
+		// replaced pull request was created with.
+		_, err := s.createPullRequest(ctx, cs, fmt.Sprintf("sourcegraph-changeset-%d-base-%s", cs.ID, pr.ID))
+		return err
+	}
+
+	updated := pr.PullRequest
+
+	if cs.Title != updated.Title {
+		var err error
+		if updated, err = s.client.UpdatePullRequestTitle(ctx, pr.ID, cs.Title); err != nil {
+			return errors.Wrap(err, "updating pull request title")
+		}
+	}
+
+	if cs.Body != updated.Description {
+		var err error
+		if updated, err = s.client.UpdatePullRequestDescription(ctx, pr.ID, cs.Body); err != nil {
+			return errors.Wrap(err, "updating pull request description")
+		}
+	}
+
+	return s.setChangesetMetadata(ctx, updated, cs)
+}
+
+// ReopenChangeset will reopen the Changeset on the source, if it's closed.
+// If not, it's a noop.
+func (s AWSCodeCommitSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	pr := cs.Metadata.(*awscodecommitbatches.AnnotatedPullRequest)
+	if pr.Status != awscodecommit.PullRequestStatusClosed || pr.Target().IsMerged {
+		return nil
+	}
+
+	// AWS CodeCommit can't reopen a closed pull request, so we create a new
+	// one for the same branches instead, which replaces the closed pull
+	// request as the changeset's pull request. The request token includes the
+	// ID of the closed pull request, so that it doesn't match the token the
+	// closed pull request was created with.
+	_, err := s.createPullRequest(ctx, cs, fmt.Sprintf("sourcegraph-changeset-%d-reopen-%s", cs.ID, pr.ID))
+	return err
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s AWSCodeCommitSource) CreateComment(ctx context.Context, cs *Changeset, comment string) error {
+	pr := cs.Metadata.(*awscodecommitbatches.AnnotatedPullRequest)
+
+	return s.client.CreatePullRequestComment(ctx, pr.PullRequest, comment)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is true, a squash merge is performed, otherwise a three-way merge.
+// If the changeset cannot be merged, because it is in an unmergeable state,
+// ChangesetNotMergeableError is returned.
+func (s AWSCodeCommitSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	pr := cs.Metadata.(*awscodecommitbatches.AnnotatedPullRequest)
+
+	updated, err := s.client.MergePullRequest(ctx, pr.PullRequest, squash)
+	if err != nil {
+		if awscodecommit.IsNotMergeable(err) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "merging pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, updated, cs)
+}
+
+// createPullRequest creates the pull request of the given changeset, unless
+// an open pull request for the same branches already exists, in which case
+// that pull request is loaded and the return value is true. The request token
+// makes retries of the request return the pull request created by the first
+// attempt, rather than creating a duplicate pull request.
+func (s AWSCodeCommitSource) createPullRequest(ctx context.Context, cs *Changeset, token string) (bool, error) {
+	repo := cs.TargetRepo.Metadata.(*awscodecommit.Repository)
+	source := gitdomain.EnsureRefPrefix(cs.HeadRef)
+	destination := gitdomain.EnsureRefPrefix(cs.BaseRef)
+
+	open, err := s.client.ListOpenPullRequests(ctx, repo.Name)
+	if err != nil {
+		return false, errors.Wrap(err, "listing open pull requests")
+	}
+	for _, pr := range open {
+		if t := pr.Target(); t.SourceReference == source && t.DestinationReference == destination {
+			if err := s.setChangesetMetadata(ctx, pr, cs); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	pr, err := s.client.CreatePullRequest(ctx, awscodecommit.CreatePullRequestInput{
+		RepositoryName:       repo.Name,
+		Title:                cs.Title,
+		Description:          cs.Body,
+		SourceReference:      source,
+		DestinationReference: destination,
+		ClientRequestToken:   token,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "creating pull request")
+	}
+
+	if err := s.setChangesetMetadata(ctx, pr, cs); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (s AWSCodeCommitSource) annotatePullRequest(ctx context.Context, pr *awscodecommit.PullRequest) (*awscodecommitbatches.AnnotatedPullRequest, error) {
+	approvals, err := s.client.GetPullRequestApprovals(ctx, pr)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting pull request approvals")
+	}
+
+	evaluation, err := s.client.EvaluatePullRequestApprovalRules(ctx, pr)
+	if err != nil {
+		return nil, errors.Wrap(err, "evaluating pull request approval rules")
+	}
+
+	return &awscodecommitbatches.AnnotatedPullRequest{
+		PullRequest: pr,
+		Approvals:   approvals,
+		Evaluation:  evaluation,
+		Region:      s.region,
+	}, nil
+}
+
+func (s AWSCodeCommitSource) setChangesetMetadata(ctx context.Context, pr *awscodecommit.PullRequest, cs *Changeset) error {
+	apr, err := s.annotatePullRequest(ctx, pr)
+	if err != nil {
+		return errors.Wrap(err, "annotating pull request")
+	}
+
+	if err := cs.SetMetadata(apr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
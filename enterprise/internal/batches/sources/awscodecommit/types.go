@@ -0,0 +1,43 @@
+package awscodecommit
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+)
+
+// AnnotatedPullRequest adds metadata we need that lives outside the main
+// PullRequest type returned by the AWS CodeCommit API alongside the pull
+// request. This type is used as the primary metadata type for AWS CodeCommit
+// changesets.
+type AnnotatedPullRequest struct {
+	*awscodecommit.PullRequest
+	Approvals  []*awscodecommit.Approval
+	Evaluation *awscodecommit.ApprovalEvaluation
+	// Region is the AWS region of the repository, since the API does not
+	// return a link to the pull request.
+	Region string
+}
+
+// URL returns the URL of the pull request in the AWS console.
+func (pr *AnnotatedPullRequest) URL() string {
+	return fmt.Sprintf(
+		"https://%s.console.aws.amazon.com/codesuite/codecommit/repositories/%s/pull-requests/%s/details?region=%s",
+		pr.Region,
+		url.PathEscape(pr.Target().RepositoryName),
+		url.PathEscape(pr.ID),
+		url.QueryEscape(pr.Region),
+	)
+}
+
+// AuthorName returns the name of the IAM user or role that created the pull
+// request, which is the last segment of the author ARN.
+func (pr *AnnotatedPullRequest) AuthorName() string {
+	arn := pr.AuthorARN
+	if i := strings.LastIndexAny(arn, ":/"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
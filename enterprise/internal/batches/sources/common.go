@@ -3,6 +3,7 @@ package sources
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
@@ -48,6 +49,19 @@ type DraftChangesetSource interface {
 	UndraftChangeset(context.Context, *Changeset) error
 }
 
+// A CommitDecoratingChangesetSource needs to adjust the commit of a changeset
+// before it is pushed, because the code host derives the changeset from the
+// commit itself rather than from a branch.
+type CommitDecoratingChangesetSource interface {
+	ChangesetSource
+
+	// DecorateCommitOpts modifies the options used to create and push the
+	// commit of the given Changeset, for example to add trailers to the commit
+	// message or to push to a different ref on the code host. asDraft is true
+	// if the changeset is published as a draft after the push.
+	DecorateCommitOpts(cs *Changeset, asDraft bool, opts *protocol.CreateCommitFromPatchRequest) error
+}
+
 type ForkableChangesetSource interface {
 	ChangesetSource
 
@@ -140,7 +154,9 @@ func (c *Changeset) IsOutdated() (bool, error) {
 		return false, err
 	}
 
-	if currentTitle != c.Title {
+	// Code hosts may normalize whitespace around the title and body, for
+	// example when they are stored in a commit message.
+	if strings.TrimSpace(currentTitle) != strings.TrimSpace(c.Title) {
 		return true, nil
 	}
 
@@ -149,7 +165,7 @@ func (c *Changeset) IsOutdated() (bool, error) {
 		return false, err
 	}
 
-	if currentBody != c.Body {
+	if strings.TrimSpace(currentBody) != strings.TrimSpace(c.Body) {
 		return true, nil
 	}
 
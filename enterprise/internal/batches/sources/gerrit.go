@@ -0,0 +1,291 @@
+package sources
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/url"
+	"strings"
+
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// GerritSource is a ChangesetSource for Gerrit changes. Gerrit has no pull
+// requests: a change is created or updated by pushing a commit with a
+// Change-Id trailer to the magic refs/for/<branch> ref, so the source takes
+// care of preparing the commit before it is pushed.
+type GerritSource struct {
+	client *gerrit.Client
+}
+
+var (
+	_ CommitDecoratingChangesetSource = GerritSource{}
+	_ DraftChangesetSource            = GerritSource{}
+)
+
+func NewGerritSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, errors.Wrap(err, "creating external client")
+	}
+
+	client, err := gerrit.NewClient(svc.URN(), &c, cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Gerrit client")
+	}
+
+	return &GerritSource{client: client}, nil
+}
+
+// GitserverPushConfig returns an authenticated push config used for pushing
+// commits to the code host.
+func (s GerritSource) GitserverPushConfig(repo *types.Repo) (*protocol.PushConfig, error) {
+	return GitserverPushConfig(repo, s.client.Authenticator())
+}
+
+// WithAuthenticator returns a copy of the original Source configured to use the
+// given authenticator, provided that authenticator type is supported by the
+// code host.
+func (s GerritSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("GerritSource", a)
+	}
+
+	return &GerritSource{client: s.client.WithAuthenticator(a)}, nil
+}
+
+// ValidateAuthenticator validates the currently set authenticator is usable.
+// Returns an error, when validating the Authenticator yielded an error.
+func (s GerritSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.GetAuthenticatedUserAccount(ctx)
+	return err
+}
+
+// DecorateCommitOpts adds the Change-Id trailer identifying the changeset to
+// the commit message and pushes the commit to refs/for/<base branch>, with the
+// head ref of the changeset as the topic of the change. Since the commit
+// message is the description of a Gerrit change, it is built from the title
+// and body of the changeset. Drafts are pushed with the wip option, so that
+// the change is created in work in progress mode and reviewers aren't
+// notified.
+func (s GerritSource) DecorateCommitOpts(cs *Changeset, asDraft bool, opts *protocol.CreateCommitFromPatchRequest) error {
+	opts.CommitInfo.Message = gerritCommitMessage(cs.Title, cs.Body, GerritChangeID(cs.Changeset))
+
+	pushRef := fmt.Sprintf(
+		"refs/for/%s%%topic=%s",
+		gitdomain.AbbreviateRef(cs.BaseRef),
+		url.QueryEscape(gitdomain.AbbreviateRef(cs.HeadRef)),
+	)
+	if asDraft {
+		pushRef += ",wip"
+	}
+	opts.PushRef = &pushRef
+
+	return nil
+}
+
+// LoadChangeset loads the given Changeset from the source and updates it. If
+// the Changeset could not be found on the source, a ChangesetNotFoundError is
+// returned.
+func (s GerritSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	change, err := s.client.GetChange(ctx, cs.ExternalID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting change")
+	}
+
+	return s.setChangesetMetadata(change, cs)
+}
+
+// CreateChangeset will create the Changeset on the source. If it already
+// exists, *Changeset will be populated and the return value will be true.
+func (s GerritSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	// The change has already been created by pushing the commit, so all we
+	// need to do is to look it up by its Change-Id.
+	change, err := s.client.GetChange(ctx, gerritChangeTriplet(cs))
+	if err != nil {
+		return false, errors.Wrap(err, "getting change")
+	}
+
+	if err := s.setChangesetMetadata(change, cs); err != nil {
+		return false, err
+	}
+
+	// A push which creates a change creates its first patch set, so the change
+	// already existed if the push added a later one.
+	rev, ok := change.Revisions[change.CurrentRevision]
+	return ok && rev.Number > 1, nil
+}
+
+// CreateDraftChangeset creates the given changeset on the code host in draft
+// mode, which Gerrit calls work in progress. The change has already been
+// created in work in progress mode by pushing the commit with the wip option.
+func (s GerritSource) CreateDraftChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	return s.CreateChangeset(ctx, cs)
+}
+
+// UndraftChangeset will update the Changeset on the source to be not in draft
+// mode anymore.
+func (s GerritSource) UndraftChangeset(ctx context.Context, cs *Changeset) error {
+	if err := s.client.SetReadyForReview(ctx, cs.ExternalID); err != nil {
+		return errors.Wrap(err, "marking change as ready for review")
+	}
+
+	return s.reloadChangeset(ctx, cs)
+}
+
+// CloseChangeset will close the Changeset on the source, where "close"
+// means the appropriate final state on the codehost (e.g. "abandoned" on
+// Gerrit).
+func (s GerritSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	if _, err := s.client.AbandonChange(ctx, cs.ExternalID); err != nil {
+		return errors.Wrap(err, "abandoning change")
+	}
+
+	return s.reloadChangeset(ctx, cs)
+}
+
+// UpdateChangeset can update Changesets.
+func (s GerritSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange)
+
+	if base := gitdomain.AbbreviateRef(cs.BaseRef); base != change.Branch {
+		if _, err := s.client.MoveChange(ctx, cs.ExternalID, gerrit.MoveChangeInput{DestinationBranch: base}); err != nil {
+			return errors.Wrap(err, "moving change")
+		}
+	}
+
+	// The title and body of a change are its commit message, so updating them
+	// creates a new patch set.
+	if strings.TrimSpace(cs.Title) != strings.TrimSpace(change.Subject) || strings.TrimSpace(cs.Body) != strings.TrimSpace(change.Body()) {
+		message := gerritCommitMessage(cs.Title, cs.Body, change.ChangeID)
+		if err := s.client.SetCommitMessage(ctx, cs.ExternalID, message); err != nil {
+			return errors.Wrap(err, "setting commit message")
+		}
+	}
+
+	return s.reloadChangeset(ctx, cs)
+}
+
+// ReopenChangeset will reopen the Changeset on the source, if it's closed.
+// If not, it's a noop.
+func (s GerritSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	change := cs.Metadata.(*gerritbatches.AnnotatedChange)
+	if change.Status != gerrit.ChangeStatusAbandoned {
+		return nil
+	}
+
+	if _, err := s.client.RestoreChange(ctx, cs.ExternalID); err != nil {
+		return errors.Wrap(err, "restoring change")
+	}
+
+	return s.reloadChangeset(ctx, cs)
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s GerritSource) CreateComment(ctx context.Context, cs *Changeset, comment string) error {
+	return s.client.SetReview(ctx, cs.ExternalID, gerrit.ReviewInput{Message: comment})
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// Gerrit submits changes according to the submit type configured for the
+// project, so squash is ignored. If the changeset cannot be merged, because it
+// is in an unmergeable state, ChangesetNotMergeableError is returned.
+func (s GerritSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	if _, err := s.client.SubmitChange(ctx, cs.ExternalID); err != nil {
+		if gerrit.IsNotMergeable(err) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "submitting change")
+	}
+
+	return s.reloadChangeset(ctx, cs)
+}
+
+// reloadChangeset fetches the change of the given changeset again, since most
+// of the change endpoints don't return the labels and revisions we need for
+// the metadata.
+func (s GerritSource) reloadChangeset(ctx context.Context, cs *Changeset) error {
+	change, err := s.client.GetChange(ctx, cs.ExternalID)
+	if err != nil {
+		return errors.Wrap(err, "getting change")
+	}
+
+	return s.setChangesetMetadata(change, cs)
+}
+
+func (s GerritSource) setChangesetMetadata(change *gerrit.Change, cs *Changeset) error {
+	if err := cs.SetMetadata(&gerritbatches.AnnotatedChange{
+		Change:      change,
+		CodeHostURL: s.client.URL.String(),
+	}); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
+
+// GerritChangeID returns the Change-Id used for the Gerrit change of the given
+// changeset. It is derived from the changeset so that every commit pushed for
+// the changeset updates the same change.
+func GerritChangeID(ch *btypes.Changeset) string {
+	return fmt.Sprintf("I%x", sha1.Sum([]byte(fmt.Sprintf("sourcegraph-changeset:%d:%d", ch.RepoID, ch.ID))))
+}
+
+// gerritChangeTriplet returns the "project~branch~Change-Id" triplet that
+// identifies the change of the given changeset before its number is known.
+func gerritChangeTriplet(cs *Changeset) string {
+	project := cs.TargetRepo.Metadata.(*gerrit.Project)
+
+	// Project IDs are the URL encoded project names.
+	name, err := url.PathUnescape(project.ID)
+	if err != nil {
+		name = project.ID
+	}
+
+	return strings.Join([]string{name, gitdomain.AbbreviateRef(cs.BaseRef), GerritChangeID(cs.Changeset)}, "~")
+}
+
+func gerritCommitMessage(title, body, changeID string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(title))
+	if body = strings.TrimSpace(body); body != "" {
+		b.WriteString("\n\n")
+		b.WriteString(body)
+	}
+	b.WriteString("\n\nChange-Id: ")
+	b.WriteString(changeID)
+	b.WriteString("\n")
+	return b.String()
+}
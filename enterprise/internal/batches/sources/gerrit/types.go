@@ -0,0 +1,48 @@
+package gerrit
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+)
+
+// AnnotatedChange adds metadata we need that lives outside the main Change
+// type returned by the Gerrit API alongside the change. This type is used as
+// the primary metadata type for Gerrit changesets.
+type AnnotatedChange struct {
+	*gerrit.Change
+	// CodeHostURL is the base URL of the Gerrit instance, since the API does
+	// not return a link to the change.
+	CodeHostURL string `json:"code_host_url"`
+}
+
+// URL returns the URL of the change in the Gerrit web UI.
+func (c *AnnotatedChange) URL() (string, error) {
+	u, err := url.Parse(c.CodeHostURL)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(u.Path, "c", c.Project, "+", strconv.Itoa(c.Number))
+	return u.String(), nil
+}
+
+// Body returns the commit message of the current revision of the change
+// without its subject and Change-Id footer.
+func (c *AnnotatedChange) Body() string {
+	commit := c.CurrentCommit()
+	if commit == nil {
+		return ""
+	}
+
+	// The subject is the first paragraph of the commit message.
+	_, body, _ := strings.Cut(commit.Message, "\n\n")
+
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	if n := len(lines); n > 0 && strings.HasPrefix(lines[n-1], "Change-Id: ") {
+		lines = lines[:n-1]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
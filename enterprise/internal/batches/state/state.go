@@ -11,15 +11,19 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	awscodecommitbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/awscodecommit"
 	bbcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/bitbucketcloud"
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -113,6 +117,9 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 
 	case *bbcs.AnnotatedPullRequest:
 		return computeBitbucketCloudBuildState(c.UpdatedAt, m, events)
+
+	case *gerritbatches.AnnotatedChange:
+		return computeGerritCheckState(m)
 	}
 
 	return btypes.ChangesetCheckStateUnknown
@@ -252,6 +259,35 @@ func parseBitbucketCloudBuildState(s bitbucketcloud.PullRequestStatusState) btyp
 	}
 }
 
+// computeGerritCheckState derives the check state of a change from the votes on
+// its Verified label, which is where CI systems report their results in Gerrit.
+func computeGerritCheckState(c *gerritbatches.AnnotatedChange) btypes.ChangesetCheckState {
+	label, ok := c.Labels[gerritLabelVerified]
+	if !ok {
+		return btypes.ChangesetCheckStateUnknown
+	}
+
+	if label.Rejected != nil {
+		return btypes.ChangesetCheckStateFailed
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(label.All))
+	for _, vote := range label.All {
+		switch {
+		case vote.Value < 0:
+			states = append(states, btypes.ChangesetCheckStateFailed)
+		case vote.Value > 0:
+			states = append(states, btypes.ChangesetCheckStatePassed)
+		}
+	}
+	if len(states) == 0 {
+		// The label applies to the change, but nothing has voted on it yet.
+		return btypes.ChangesetCheckStatePending
+	}
+
+	return combineCheckStates(states)
+}
+
 func computeGitHubCheckState(lastSynced time.Time, pr *github.PullRequest, events []*btypes.ChangesetEvent) btypes.ChangesetCheckState {
 	// We should only consider the latest commit. This could be from a sync or a webhook that
 	// has occurred later
@@ -516,6 +552,35 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown Bitbucket Cloud pull request state: %s", m.State)
 		}
+	case *gerritbatches.AnnotatedChange:
+		switch m.Status {
+		case gerrit.ChangeStatusAbandoned:
+			s = btypes.ChangesetExternalStateClosed
+		case gerrit.ChangeStatusMerged:
+			s = btypes.ChangesetExternalStateMerged
+		case gerrit.ChangeStatusNew:
+			if m.WorkInProgress {
+				s = btypes.ChangesetExternalStateDraft
+			} else {
+				s = btypes.ChangesetExternalStateOpen
+			}
+		default:
+			return "", errors.Errorf("unknown Gerrit change status: %s", m.Status)
+		}
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		switch m.Status {
+		case awscodecommit.PullRequestStatusClosed:
+			// Merged pull requests are closed as well.
+			if m.Target().IsMerged {
+				s = btypes.ChangesetExternalStateMerged
+			} else {
+				s = btypes.ChangesetExternalStateClosed
+			}
+		case awscodecommit.PullRequestStatusOpen:
+			s = btypes.ChangesetExternalStateOpen
+		default:
+			return "", errors.Errorf("unknown AWS CodeCommit pull request status: %s", m.Status)
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -585,6 +650,43 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 			}
 		}
 
+	case *gerritbatches.AnnotatedChange:
+		// Gerrit reviews are votes on the Code-Review label: a negative vote
+		// asks for changes, while the maximum vote of +2 approves the change.
+		label := m.Labels[gerritLabelCodeReview]
+		if label.Rejected != nil {
+			states[btypes.ChangesetReviewStateChangesRequested] = true
+		}
+		if label.Approved != nil {
+			states[btypes.ChangesetReviewStateApproved] = true
+		}
+		for _, vote := range label.All {
+			switch {
+			case vote.Value < 0:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			case vote.Value >= 2:
+				states[btypes.ChangesetReviewStateApproved] = true
+			default:
+				states[btypes.ChangesetReviewStatePending] = true
+			}
+		}
+
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		// AWS CodeCommit has no way to request changes. If the pull request
+		// has approval rules, it is approved once they are satisfied, otherwise
+		// any approval of the current revision approves it.
+		if len(m.ApprovalRules) > 0 && m.Evaluation != nil {
+			if m.Evaluation.Approved || m.Evaluation.Overridden {
+				return btypes.ChangesetReviewStateApproved, nil
+			}
+			return btypes.ChangesetReviewStatePending, nil
+		}
+		for _, a := range m.Approvals {
+			if a.State == awscodecommit.ApprovalStateApprove {
+				states[btypes.ChangesetReviewStateApproved] = true
+			}
+		}
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -592,6 +694,13 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 	return selectReviewState(states), nil
 }
 
+// The names of the Gerrit review labels the review and check states of a
+// change are derived from.
+const (
+	gerritLabelCodeReview = "Code-Review"
+	gerritLabelVerified   = "Verified"
+)
+
 // selectReviewState computes the single review state for a given set of
 // ChangesetReviewStates. Since a pull request, for example, can have multiple
 // reviews with different states, we need a function to determine what the
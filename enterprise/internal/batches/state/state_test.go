@@ -7,9 +7,13 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
+	awscodecommitbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/awscodecommit"
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
@@ -407,6 +411,54 @@ func TestComputeGitLabCheckState(t *testing.T) {
 	})
 }
 
+func TestComputeGerritCheckState(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		labels map[string]gerrit.LabelInfo
+		want   btypes.ChangesetCheckState
+	}{
+		"no verified label": {
+			labels: map[string]gerrit.LabelInfo{
+				"Code-Review": {All: []gerrit.ApprovalInfo{{Value: 2}}},
+			},
+			want: btypes.ChangesetCheckStateUnknown,
+		},
+		"no votes": {
+			labels: map[string]gerrit.LabelInfo{
+				"Verified": {All: []gerrit.ApprovalInfo{{Value: 0}}},
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		"passed": {
+			labels: map[string]gerrit.LabelInfo{
+				"Verified": {All: []gerrit.ApprovalInfo{{Value: 1}, {Value: 0}}},
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		"failed": {
+			labels: map[string]gerrit.LabelInfo{
+				"Verified": {All: []gerrit.ApprovalInfo{{Value: 1}, {Value: -1}}},
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+		"rejected": {
+			labels: map[string]gerrit.LabelInfo{
+				"Verified": {Rejected: &gerrit.Account{Username: "ci"}},
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			change := &gerritbatches.AnnotatedChange{Change: &gerrit.Change{Labels: tc.labels}}
+			have := computeGerritCheckState(change)
+			if have != tc.want {
+				t.Errorf("unexpected check state: have %s; want %s", have, tc.want)
+			}
+		})
+	}
+}
+
 func TestComputeReviewState(t *testing.T) {
 	t.Parallel()
 
@@ -536,6 +588,59 @@ func TestComputeReviewState(t *testing.T) {
 			},
 			want: btypes.ChangesetReviewStateChangesRequested,
 		},
+		{
+			name:      "gerrit - no votes",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, 0),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name:      "gerrit - looks good, but needs another approval",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, 1),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name:      "gerrit - approved",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, 2),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name:      "gerrit - changes requested",
+			changeset: gerritChangeset(daysAgo(0), gerrit.ChangeStatusNew, 2, -1),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateChangesRequested,
+		},
+		{
+			name:      "aws codecommit - no approvals",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusOpen, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name:      "aws codecommit - approved",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusOpen, false, nil, awscodecommit.ApprovalStateRevoke, awscodecommit.ApprovalStateApprove),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name: "aws codecommit - approval rules not satisfied",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusOpen, false, &awscodecommit.ApprovalEvaluation{
+				RulesNotSatisfied: []string{"two-approvals"},
+			}, awscodecommit.ApprovalStateApprove),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "aws codecommit - approval rules satisfied",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusOpen, false, &awscodecommit.ApprovalEvaluation{
+				Approved:       true,
+				RulesSatisfied: []string{"two-approvals"},
+			}, awscodecommit.ApprovalStateApprove, awscodecommit.ApprovalStateApprove),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
 	}
 
 	for i, tc := range tests {
@@ -744,6 +849,48 @@ func TestComputeExternalState(t *testing.T) {
 			},
 			want: btypes.ChangesetExternalStateReadOnly,
 		},
+		{
+			name:      "gerrit new - no events",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusNew),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "gerrit work in progress - no events",
+			changeset: setDraft(gerritChangeset(daysAgo(10), gerrit.ChangeStatusNew)),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateDraft,
+		},
+		{
+			name:      "gerrit abandoned - no events",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusAbandoned),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "gerrit merged - no events",
+			changeset: gerritChangeset(daysAgo(10), gerrit.ChangeStatusMerged),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "aws codecommit open - no events",
+			changeset: awsCodeCommitChangeset(daysAgo(10), awscodecommit.PullRequestStatusOpen, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateOpen,
+		},
+		{
+			name:      "aws codecommit closed - no events",
+			changeset: awsCodeCommitChangeset(daysAgo(10), awscodecommit.PullRequestStatusClosed, false, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "aws codecommit merged - no events",
+			changeset: awsCodeCommitChangeset(daysAgo(10), awscodecommit.PullRequestStatusClosed, true, nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
 	}
 
 	for i, tc := range tests {
@@ -900,6 +1047,48 @@ func gitLabChangeset(updatedAt time.Time, state gitlab.MergeRequestState, notes
 	}
 }
 
+func gerritChangeset(updatedAt time.Time, status gerrit.ChangeStatus, codeReviewVotes ...int) *btypes.Changeset {
+	var votes []gerrit.ApprovalInfo
+	for _, v := range codeReviewVotes {
+		votes = append(votes, gerrit.ApprovalInfo{Value: v})
+	}
+
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeGerrit,
+		UpdatedAt:           updatedAt,
+		Metadata: &gerritbatches.AnnotatedChange{
+			Change: &gerrit.Change{
+				Status: status,
+				Labels: map[string]gerrit.LabelInfo{
+					"Code-Review": {All: votes},
+				},
+			},
+		},
+	}
+}
+
+func awsCodeCommitChangeset(updatedAt time.Time, status awscodecommit.PullRequestStatus, merged bool, evaluation *awscodecommit.ApprovalEvaluation, approvals ...awscodecommit.ApprovalState) *btypes.Changeset {
+	pr := &awscodecommitbatches.AnnotatedPullRequest{
+		PullRequest: &awscodecommit.PullRequest{
+			Status:  status,
+			Targets: []awscodecommit.PullRequestTarget{{IsMerged: merged}},
+		},
+		Evaluation: evaluation,
+	}
+	if evaluation != nil {
+		pr.ApprovalRules = append(evaluation.RulesSatisfied, evaluation.RulesNotSatisfied...)
+	}
+	for _, state := range approvals {
+		pr.Approvals = append(pr.Approvals, &awscodecommit.Approval{State: state})
+	}
+
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeAWSCodeCommit,
+		UpdatedAt:           updatedAt,
+		Metadata:            pr,
+	}
+}
+
 func setDeletedAt(c *btypes.Changeset, deletedAt time.Time) *btypes.Changeset {
 	c.ExternalDeletedAt = deletedAt
 	return c
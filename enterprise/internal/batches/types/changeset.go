@@ -8,7 +8,9 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/go-diff/diff"
 
+	awscodecommitbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/awscodecommit"
 	bbcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/bitbucketcloud"
+	gerritbatches "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
@@ -420,6 +422,26 @@ func (c *Changeset) SetMetadata(meta any) error {
 		} else {
 			c.ExternalForkNamespace = ""
 		}
+	case *gerritbatches.AnnotatedChange:
+		c.Metadata = pr
+		c.ExternalID = strconv.Itoa(pr.Number)
+		c.ExternalServiceType = extsvc.TypeGerrit
+		// Gerrit changes don't have a source branch, so we push them with the
+		// head ref as their topic.
+		if pr.Topic != "" {
+			c.ExternalBranch = gitdomain.EnsureRefPrefix(pr.Topic)
+		} else {
+			c.ExternalBranch = ""
+		}
+		c.ExternalUpdatedAt = pr.Updated.Time
+		c.ExternalForkNamespace = ""
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		c.Metadata = pr
+		c.ExternalID = pr.ID
+		c.ExternalServiceType = extsvc.TypeAWSCodeCommit
+		c.ExternalBranch = gitdomain.EnsureRefPrefix(pr.Target().SourceReference)
+		c.ExternalUpdatedAt = pr.LastActivityDate
+		c.ExternalForkNamespace = ""
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -447,6 +469,10 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Title, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Subject, nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.Title, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -466,6 +492,10 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.Author.Username, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Author.Username, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Owner.Username, nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.AuthorName(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -493,6 +523,12 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		// Bitbucket Cloud does not provide the e-mail of the author under any
 		// circumstances.
 		return "", nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Owner.Email, nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		// AWS CodeCommit pull requests are authored by IAM identities, which
+		// don't have an e-mail address.
+		return "", nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -511,6 +547,10 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return m.CreatedAt.Time
 	case *bbcs.AnnotatedPullRequest:
 		return m.CreatedOn
+	case *gerritbatches.AnnotatedChange:
+		return m.Created.Time
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.CreationDate
 	default:
 		return time.Time{}
 	}
@@ -527,6 +567,10 @@ func (c *Changeset) Body() (string, error) {
 		return m.Description, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Rendered.Description.Raw, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.Body(), nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.Description, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -572,6 +616,10 @@ func (c *Changeset) URL() (s string, err error) {
 		// pull request ID, but since the link _should_ be there, we'll error
 		// instead.
 		return "", errors.New("Bitbucket Cloud pull request does not have a html link")
+	case *gerritbatches.AnnotatedChange:
+		return m.URL()
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.URL(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -761,6 +809,10 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return m.DiffRefs.HeadSHA, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Source.Commit.Hash, nil
+	case *gerritbatches.AnnotatedChange:
+		return m.CurrentRevision, nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.Target().SourceCommit, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -778,6 +830,15 @@ func (c *Changeset) HeadRef() (string, error) {
 		return "refs/heads/" + m.SourceBranch, nil
 	case *bbcs.AnnotatedPullRequest:
 		return "refs/heads/" + m.Source.Branch.Name, nil
+	case *gerritbatches.AnnotatedChange:
+		// Patch sets of a change live under refs/changes/ rather than in a
+		// branch.
+		if rev, ok := m.Revisions[m.CurrentRevision]; ok {
+			return rev.Ref, nil
+		}
+		return "", nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return gitdomain.EnsureRefPrefix(m.Target().SourceReference), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -796,6 +857,13 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return m.DiffRefs.BaseSHA, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Destination.Commit.Hash, nil
+	case *gerritbatches.AnnotatedChange:
+		if commit := m.CurrentCommit(); commit != nil && len(commit.Parents) > 0 {
+			return commit.Parents[0].Commit, nil
+		}
+		return "", nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return m.Target().DestinationCommit, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -813,6 +881,10 @@ func (c *Changeset) BaseRef() (string, error) {
 		return "refs/heads/" + m.TargetBranch, nil
 	case *bbcs.AnnotatedPullRequest:
 		return "refs/heads/" + m.Destination.Branch.Name, nil
+	case *gerritbatches.AnnotatedChange:
+		return "refs/heads/" + m.Branch, nil
+	case *awscodecommitbatches.AnnotatedPullRequest:
+		return gitdomain.EnsureRefPrefix(m.Target().DestinationReference), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
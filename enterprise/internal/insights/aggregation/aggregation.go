@@ -12,6 +12,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/codeownership"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
@@ -103,6 +105,42 @@ func countAuthor(r result.Match) (map[MatchKey]int, error) {
 	return nil, nil
 }
 
+// countOwnerFunc returns a function counting file matches by the owners of the
+// file, as assigned by the CODEOWNERS file of the repository at the matched
+// commit. Files without owners are not counted.
+func countOwnerFunc(ctx context.Context, gitserverClient gitserver.Client) AggregationCountFunc {
+	rules := codeownership.NewRulesCache()
+
+	return func(r result.Match) (map[MatchKey]int, error) {
+		match, ok := r.(*result.FileMatch)
+		if !ok {
+			return nil, nil
+		}
+
+		ruleset, err := rules.GetFromCacheOrFetch(ctx, gitserverClient, match.Repo.Name, match.CommitID)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetFromCacheOrFetch")
+		}
+		owners, err := ruleset.Match(match.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "Match")
+		}
+		if len(owners) == 0 {
+			return nil, nil
+		}
+
+		matches := make(map[MatchKey]int, len(owners))
+		for _, owner := range owners {
+			matches[MatchKey{
+				RepoID: int32(r.RepoName().ID),
+				Repo:   string(r.RepoName().Name),
+				Group:  owner.String(),
+			}] = r.ResultCount()
+		}
+		return matches, nil
+	}
+}
+
 func countCaptureGroupsFunc(querystring string) (AggregationCountFunc, error) {
 	pattern, err := getCasedPattern(querystring)
 	if err != nil {
@@ -166,7 +204,7 @@ func matchContent(event result.Match) []string {
 	}
 }
 
-func GetCountFuncForMode(query, patternType string, mode types.SearchAggregationMode) (AggregationCountFunc, error) {
+func GetCountFuncForMode(ctx context.Context, gitserverClient gitserver.Client, query, patternType string, mode types.SearchAggregationMode) (AggregationCountFunc, error) {
 	modeCountTypes := map[types.SearchAggregationMode]AggregationCountFunc{
 		types.REPO_AGGREGATION_MODE:   countRepo,
 		types.PATH_AGGREGATION_MODE:   countPath,
@@ -181,6 +219,10 @@ func GetCountFuncForMode(query, patternType string, mode types.SearchAggregation
 		modeCountTypes[types.CAPTURE_GROUP_AGGREGATION_MODE] = captureGroupsCount
 	}
 
+	if mode == types.OWNER_AGGREGATION_MODE {
+		modeCountTypes[types.OWNER_AGGREGATION_MODE] = countOwnerFunc(ctx, gitserverClient)
+	}
+
 	modeCountFunc, ok := modeCountTypes[mode]
 	if !ok {
 		return nil, errors.Newf("unsupported aggregation mode: %s for query", mode)
@@ -9,10 +9,13 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	internaltypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func newTestSearchResultsAggregator(ctx context.Context, tabulator AggregationTabulator, countFunc AggregationCountFunc) SearchResultsAggregator {
@@ -240,7 +243,7 @@ func TestRepoAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, _ := GetCountFuncForMode("", "", tc.mode)
+			countFunc, _ := GetCountFuncForMode(context.Background(), nil, "", "", tc.mode)
 			sra := newTestSearchResultsAggregator(context.Background(), aggregator.AddResult, countFunc)
 			sra.Send(tc.searchEvent)
 			tc.want.Equal(t, aggregator.results)
@@ -302,7 +305,7 @@ func TestAuthorAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, _ := GetCountFuncForMode("", "", tc.mode)
+			countFunc, _ := GetCountFuncForMode(context.Background(), nil, "", "", tc.mode)
 			sra := newTestSearchResultsAggregator(context.Background(), aggregator.AddResult, countFunc)
 			sra.Send(tc.searchEvent)
 			tc.want.Equal(t, aggregator.results)
@@ -399,7 +402,67 @@ func TestPathAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, _ := GetCountFuncForMode("", "", tc.mode)
+			countFunc, _ := GetCountFuncForMode(context.Background(), nil, "", "", tc.mode)
+			sra := newTestSearchResultsAggregator(context.Background(), aggregator.AddResult, countFunc)
+			sra.Send(tc.searchEvent)
+			tc.want.Equal(t, aggregator.results)
+		})
+	}
+}
+
+func TestOwnerAggregation(t *testing.T) {
+	codeowners := map[api.RepoName]string{
+		"repoA": "*.go @team-go\nREADME.md @alice docs@example.com\n",
+	}
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.ReadFileFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, _ api.CommitID, path string, _ authz.SubRepoPermissionChecker) ([]byte, error) {
+		content, ok := codeowners[repo]
+		if !ok || path != "CODEOWNERS" {
+			return nil, errors.New("file does not exist")
+		}
+		return []byte(content), nil
+	})
+
+	testCases := []struct {
+		mode        types.SearchAggregationMode
+		searchEvent streaming.SearchEvent
+		want        autogold.Value
+	}{
+		{types.OWNER_AGGREGATION_MODE, streaming.SearchEvent{}, autogold.Want("No results", map[string]int{})},
+		{
+			types.OWNER_AGGREGATION_MODE,
+			streaming.SearchEvent{
+				Results: []result.Match{contentMatch("repoB", "file.go", 2, "a", "b")},
+			},
+			autogold.Want("No owner without CODEOWNERS file", map[string]int{}),
+		},
+		{
+			types.OWNER_AGGREGATION_MODE,
+			streaming.SearchEvent{
+				Results: []result.Match{
+					repoMatch("repoA", 1),
+					commitMatch("repoA", "Author A", sampleDate, 1, 2, "a"),
+				},
+			},
+			autogold.Want("No owner for repo and commit matches", map[string]int{}),
+		},
+		{
+			types.OWNER_AGGREGATION_MODE,
+			streaming.SearchEvent{
+				Results: []result.Match{
+					contentMatch("repoA", "file.go", 1, "a", "b"),
+					pathMatch("repoA", "other.go", 1),
+					contentMatch("repoA", "README.md", 1, "a"),
+					contentMatch("repoA", "file.ts", 1, "a"),
+				},
+			},
+			autogold.Want("counts by owner", map[string]int{"@alice": 1, "@team-go": 3, "docs@example.com": 1}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want.Name(), func(t *testing.T) {
+			aggregator := testAggregator{results: make(map[string]int)}
+			countFunc, _ := GetCountFuncForMode(context.Background(), gitserverClient, "", "", tc.mode)
 			sra := newTestSearchResultsAggregator(context.Background(), aggregator.AddResult, countFunc)
 			sra.Send(tc.searchEvent)
 			tc.want.Equal(t, aggregator.results)
@@ -588,7 +651,7 @@ func TestCaptureGroupAggregation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, err := GetCountFuncForMode(tc.query, "regexp", tc.mode)
+			countFunc, err := GetCountFuncForMode(context.Background(), nil, tc.query, "regexp", tc.mode)
 			if err != nil {
 				t.Errorf("expected test not to error, got %v", err)
 				t.FailNow()
@@ -624,7 +687,7 @@ func TestAggregationCancelation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.want.Name(), func(t *testing.T) {
 			aggregator := testAggregator{results: make(map[string]int)}
-			countFunc, err := GetCountFuncForMode(tc.query, "regexp", tc.mode)
+			countFunc, err := GetCountFuncForMode(context.Background(), nil, tc.query, "regexp", tc.mode)
 			if err != nil {
 				t.Errorf("expected test not to error, got %v", err)
 				t.FailNow()
@@ -274,6 +274,30 @@ func AddFileFilter(query BasicQuery, file string) (BasicQuery, error) {
 	return addFilterSimple(query, searchquery.FieldFile, file)
 }
 
+// AddOwnerFilter adds a file:has.owner() predicate for the given CODEOWNERS owner,
+// such as @alice or alice@example.com, to each step of the query. Searches only
+// apply the predicate if the experimental code-ownership feature flag is
+// enabled, so callers must check the flag first.
+func AddOwnerFilter(query BasicQuery, owner string) (BasicQuery, error) {
+	plan, err := searchquery.Pipeline(searchquery.Init(string(query), searchquery.SearchTypeLiteral))
+	if err != nil {
+		return "", err
+	}
+
+	mutatedQuery := searchquery.MapPlan(plan, func(basic searchquery.Basic) searchquery.Basic {
+		modified := make([]searchquery.Parameter, 0, len(basic.Parameters)+1)
+		modified = append(modified, basic.Parameters...)
+		modified = append(modified, searchquery.Parameter{
+			Field:      searchquery.FieldFile,
+			Value:      fmt.Sprintf("has.owner(%s)", owner),
+			Negated:    false,
+			Annotation: searchquery.Annotation{Labels: searchquery.IsPredicate},
+		})
+		return basic.MapParameters(modified)
+	})
+	return BasicQuery(searchquery.StringHuman(mutatedQuery.ToQ())), nil
+}
+
 func buildFilterText(raw string) string {
 	quoted := regexp.QuoteMeta(raw)
 	if strings.Contains(raw, " ") {
@@ -601,3 +601,37 @@ func Test_addFileFilter(t *testing.T) {
 		})
 	}
 }
+
+func Test_addOwnerFilter(t *testing.T) {
+	tests := []struct {
+		input string
+		owner string
+		want  autogold.Value
+	}{
+		{
+			input: "myquery",
+			owner: "@sourcegraph/search",
+			want:  autogold.Want("team owner", BasicQuery("file:has.owner(@sourcegraph/search) myquery")),
+		},
+		{
+			input: "myquery repo:supergreat",
+			owner: "alice@example.com",
+			want:  autogold.Want("email owner with initial repo filter", BasicQuery("repo:supergreat file:has.owner(alice@example.com) myquery")),
+		},
+		{
+			input: "(myquery repo:supergreat) or (big repo:asdf)",
+			owner: "@sqs",
+			want:  autogold.Want("compound query adding owner", BasicQuery("(repo:supergreat file:has.owner(@sqs) myquery OR repo:asdf file:has.owner(@sqs) big)")),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.want.Name(), func(t *testing.T) {
+			got, err := AddOwnerFilter(BasicQuery(test.input), test.owner)
+			if err != nil {
+				test.want.Equal(t, err.Error())
+			} else {
+				test.want.Equal(t, got)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
@@ -36,6 +38,8 @@ const (
 const invalidQueryMsg = "Grouping is disabled because the search query is not valid."
 const fileUnsupportedFieldValueFmt = `Grouping by file is not available for searches with "%s:%s".`
 const authNotCommitDiffMsg = "Grouping by author is only available for diff and commit searches."
+const ownerUnsupportedFieldValueFmt = `Grouping by owner is not available for searches with "%s:%s".`
+const ownerFeatureFlagMsg = "Grouping by owner requires the experimental code-ownership feature flag."
 const cgInvalidQueryMsg = "Grouping by capture group is only available for regexp searches that contain a capturing group."
 const cgMultipleQueryPatternMsg = "Grouping by capture group does not support search patterns with the following: and, or, negation."
 const cgUnsupportedSelectFmt = `Grouping by capture group is not available for searches with "%s:%s".`
@@ -68,8 +72,9 @@ func (r *searchAggregateResolver) getLogger() log.Logger {
 
 func (r *searchAggregateResolver) ModeAvailability(ctx context.Context) []graphqlbackend.AggregationModeAvailabilityResolver {
 	resolvers := []graphqlbackend.AggregationModeAvailabilityResolver{}
+	codeOwnership := codeOwnershipEnabled(ctx)
 	for _, mode := range types.SearchAggregationModes {
-		resolvers = append(resolvers, newAggregationModeAvailabilityResolver(r.searchQuery, r.patternType, mode))
+		resolvers = append(resolvers, newAggregationModeAvailabilityResolver(r.searchQuery, r.patternType, mode, codeOwnership))
 	}
 	return resolvers
 }
@@ -97,7 +102,7 @@ func (r *searchAggregateResolver) Aggregations(ctx context.Context, args graphql
 		aggregationMode = types.SearchAggregationMode(*args.Mode)
 	}
 
-	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, aggregationMode)
+	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, aggregationMode, codeOwnershipEnabled(ctx))
 	if notAvailable != nil {
 		return &searchAggregationResultResolver{resolver: newSearchAggregationNotAvailableResolver(*notAvailable, aggregationMode)}, nil
 	}
@@ -139,7 +144,7 @@ func (r *searchAggregateResolver) Aggregations(ctx context.Context, args graphql
 		cappedAggregator.Add(amr.Key.Group, int32(amr.Count))
 	}
 
-	countingFunc, err := aggregation.GetCountFuncForMode(r.searchQuery, r.patternType, aggregationMode)
+	countingFunc, err := aggregation.GetCountFuncForMode(ctx, gitserver.NewClient(r.postgresDB), r.searchQuery, r.patternType, aggregationMode)
 	if err != nil {
 		r.getLogger().Debug("no aggregation counting function for mode", log.String("mode", string(aggregationMode)), log.Error(err))
 		return &searchAggregationResultResolver{
@@ -311,14 +316,15 @@ func buildResults(aggregator aggregation.LimitedAggregator, limit int, mode type
 	}
 }
 
-func newAggregationModeAvailabilityResolver(searchQuery string, patternType string, mode types.SearchAggregationMode) graphqlbackend.AggregationModeAvailabilityResolver {
-	return &aggregationModeAvailabilityResolver{searchQuery: searchQuery, patternType: patternType, mode: mode}
+func newAggregationModeAvailabilityResolver(searchQuery string, patternType string, mode types.SearchAggregationMode, codeOwnership bool) graphqlbackend.AggregationModeAvailabilityResolver {
+	return &aggregationModeAvailabilityResolver{searchQuery: searchQuery, patternType: patternType, mode: mode, codeOwnership: codeOwnership}
 }
 
 type aggregationModeAvailabilityResolver struct {
-	searchQuery string
-	patternType string
-	mode        types.SearchAggregationMode
+	searchQuery   string
+	patternType   string
+	mode          types.SearchAggregationMode
+	codeOwnership bool
 }
 
 func (r *aggregationModeAvailabilityResolver) Mode() string {
@@ -326,7 +332,7 @@ func (r *aggregationModeAvailabilityResolver) Mode() string {
 }
 
 func (r *aggregationModeAvailabilityResolver) Available() bool {
-	canAggregateByFunc := getAggregateBy(r.mode)
+	canAggregateByFunc := getAggregateBy(r.mode, r.codeOwnership)
 	if canAggregateByFunc == nil {
 		return false
 	}
@@ -338,7 +344,7 @@ func (r *aggregationModeAvailabilityResolver) Available() bool {
 }
 
 func (r *aggregationModeAvailabilityResolver) ReasonUnavailable() (*string, error) {
-	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, r.mode)
+	notAvailable, err := getNotAvailableReason(r.searchQuery, r.patternType, r.mode, r.codeOwnership)
 	if err != nil {
 		return nil, err
 	}
@@ -349,8 +355,8 @@ func (r *aggregationModeAvailabilityResolver) ReasonUnavailable() (*string, erro
 
 }
 
-func getNotAvailableReason(query, patternType string, mode types.SearchAggregationMode) (*notAvailableReason, error) {
-	canAggregateByFunc := getAggregateBy(mode)
+func getNotAvailableReason(query, patternType string, mode types.SearchAggregationMode, codeOwnership bool) (*notAvailableReason, error) {
+	canAggregateByFunc := getAggregateBy(mode, codeOwnership)
 	if canAggregateByFunc == nil {
 		reason := fmt.Sprintf(`Grouping by "%v" is not supported.`, mode)
 		return &notAvailableReason{reason: reason, reasonType: types.ERROR_OCCURRED}, nil
@@ -363,12 +369,19 @@ func getNotAvailableReason(query, patternType string, mode types.SearchAggregati
 	return nil, err
 }
 
-func getAggregateBy(mode types.SearchAggregationMode) canAggregateBy {
+// getAggregateBy returns the function which checks whether searches can be
+// aggregated by mode. Aggregating by owner is only available if code ownership
+// is enabled, since searches ignore ownership otherwise.
+func getAggregateBy(mode types.SearchAggregationMode, codeOwnership bool) canAggregateBy {
+	if mode == types.OWNER_AGGREGATION_MODE && !codeOwnership {
+		return cannotAggregateByOwner
+	}
 	checkByMode := map[types.SearchAggregationMode]canAggregateBy{
 		types.REPO_AGGREGATION_MODE:          canAggregateByRepo,
 		types.PATH_AGGREGATION_MODE:          canAggregateByPath,
 		types.AUTHOR_AGGREGATION_MODE:        canAggregateByAuthor,
 		types.CAPTURE_GROUP_AGGREGATION_MODE: canAggregateByCaptureGroup,
+		types.OWNER_AGGREGATION_MODE:         canAggregateByOwner,
 	}
 	canAggregateByFunc, ok := checkByMode[mode]
 	if !ok {
@@ -431,6 +444,36 @@ func canAggregateByAuthor(searchQuery, patternType string) (bool, *notAvailableR
 	return false, &notAvailableReason{reason: authNotCommitDiffMsg, reasonType: types.INVALID_AGGREGATION_MODE_FOR_QUERY}, nil
 }
 
+func canAggregateByOwner(searchQuery, patternType string) (bool, *notAvailableReason, error) {
+	plan, err := querybuilder.ParseQuery(searchQuery, patternType)
+	if err != nil {
+		return false, &notAvailableReason{reason: invalidQueryMsg, reasonType: types.INVALID_QUERY}, errors.Wrapf(err, "ParseQuery")
+	}
+	parameters := querybuilder.ParametersFromQueryPlan(plan)
+	// ownership is assigned to files, so we cannot aggregate over:
+	// - searches by commit, diff or repo
+	for _, parameter := range parameters {
+		if parameter.Field == query.FieldSelect || parameter.Field == query.FieldType {
+			if strings.EqualFold(parameter.Value, "commit") || strings.EqualFold(parameter.Value, "diff") || strings.EqualFold(parameter.Value, "repo") {
+				reason := fmt.Sprintf(ownerUnsupportedFieldValueFmt,
+					parameter.Field, parameter.Value)
+				return false, &notAvailableReason{reason: reason, reasonType: types.INVALID_AGGREGATION_MODE_FOR_QUERY}, nil
+			}
+		}
+	}
+	return true, nil, nil
+}
+
+func cannotAggregateByOwner(searchQuery, patternType string) (bool, *notAvailableReason, error) {
+	return false, &notAvailableReason{reason: ownerFeatureFlagMsg, reasonType: types.INVALID_AGGREGATION_MODE_FOR_QUERY}, nil
+}
+
+// codeOwnershipEnabled returns true if the experimental code-ownership feature
+// flag is enabled for the request, like search does.
+func codeOwnershipEnabled(ctx context.Context) bool {
+	return featureflag.FromContext(ctx).GetBoolOr("code-ownership", false)
+}
+
 func canAggregateByCaptureGroup(searchQuery, patternType string) (bool, *notAvailableReason, error) {
 
 	plan, err := querybuilder.ParseQuery(searchQuery, patternType)
@@ -587,6 +630,8 @@ func buildDrilldownQuery(mode types.SearchAggregationMode, originalQuery string,
 		modifierFunc = querybuilder.AddFileFilter
 	case types.AUTHOR_AGGREGATION_MODE:
 		modifierFunc = querybuilder.AddAuthorFilter
+	case types.OWNER_AGGREGATION_MODE:
+		modifierFunc = querybuilder.AddOwnerFilter
 	case types.CAPTURE_GROUP_AGGREGATION_MODE:
 		searchType, err := client.SearchTypeFromString(patternType)
 		if err != nil {
@@ -128,6 +128,64 @@ func Test_canAggregateByPath(t *testing.T) {
 	suite.Test_canAggregateBy()
 }
 
+func Test_canAggregateByOwner(t *testing.T) {
+	testCases := []canAggregateTestCase{
+		{
+			name:         "can aggregate for query without parameters",
+			query:        "func(t *testing.T)",
+			canAggregate: true,
+		},
+		{
+			name:         "can aggregate for query with type:symbol parameter",
+			query:        "insights type:symbol",
+			canAggregate: true,
+		},
+		{
+			name:         "cannot aggregate for query with select:repo parameter",
+			query:        "repo:contains.path(README) select:repo",
+			reason:       fmt.Sprintf(ownerUnsupportedFieldValueFmt, "select", "repo"),
+			canAggregate: false,
+		},
+		{
+			name:         "cannot aggregate for query with type:diff parameter",
+			query:        "insights type:diff",
+			reason:       fmt.Sprintf(ownerUnsupportedFieldValueFmt, "type", "diff"),
+			canAggregate: false,
+		},
+		{
+			name:         "cannot aggregate for invalid query",
+			query:        "insights type:commit fork:test",
+			canAggregate: false,
+			reason:       invalidQueryMsg,
+			err:          errors.Newf("ParseQuery"),
+		},
+	}
+	suite := canAggregateBySuite{
+		canAggregateByFunc: canAggregateByOwner,
+		testCases:          testCases,
+		t:                  t,
+	}
+	suite.Test_canAggregateBy()
+}
+
+func Test_getNotAvailableReasonOwner(t *testing.T) {
+	reason, err := getNotAvailableReason("insights", "literal", types.OWNER_AGGREGATION_MODE, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if safeReason(reason) != ownerFeatureFlagMsg {
+		t.Errorf("expected reason to be %v without code ownership, got %v", ownerFeatureFlagMsg, safeReason(reason))
+	}
+
+	reason, err = getNotAvailableReason("insights", "literal", types.OWNER_AGGREGATION_MODE, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != nil {
+		t.Errorf("expected owner aggregation to be available with code ownership, got %v", reason.reason)
+	}
+}
+
 func Test_canAggregateByAuthor(t *testing.T) {
 	testCases := []canAggregateTestCase{
 		{
@@ -456,6 +514,13 @@ func Test_buildDrilldownQuery(t *testing.T) {
 			patternType: "standard",
 			mode:        types.PATH_AGGREGATION_MODE,
 		},
+		{
+			want:        autogold.Want("owner_team", "file:has.owner(@sourcegraph/search) findme"),
+			query:       "findme",
+			drilldown:   "@sourcegraph/search",
+			patternType: "standard",
+			mode:        types.OWNER_AGGREGATION_MODE,
+		},
 		{
 			want:        autogold.Want("capturegroup_with_whitespace", "case:yes /fin(?:d m)e/"),
 			query:       "/fin(.*)e/",
@@ -23,6 +23,9 @@ type LsifStore interface {
 	// Definition
 	GetDefinitionLocations(ctx context.Context, uploadID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error)
 
+	// Type Definition
+	GetTypeDefinitionLocations(ctx context.Context, uploadID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error)
+
 	// Monikers
 	GetMonikersByPosition(ctx context.Context, uploadID int, path string, line, character int) (_ [][]precise.MonikerData, err error)
 	GetBulkMonikerLocations(ctx context.Context, tableName string, uploadIDs []int, monikers []precise.MonikerData, limit, offset int) (_ []shared.Location, totalCount int, err error)
@@ -38,6 +41,7 @@ type LsifStore interface {
 
 	// Ranges
 	GetRanges(ctx context.Context, bundleID int, path string, startLine, endLine int) (_ []shared.CodeIntelligenceRange, err error)
+	GetDefinitionRanges(ctx context.Context, bundleID int, path string) (_ []shared.DefinitionRange, err error)
 
 	GetPathExists(ctx context.Context, bundleID int, path string) (_ bool, err error)
 }
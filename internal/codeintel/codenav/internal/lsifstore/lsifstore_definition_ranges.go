@@ -0,0 +1,86 @@
+package lsifstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// GetDefinitionRanges returns the ranges of the given document that define a symbol visible outside of
+// the document (a range with an attached non-local moniker whose definition result contains itself),
+// along with their extents if the index provides them. The ranges are returned in the order that their
+// extents start in the document.
+func (s *store) GetDefinitionRanges(ctx context.Context, bundleID int, path string) (_ []shared.DefinitionRange, err error) {
+	ctx, trace, endObservation := s.operations.getDefinitionRanges.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+		log.String("path", path),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	documentData, exists, err := s.scanFirstDocumentData(s.db.Query(ctx, sqlf.Sprintf(monikersDocumentQuery, bundleID, path)))
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	trace.Log(log.Int("numRanges", len(documentData.Document.Ranges)))
+	ranges := make([]precise.RangeData, 0, len(documentData.Document.Ranges))
+	for _, r := range documentData.Document.Ranges {
+		if hasNonLocalMoniker(documentData.Document, r) {
+			ranges = append(ranges, r)
+		}
+	}
+	trace.Log(log.Int("numRangesWithNonLocalMonikers", len(ranges)))
+
+	definitionResultIDs := extractResultIDs(ranges, func(r precise.RangeData) precise.ID { return r.DefinitionResultID })
+	definitionLocations, err := s.getLocationsWithinFile(ctx, bundleID, definitionResultIDs, path, documentData.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionRanges := make([]shared.DefinitionRange, 0, len(ranges))
+	for _, r := range ranges {
+		rn := newRange(r.StartLine, r.StartCharacter, r.EndLine, r.EndCharacter)
+
+		for _, location := range definitionLocations[r.DefinitionResultID] {
+			if location.Range == rn {
+				definitionRange := shared.DefinitionRange{Range: rn}
+				if r.FullRange != nil {
+					extent := newRange(r.FullRange.StartLine, r.FullRange.StartCharacter, r.FullRange.EndLine, r.FullRange.EndCharacter)
+					definitionRange.Extent = &extent
+				}
+				definitionRanges = append(definitionRanges, definitionRange)
+				break
+			}
+		}
+	}
+	sort.Slice(definitionRanges, func(i, j int) bool {
+		a, b := definitionRanges[i].ExtentOrRange(), definitionRanges[j].ExtentOrRange()
+		if a.Start == b.Start {
+			// Enclosing extents come before the extents they enclose
+			return compareBundleRanges(shared.Range{Start: b.End}, shared.Range{Start: a.End})
+		}
+
+		return compareBundleRanges(a, b)
+	})
+	trace.Log(log.Int("numDefinitionRanges", len(definitionRanges)))
+
+	return definitionRanges, nil
+}
+
+// hasNonLocalMoniker returns true if the given range has an attached moniker that is neither
+// an import moniker nor local to the index.
+func hasNonLocalMoniker(document precise.DocumentData, r precise.RangeData) bool {
+	for _, monikerID := range r.MonikerIDs {
+		if moniker, exists := document.Monikers[monikerID]; exists && moniker.Kind != precise.Import && moniker.Kind != precise.Local {
+			return true
+		}
+	}
+
+	return false
+}
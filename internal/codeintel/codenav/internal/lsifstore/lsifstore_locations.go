@@ -37,6 +37,13 @@ func (s *store) GetDefinitionLocations(ctx context.Context, bundleID int, path s
 	return s.getLocations(ctx, extractor, s.operations.getDefinitions, bundleID, path, line, character, limit, offset)
 }
 
+// GetTypeDefinitionLocations returns the set of locations defining the type of the symbol at the given position.
+func (s *store) GetTypeDefinitionLocations(ctx context.Context, bundleID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error) {
+	extractor := func(r precise.RangeData) precise.ID { return r.TypeDefinitionResultID }
+
+	return s.getLocations(ctx, extractor, s.operations.getTypeDefinitions, bundleID, path, line, character, limit, offset)
+}
+
 func (s *store) getLocations(ctx context.Context, extractor func(r precise.RangeData) precise.ID, operation *observation.Operation, bundleID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error) {
 	ctx, trace, endObservation := operation.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("bundleID", bundleID),
@@ -12,8 +12,10 @@ type operations struct {
 	getImplementations     *observation.Operation
 	getHover               *observation.Operation
 	getDefinitions         *observation.Operation
+	getTypeDefinitions     *observation.Operation
 	getDiagnostics         *observation.Operation
 	getRanges              *observation.Operation
+	getDefinitionRanges    *observation.Operation
 	getStencil             *observation.Operation
 	getExists              *observation.Operation
 	getMonikersByPosition  *observation.Operation
@@ -54,8 +56,10 @@ func newOperations(observationContext *observation.Context) *operations {
 		getImplementations:     op("GetImplementations"),
 		getHover:               op("GetHover"),
 		getDefinitions:         op("GetDefinitions"),
+		getTypeDefinitions:     op("GetTypeDefinitions"),
 		getDiagnostics:         op("GetDiagnostics"),
 		getRanges:              op("GetRanges"),
+		getDefinitionRanges:    op("GetDefinitionRanges"),
 		getStencil:             op("GetStencil"),
 		getExists:              op("GetExists"),
 		getMonikersByPosition:  op("GetMonikersByPosition"),
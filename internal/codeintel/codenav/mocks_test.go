@@ -312,6 +312,9 @@ type MockLsifStore struct {
 	// GetDefinitionLocationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDefinitionLocations.
 	GetDefinitionLocationsFunc *LsifStoreGetDefinitionLocationsFunc
+	// GetDefinitionRangesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDefinitionRanges.
+	GetDefinitionRangesFunc *LsifStoreGetDefinitionRangesFunc
 	// GetDiagnosticsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDiagnostics.
 	GetDiagnosticsFunc *LsifStoreGetDiagnosticsFunc
@@ -340,6 +343,10 @@ type MockLsifStore struct {
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *LsifStoreGetStencilFunc
+	// GetTypeDefinitionLocationsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetTypeDefinitionLocations.
+	GetTypeDefinitionLocationsFunc *LsifStoreGetTypeDefinitionLocationsFunc
 }
 
 // NewMockLsifStore creates a new mock of the LsifStore interface. All
@@ -356,6 +363,11 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetDefinitionRangesFunc: &LsifStoreGetDefinitionRangesFunc{
+			defaultHook: func(context.Context, int, string) (r0 []shared.DefinitionRange, r1 error) {
+				return
+			},
+		},
 		GetDiagnosticsFunc: &LsifStoreGetDiagnosticsFunc{
 			defaultHook: func(context.Context, int, string, int, int) (r0 []shared.Diagnostic, r1 int, r2 error) {
 				return
@@ -401,6 +413,11 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetTypeDefinitionLocationsFunc: &LsifStoreGetTypeDefinitionLocationsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) (r0 []shared.Location, r1 int, r2 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -418,6 +435,11 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetDefinitionLocations")
 			},
 		},
+		GetDefinitionRangesFunc: &LsifStoreGetDefinitionRangesFunc{
+			defaultHook: func(context.Context, int, string) ([]shared.DefinitionRange, error) {
+				panic("unexpected invocation of MockLsifStore.GetDefinitionRanges")
+			},
+		},
 		GetDiagnosticsFunc: &LsifStoreGetDiagnosticsFunc{
 			defaultHook: func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
 				panic("unexpected invocation of MockLsifStore.GetDiagnostics")
@@ -463,6 +485,11 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetStencil")
 			},
 		},
+		GetTypeDefinitionLocationsFunc: &LsifStoreGetTypeDefinitionLocationsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+				panic("unexpected invocation of MockLsifStore.GetTypeDefinitionLocations")
+			},
+		},
 	}
 }
 
@@ -476,6 +503,9 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		GetDefinitionLocationsFunc: &LsifStoreGetDefinitionLocationsFunc{
 			defaultHook: i.GetDefinitionLocations,
 		},
+		GetDefinitionRangesFunc: &LsifStoreGetDefinitionRangesFunc{
+			defaultHook: i.GetDefinitionRanges,
+		},
 		GetDiagnosticsFunc: &LsifStoreGetDiagnosticsFunc{
 			defaultHook: i.GetDiagnostics,
 		},
@@ -503,6 +533,9 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		GetStencilFunc: &LsifStoreGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetTypeDefinitionLocationsFunc: &LsifStoreGetTypeDefinitionLocationsFunc{
+			defaultHook: i.GetTypeDefinitionLocations,
+		},
 	}
 }
 
@@ -761,6 +794,118 @@ func (c LsifStoreGetDefinitionLocationsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// LsifStoreGetDefinitionRangesFunc describes the behavior when the
+// GetDefinitionRanges method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreGetDefinitionRangesFunc struct {
+	defaultHook func(context.Context, int, string) ([]shared.DefinitionRange, error)
+	hooks       []func(context.Context, int, string) ([]shared.DefinitionRange, error)
+	history     []LsifStoreGetDefinitionRangesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDefinitionRanges delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetDefinitionRanges(v0 context.Context, v1 int, v2 string) ([]shared.DefinitionRange, error) {
+	r0, r1 := m.GetDefinitionRangesFunc.nextHook()(v0, v1, v2)
+	m.GetDefinitionRangesFunc.appendCall(LsifStoreGetDefinitionRangesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDefinitionRanges
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreGetDefinitionRangesFunc) SetDefaultHook(hook func(context.Context, int, string) ([]shared.DefinitionRange, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDefinitionRanges method of the parent MockLsifStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LsifStoreGetDefinitionRangesFunc) PushHook(hook func(context.Context, int, string) ([]shared.DefinitionRange, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetDefinitionRangesFunc) SetDefaultReturn(r0 []shared.DefinitionRange, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) ([]shared.DefinitionRange, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetDefinitionRangesFunc) PushReturn(r0 []shared.DefinitionRange, r1 error) {
+	f.PushHook(func(context.Context, int, string) ([]shared.DefinitionRange, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreGetDefinitionRangesFunc) nextHook() func(context.Context, int, string) ([]shared.DefinitionRange, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetDefinitionRangesFunc) appendCall(r0 LsifStoreGetDefinitionRangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetDefinitionRangesFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetDefinitionRangesFunc) History() []LsifStoreGetDefinitionRangesFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetDefinitionRangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetDefinitionRangesFuncCall is an object that describes an
+// invocation of method GetDefinitionRanges on an instance of MockLsifStore.
+type LsifStoreGetDefinitionRangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.DefinitionRange
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetDefinitionRangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetDefinitionRangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // LsifStoreGetDiagnosticsFunc describes the behavior when the
 // GetDiagnostics method of the parent MockLsifStore instance is invoked.
 type LsifStoreGetDiagnosticsFunc struct {
@@ -1835,6 +1980,135 @@ func (c LsifStoreGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// LsifStoreGetTypeDefinitionLocationsFunc describes the behavior when the
+// GetTypeDefinitionLocations method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreGetTypeDefinitionLocationsFunc struct {
+	defaultHook func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)
+	history     []LsifStoreGetTypeDefinitionLocationsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetTypeDefinitionLocations delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetTypeDefinitionLocations(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]shared.Location, int, error) {
+	r0, r1, r2 := m.GetTypeDefinitionLocationsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.GetTypeDefinitionLocationsFunc.appendCall(LsifStoreGetTypeDefinitionLocationsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetTypeDefinitionLocations method of the parent MockLsifStore instance is
+// invoked and the hook queue is empty.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetTypeDefinitionLocations method of the parent MockLsifStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) SetDefaultReturn(r0 []shared.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) PushReturn(r0 []shared.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) appendCall(r0 LsifStoreGetTypeDefinitionLocationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetTypeDefinitionLocationsFuncCall
+// objects describing the invocations of this function.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) History() []LsifStoreGetTypeDefinitionLocationsFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetTypeDefinitionLocationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetTypeDefinitionLocationsFuncCall is an object that describes an
+// invocation of method GetTypeDefinitionLocations on an instance of
+// MockLsifStore.
+type LsifStoreGetTypeDefinitionLocationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Location
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetTypeDefinitionLocationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetTypeDefinitionLocationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // MockDBStore is a mock implementation of the DBStore interface (from the
 // package github.com/sourcegraph/sourcegraph/internal/codeintel/codenav)
 // used for unit testing.
@@ -14,9 +14,12 @@ import (
 type operations struct {
 	getReferences                        *observation.Operation
 	getImplementations                   *observation.Operation
+	getIncomingCalls                     *observation.Operation
+	getOutgoingCalls                     *observation.Operation
 	getDiagnostics                       *observation.Operation
 	getHover                             *observation.Operation
 	getDefinitions                       *observation.Operation
+	getTypeDefinitions                   *observation.Operation
 	getRanges                            *observation.Operation
 	getStencil                           *observation.Operation
 	getMonikersByPosition                *observation.Operation
@@ -49,9 +52,12 @@ func newOperations(observationContext *observation.Context) *operations {
 	return &operations{
 		getReferences:                        op("getReferences"),
 		getImplementations:                   op("getImplementations"),
+		getIncomingCalls:                     op("getIncomingCalls"),
+		getOutgoingCalls:                     op("getOutgoingCalls"),
 		getDiagnostics:                       op("getDiagnostics"),
 		getHover:                             op("getHover"),
 		getDefinitions:                       op("getDefinitions"),
+		getTypeDefinitions:                   op("getTypeDefinitions"),
 		getRanges:                            op("getRanges"),
 		getStencil:                           op("getStencil"),
 		getMonikersByPosition:                op("GetMonikersByPosition"),
@@ -2,6 +2,7 @@ package codenav
 
 import (
 	"context"
+	"math"
 	"strings"
 
 	traceLog "github.com/opentracing/opentracing-go/log"
@@ -27,9 +28,12 @@ type service interface {
 	GetDiagnostics(ctx context.Context, args shared.RequestArgs, requestState RequestState) (diagnosticsAtUploads []shared.DiagnosticAtUpload, _ int, err error)
 	GetHover(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ string, _ shared.Range, _ bool, err error)
 	GetImplementations(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ImplementationsCursor) (_ []shared.UploadLocation, nextCursor shared.ImplementationsCursor, err error)
+	GetIncomingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor) (_ []shared.CallHierarchyCall, nextCursor shared.ReferencesCursor, err error)
+	GetOutgoingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor) (_ []shared.CallHierarchyCall, nextCursor shared.ReferencesCursor, err error)
 	GetRanges(ctx context.Context, args shared.RequestArgs, requestState RequestState, startLine, endLine int) (adjustedRanges []shared.AdjustedCodeIntelligenceRange, err error)
 	GetReferences(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor) (_ []shared.UploadLocation, nextCursor shared.ReferencesCursor, err error)
 	GetStencil(ctx context.Context, args shared.RequestArgs, requestState RequestState) (adjustedRanges []shared.Range, err error)
+	GetTypeDefinitions(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.UploadLocation, err error)
 
 	GetMonikersByPosition(ctx context.Context, bundleID int, path string, line, character int) (_ [][]precise.MonikerData, err error)
 	GetBulkMonikerLocations(ctx context.Context, tableName string, uploadIDs []int, monikers []precise.MonikerData, limit, offset int) (_ []shared.Location, _ int, err error)
@@ -878,6 +882,417 @@ func (s *Service) GetDefinitions(ctx context.Context, args shared.RequestArgs, r
 	return adjustedLocations, nil
 }
 
+// GetTypeDefinitions returns the set of locations defining the type of the symbol at the given position.
+func (s *Service) GetTypeDefinitions(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.UploadLocation, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getTypeDefinitions, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	// Adjust the path and position for each visible upload based on its git difference to
+	// the target commit.
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gather the "local" type definition locations that are reachable via a typeDefinitionResult
+	// vertex. Unlike definitions, we cannot fall back to a moniker search from the requested
+	// position, as the monikers attached to that range describe the symbol and not its type.
+	for i := range visibleUploads {
+		trace.Log(traceLog.Int("uploadID", visibleUploads[i].Upload.ID))
+
+		locations, _, err := s.lsifstore.GetTypeDefinitionLocations(
+			ctx,
+			visibleUploads[i].Upload.ID,
+			visibleUploads[i].TargetPathWithoutRoot,
+			visibleUploads[i].TargetPosition.Line,
+			visibleUploads[i].TargetPosition.Character,
+			DefinitionsLimit,
+			0,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.TypeDefinitions")
+		}
+		if len(locations) == 0 {
+			continue
+		}
+
+		// The type may be defined outside of this index, in which case the location we found
+		// is a use of the type that carries an import moniker. Resolve the definition of such
+		// a moniker in the index that provides it, in addition to the local locations.
+		xrepoLocations, err := s.getTypeDefinitionMonikerLocations(ctx, visibleUploads[i].Upload, locations, requestState, trace)
+		if err != nil {
+			return nil, err
+		}
+		locations = dedupeLocations(append(locations, xrepoLocations...))
+
+		// Adjust the locations back to the appropriate range in the target commits. This adjusts
+		// locations within the repository the user is browsing so that it appears all type
+		// definitions are occurring at the same commit they are looking at.
+
+		adjustedLocations, err := s.getUploadLocations(ctx, args, requestState, locations)
+		if err != nil {
+			return nil, err
+		}
+		trace.Log(traceLog.Int("numAdjustedLocations", len(adjustedLocations)))
+
+		return adjustedLocations, nil
+	}
+
+	return nil, nil
+}
+
+// dedupeLocations removes duplicate locations, keeping the first occurrence of each.
+func dedupeLocations(locations []shared.Location) []shared.Location {
+	seen := make(map[shared.Location]struct{}, len(locations))
+	deduped := locations[:0]
+	for _, location := range locations {
+		if _, ok := seen[location]; ok {
+			continue
+		}
+		seen[location] = struct{}{}
+		deduped = append(deduped, location)
+	}
+
+	return deduped
+}
+
+// getTypeDefinitionMonikerLocations returns the definitions of the import monikers attached to the
+// given type definition locations within the given upload. If none of the locations carry an import
+// moniker, an empty slice is returned.
+func (s *Service) getTypeDefinitionMonikerLocations(ctx context.Context, upload shared.Dump, locations []shared.Location, requestState RequestState, trace observation.TraceLogger) ([]shared.Location, error) {
+	locationUploads := make([]visibleUpload, 0, len(locations))
+	for _, location := range locations {
+		locationUploads = append(locationUploads, visibleUpload{
+			Upload:                upload,
+			TargetPathWithoutRoot: location.Path,
+			TargetPosition:        location.Range.Start,
+		})
+	}
+
+	// Gather all import monikers attached to the ranges of the type definitions
+	orderedMonikers, err := s.getOrderedMonikers(ctx, locationUploads, "import")
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(
+		traceLog.Int("numMonikers", len(orderedMonikers)),
+		traceLog.String("monikers", monikersToString(orderedMonikers)),
+	)
+	if len(orderedMonikers) == 0 {
+		return nil, nil
+	}
+
+	// Determine the set of uploads over which we need to perform a moniker search
+	uploads, err := s.getUploadsWithDefinitionsForMonikers(ctx, orderedMonikers, requestState)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(
+		traceLog.Int("numXrepoTypeDefinitionUploads", len(uploads)),
+		traceLog.String("xrepoTypeDefinitionUploads", uploadIDsToString(uploads)),
+	)
+
+	// Perform the moniker search
+	xrepoLocations, _, err := s.getBulkMonikerLocations(ctx, uploads, orderedMonikers, "definitions", DefinitionsLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numXrepoLocations", len(xrepoLocations)))
+
+	return xrepoLocations, nil
+}
+
+// GetIncomingCalls returns the callers of the symbol at the given position. Each reference to the symbol
+// is attributed to the innermost definition of a non-local symbol in the same document whose extent
+// encloses it. Definitions whose extent is not provided by the index are assumed to span until the next
+// definition in the document. References are paged using the given references cursor, so calls from a
+// single caller may be split over several pages.
+func (s *Service) GetIncomingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor) (_ []shared.CallHierarchyCall, _ shared.ReferencesCursor, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getIncomingCalls, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+			traceLog.Int("limit", args.Limit),
+		},
+	})
+	defer endObservation()
+
+	references, cursor, err := s.GetReferences(ctx, args, requestState, cursor)
+	if err != nil {
+		return nil, cursor, err
+	}
+	trace.Log(traceLog.Int("numReferences", len(references)))
+
+	definitionRanges := map[documentKey][]shared.DefinitionRange{}
+	callIndexes := map[callKey]int{}
+	calls := make([]shared.CallHierarchyCall, 0, len(references))
+
+	for _, reference := range references {
+		key, ok, err := s.getEnclosingDefinition(ctx, requestState, reference, definitionRanges)
+		if err != nil {
+			return nil, cursor, err
+		}
+		if !ok {
+			// Reference does not occur within another definition (e.g. an import statement
+			// or the definition of the requested symbol itself)
+			continue
+		}
+
+		if index, ok := callIndexes[key]; ok {
+			calls[index].CallSites = append(calls[index].CallSites, reference)
+			continue
+		}
+
+		definition, err := s.getUploadLocation(ctx, args, requestState, reference.Dump, shared.Location{
+			DumpID: key.uploadID,
+			Path:   key.path,
+			Range:  key.rn,
+		})
+		if err != nil {
+			return nil, cursor, err
+		}
+
+		callIndexes[key] = len(calls)
+		calls = append(calls, shared.CallHierarchyCall{
+			Definition: definition,
+			CallSites:  []shared.UploadLocation{reference},
+		})
+	}
+	trace.Log(traceLog.Int("numIncomingCalls", len(calls)))
+
+	return calls, cursor, nil
+}
+
+// getEnclosingDefinition returns the definition range enclosing the given location, relative to the
+// location's indexed commit. If the location cannot be mapped back into the indexed commit, is not
+// enclosed by a definition, or is itself a definition, a false-valued flag is returned.
+func (s *Service) getEnclosingDefinition(ctx context.Context, requestState RequestState, location shared.UploadLocation, definitionRanges map[documentKey][]shared.DefinitionRange) (callKey, bool, error) {
+	rn := location.TargetRange
+	if location.TargetCommit != location.Dump.Commit {
+		// The location was adjusted into the requested commit; undo the adjustment so that
+		// we can compare the location against the data in the index.
+		_, sourceRange, ok, err := requestState.GitTreeTranslator.GetTargetCommitRangeFromSourceRange(ctx, location.Dump.Commit, location.Path, rn, false)
+		if err != nil {
+			return callKey{}, false, errors.Wrap(err, "gitTreeTranslator.GetTargetCommitRangeFromSourceRange")
+		}
+		if !ok {
+			return callKey{}, false, nil
+		}
+		rn = sourceRange
+	}
+
+	key := documentKey{uploadID: location.Dump.ID, path: strings.TrimPrefix(location.Path, location.Dump.Root)}
+	definitions, err := s.getDefinitionRanges(ctx, key, definitionRanges)
+	if err != nil {
+		return callKey{}, false, err
+	}
+
+	index := enclosingDefinitionIndex(definitions, rn.Start)
+	if index < 0 || definitions[index].Range == rn {
+		return callKey{}, false, nil
+	}
+
+	return callKey{documentKey: key, rn: definitions[index].Range}, true, nil
+}
+
+// getDefinitionRanges returns the definition ranges of the given document, ordered by the start of their
+// extents. Results are memoized in the given map.
+func (s *Service) getDefinitionRanges(ctx context.Context, key documentKey, definitionRanges map[documentKey][]shared.DefinitionRange) ([]shared.DefinitionRange, error) {
+	if ranges, ok := definitionRanges[key]; ok {
+		return ranges, nil
+	}
+
+	ranges, err := s.lsifstore.GetDefinitionRanges(ctx, key.uploadID, key.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifStore.GetDefinitionRanges")
+	}
+
+	definitionRanges[key] = ranges
+	return ranges, nil
+}
+
+// GetOutgoingCalls returns the callees of the definition enclosing the given position. Each range within
+// the body of the enclosing definition (its extent, or up to the next definition in the same document if
+// the index does not provide its extent), excluding the bodies of nested definitions, which refers to a
+// definition of a non-local symbol within the same index is reported as a call site of that symbol. Calls
+// are paged per visible upload using the local cursor of the given references cursor.
+func (s *Service) GetOutgoingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor) (_ []shared.CallHierarchyCall, _ shared.ReferencesCursor, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getOutgoingCalls, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+			traceLog.Int("limit", args.Limit),
+		},
+	})
+	defer endObservation()
+
+	visibleUploads, cursorsToVisibleUploads, err := s.getVisibleUploadsFromCursor(ctx, args.Line, args.Character, &cursor.CursorsToVisibleUploads, requestState)
+	if err != nil {
+		return nil, cursor, err
+	}
+	cursor.CursorsToVisibleUploads = cursorsToVisibleUploads
+
+	definitionRanges := map[documentKey][]shared.DefinitionRange{}
+	var calls []shared.CallHierarchyCall
+
+	for i := range visibleUploads {
+		if len(calls) >= args.Limit {
+			// We've filled the page
+			break
+		}
+		if i < cursor.LocalCursor.UploadOffset {
+			// Skip indexes we've searched completely
+			continue
+		}
+
+		uploadCalls, err := s.getOutgoingCallsForUpload(ctx, args, requestState, visibleUploads[i], definitionRanges)
+		if err != nil {
+			return nil, cursor, err
+		}
+		trace.Log(
+			traceLog.Int("uploadID", visibleUploads[i].Upload.ID),
+			traceLog.Int("numUploadOutgoingCalls", len(uploadCalls)),
+		)
+
+		page := uploadCalls
+		if cursor.LocalCursor.LocationOffset < len(page) {
+			page = page[cursor.LocalCursor.LocationOffset:]
+		} else {
+			page = nil
+		}
+		if n := args.Limit - len(calls); len(page) > n {
+			page = page[:n]
+		}
+
+		cursor.LocalCursor.LocationOffset += len(page)
+		if cursor.LocalCursor.LocationOffset >= len(uploadCalls) {
+			// Skip this index on next request
+			cursor.LocalCursor.LocationOffset = 0
+			cursor.LocalCursor.UploadOffset++
+		}
+
+		calls = append(calls, page...)
+	}
+	trace.Log(traceLog.Int("numOutgoingCalls", len(calls)))
+
+	if cursor.LocalCursor.UploadOffset >= len(visibleUploads) {
+		cursor.Phase = "done"
+	}
+
+	return calls, cursor, nil
+}
+
+// getOutgoingCallsForUpload returns all outgoing calls of the definition enclosing the target position
+// of the given upload.
+func (s *Service) getOutgoingCallsForUpload(ctx context.Context, args shared.RequestArgs, requestState RequestState, upload visibleUpload, definitionRanges map[documentKey][]shared.DefinitionRange) ([]shared.CallHierarchyCall, error) {
+	document := documentKey{uploadID: upload.Upload.ID, path: upload.TargetPathWithoutRoot}
+	definitions, err := s.getDefinitionRanges(ctx, document, definitionRanges)
+	if err != nil {
+		return nil, err
+	}
+
+	index := enclosingDefinitionIndex(definitions, upload.TargetPosition)
+	if index < 0 {
+		return nil, nil
+	}
+
+	// The body of the definition spans its extent, or until the next definition in the document
+	// if its extent is unknown
+	definition := definitions[index]
+	startLine := definition.ExtentOrRange().Start.Line
+	endLine := math.MaxInt32
+	if definition.Extent != nil {
+		endLine = definition.Extent.End.Line + 1
+	} else if index+1 < len(definitions) {
+		endLine = definitions[index+1].ExtentOrRange().Start.Line + 1
+	}
+
+	codeintelRanges, err := s.lsifstore.GetRanges(ctx, upload.Upload.ID, upload.TargetPathWithoutRoot, startLine, endLine)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifStore.Ranges")
+	}
+
+	var orderedKeys []callKey
+	callSites := map[callKey][]shared.Location{}
+
+	for _, r := range codeintelRanges {
+		if r.Range == definition.Range || enclosingDefinitionIndex(definitions, r.Range.Start) != index {
+			// Range is the definition itself, or falls outside of the definition body (including
+			// the bodies of nested definitions)
+			continue
+		}
+
+		for _, target := range r.Definitions {
+			if target.Path == upload.TargetPathWithoutRoot && target.Range == r.Range {
+				// Range is a definition, not a reference
+				continue
+			}
+
+			targetDefinitions, err := s.getDefinitionRanges(ctx, documentKey{uploadID: target.DumpID, path: target.Path}, definitionRanges)
+			if err != nil {
+				return nil, err
+			}
+			if !isDefinitionRange(targetDefinitions, target.Range) {
+				// Target is not the definition of a non-local symbol
+				continue
+			}
+
+			key := callKey{documentKey: documentKey{uploadID: target.DumpID, path: target.Path}, rn: target.Range}
+			if _, ok := callSites[key]; !ok {
+				orderedKeys = append(orderedKeys, key)
+			}
+			callSites[key] = append(callSites[key], shared.Location{
+				DumpID: upload.Upload.ID,
+				Path:   upload.TargetPathWithoutRoot,
+				Range:  r.Range,
+			})
+		}
+	}
+
+	calls := make([]shared.CallHierarchyCall, 0, len(orderedKeys))
+	for _, key := range orderedKeys {
+		definitions, err := s.getUploadLocations(ctx, args, requestState, []shared.Location{{DumpID: key.uploadID, Path: key.path, Range: key.rn}})
+		if err != nil {
+			return nil, err
+		}
+		if len(definitions) == 0 {
+			continue
+		}
+
+		adjustedCallSites, err := s.getUploadLocations(ctx, args, requestState, callSites[key])
+		if err != nil {
+			return nil, err
+		}
+
+		calls = append(calls, shared.CallHierarchyCall{
+			Definition: definitions[0],
+			CallSites:  adjustedCallSites,
+		})
+	}
+
+	return calls, nil
+}
+
 func (s *Service) GetDiagnostics(ctx context.Context, args shared.RequestArgs, requestState RequestState) (diagnosticsAtUploads []shared.DiagnosticAtUpload, _ int, err error) {
 	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getDiagnostics, serviceObserverThreshold, observation.Args{
 		LogFields: []traceLog.Field{
@@ -0,0 +1,256 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/internal/codeintel/stores/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestIncomingCalls(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), mockDBStore, &observation.TestContext)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &types.Repo{}, mockCommit, mockPath, 50)
+	uploads := []shared.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	// Empty result set (prevents nil pointer as scanner is always non-nil)
+	mockUploadSvc.GetUploadIDsWithReferencesFunc.PushReturn([]int{}, 0, 0, nil)
+
+	locations := []shared.Location{
+		{DumpID: 51, Path: "a.go", Range: testRange1},
+		{DumpID: 51, Path: "b.go", Range: testRange2},
+		{DumpID: 51, Path: "a.go", Range: testRange3},
+		{DumpID: 51, Path: "c.go", Range: testRange5},
+	}
+	mockLsifStore.GetReferenceLocationsFunc.PushReturn(locations, len(locations), nil)
+
+	callerRange := shared.Range{Start: shared.Position{Line: 1, Character: 5}, End: shared.Position{Line: 1, Character: 10}}
+	mockLsifStore.GetDefinitionRangesFunc.SetDefaultHook(func(ctx context.Context, bundleID int, path string) ([]shared.DefinitionRange, error) {
+		switch path {
+		case "a.go":
+			return []shared.DefinitionRange{{Range: callerRange}}, nil
+		case "b.go":
+			// The reference is the definition itself
+			return []shared.DefinitionRange{{Range: testRange2}}, nil
+		}
+
+		return nil, nil
+	})
+
+	mockCursor := shared.ReferencesCursor{Phase: "local"}
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+		Limit:        50,
+	}
+	calls, _, err := svc.GetIncomingCalls(context.Background(), mockRequest, mockRequestState, mockCursor)
+	if err != nil {
+		t.Fatalf("unexpected error querying incoming calls: %s", err)
+	}
+
+	expectedCalls := []shared.CallHierarchyCall{
+		{
+			Definition: shared.UploadLocation{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: callerRange},
+			CallSites: []shared.UploadLocation{
+				{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: testRange1},
+				{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: "deadbeef", TargetRange: testRange3},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+
+	if history := mockLsifStore.GetDefinitionRangesFunc.History(); len(history) != 3 {
+		t.Errorf("unexpected call count for lsifstore.GetDefinitionRanges. want=%d have=%d", 3, len(history))
+	}
+}
+
+func TestIncomingCallsNestedDefinitions(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), mockDBStore, &observation.TestContext)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &types.Repo{}, mockCommit, mockPath, 50)
+	uploads := []shared.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	// Empty result set (prevents nil pointer as scanner is always non-nil)
+	mockUploadSvc.GetUploadIDsWithReferencesFunc.PushReturn([]int{}, 0, 0, nil)
+
+	// The outer definition spans lines 1-20 and encloses a nested definition spanning lines 5-8
+	outerRange := shared.Range{Start: shared.Position{Line: 1, Character: 5}, End: shared.Position{Line: 1, Character: 10}}
+	outerExtent := shared.Range{Start: shared.Position{Line: 1, Character: 0}, End: shared.Position{Line: 20, Character: 1}}
+	nestedRange := shared.Range{Start: shared.Position{Line: 5, Character: 9}, End: shared.Position{Line: 5, Character: 14}}
+	nestedExtent := shared.Range{Start: shared.Position{Line: 5, Character: 4}, End: shared.Position{Line: 8, Character: 5}}
+	mockLsifStore.GetDefinitionRangesFunc.SetDefaultReturn([]shared.DefinitionRange{
+		{Range: outerRange, Extent: &outerExtent},
+		{Range: nestedRange, Extent: &nestedExtent},
+	}, nil)
+
+	nestedCallSite := shared.Range{Start: shared.Position{Line: 6, Character: 8}, End: shared.Position{Line: 6, Character: 12}}
+	outerCallSite := shared.Range{Start: shared.Position{Line: 12, Character: 4}, End: shared.Position{Line: 12, Character: 8}}
+	outsideCallSite := shared.Range{Start: shared.Position{Line: 22, Character: 4}, End: shared.Position{Line: 22, Character: 8}}
+	locations := []shared.Location{
+		{DumpID: 50, Path: "a.go", Range: nestedCallSite},
+		{DumpID: 50, Path: "a.go", Range: outerCallSite},
+		{DumpID: 50, Path: "a.go", Range: outsideCallSite},
+	}
+	mockLsifStore.GetReferenceLocationsFunc.PushReturn(locations, len(locations), nil)
+
+	mockCursor := shared.ReferencesCursor{Phase: "local"}
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+		Limit:        50,
+	}
+	calls, _, err := svc.GetIncomingCalls(context.Background(), mockRequest, mockRequestState, mockCursor)
+	if err != nil {
+		t.Fatalf("unexpected error querying incoming calls: %s", err)
+	}
+
+	// The call after the nested definition is attributed to the outer definition, and the call
+	// after the outer definition is not attributed to any definition
+	expectedCalls := []shared.CallHierarchyCall{
+		{
+			Definition: shared.UploadLocation{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: nestedRange},
+			CallSites: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: nestedCallSite},
+			},
+		},
+		{
+			Definition: shared.UploadLocation{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: outerRange},
+			CallSites: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: outerCallSite},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), mockDBStore, &observation.TestContext)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &types.Repo{}, mockCommit, mockPath, 50)
+	uploads := []shared.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	callerRange := shared.Range{Start: shared.Position{Line: 5, Character: 5}, End: shared.Position{Line: 5, Character: 10}}
+	nextRange := shared.Range{Start: shared.Position{Line: 20, Character: 5}, End: shared.Position{Line: 20, Character: 10}}
+	calleeRange := shared.Range{Start: shared.Position{Line: 3, Character: 5}, End: shared.Position{Line: 3, Character: 10}}
+	localRange := shared.Range{Start: shared.Position{Line: 6, Character: 1}, End: shared.Position{Line: 6, Character: 2}}
+
+	mockLsifStore.GetDefinitionRangesFunc.SetDefaultHook(func(ctx context.Context, bundleID int, path string) ([]shared.DefinitionRange, error) {
+		switch path {
+		case "s1/main.go":
+			return []shared.DefinitionRange{{Range: callerRange}, {Range: nextRange}}, nil
+		case "lib.go":
+			return []shared.DefinitionRange{{Range: calleeRange}}, nil
+		}
+
+		return nil, nil
+	})
+
+	callSite1 := shared.Range{Start: shared.Position{Line: 7, Character: 4}, End: shared.Position{Line: 7, Character: 8}}
+	callSite2 := shared.Range{Start: shared.Position{Line: 8, Character: 4}, End: shared.Position{Line: 8, Character: 8}}
+	callee := shared.Location{DumpID: 50, Path: "lib.go", Range: calleeRange}
+	mockLsifStore.GetRangesFunc.PushReturn([]shared.CodeIntelligenceRange{
+		{Range: callerRange, Definitions: []shared.Location{{DumpID: 50, Path: "s1/main.go", Range: callerRange}}},
+		{Range: localRange, Definitions: []shared.Location{{DumpID: 50, Path: "s1/main.go", Range: localRange}}},
+		{Range: callSite1, Definitions: []shared.Location{callee}},
+		{Range: callSite2, Definitions: []shared.Location{callee}},
+		{Range: nextRange, Definitions: []shared.Location{callee}},
+	}, nil)
+
+	mockCursor := shared.ReferencesCursor{Phase: "local"}
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+		Limit:        50,
+	}
+	calls, nextCursor, err := svc.GetOutgoingCalls(context.Background(), mockRequest, mockRequestState, mockCursor)
+	if err != nil {
+		t.Fatalf("unexpected error querying outgoing calls: %s", err)
+	}
+
+	expectedCalls := []shared.CallHierarchyCall{
+		{
+			Definition: shared.UploadLocation{Dump: uploads[0], Path: "sub1/lib.go", TargetCommit: "deadbeef", TargetRange: calleeRange},
+			CallSites: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/s1/main.go", TargetCommit: "deadbeef", TargetRange: callSite1},
+				{Dump: uploads[0], Path: "sub1/s1/main.go", TargetCommit: "deadbeef", TargetRange: callSite2},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+	if nextCursor.Phase != "done" {
+		t.Errorf("unexpected cursor phase. want=%q have=%q", "done", nextCursor.Phase)
+	}
+
+	if history := mockLsifStore.GetRangesFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected call count for lsifstore.GetRanges. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg3 != callerRange.Start.Line || history[0].Arg4 != nextRange.Start.Line+1 {
+		t.Errorf("unexpected line bounds. want=%d-%d have=%d-%d", callerRange.Start.Line, nextRange.Start.Line+1, history[0].Arg3, history[0].Arg4)
+	}
+}
@@ -0,0 +1,175 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/internal/codeintel/stores/gitserver"
+	uploadsShared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestTypeDefinitions(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), mockDBStore, &observation.TestContext)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &types.Repo{}, mockCommit, mockPath, 50)
+	uploads := []shared.Dump{
+		{ID: 50, Commit: mockCommit, Root: "sub1/"},
+		{ID: 51, Commit: mockCommit, Root: "sub2/"},
+		{ID: 52, Commit: mockCommit, Root: "sub3/"},
+		{ID: 53, Commit: mockCommit, Root: "sub4/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	locations := []shared.Location{
+		{DumpID: 51, Path: "a.go", Range: testRange1},
+		{DumpID: 51, Path: "b.go", Range: testRange2},
+		{DumpID: 51, Path: "c.go", Range: testRange3},
+	}
+	mockLsifStore.GetTypeDefinitionLocationsFunc.PushReturn(nil, 0, nil)
+	mockLsifStore.GetTypeDefinitionLocationsFunc.PushReturn(locations, len(locations), nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 51,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+	}
+	adjustedLocations, err := svc.GetTypeDefinitions(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying type definitions: %s", err)
+	}
+	expectedLocations := []shared.UploadLocation{
+		{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange1},
+		{Dump: uploads[1], Path: "sub2/b.go", TargetCommit: mockCommit, TargetRange: testRange2},
+		{Dump: uploads[1], Path: "sub2/c.go", TargetCommit: mockCommit, TargetRange: testRange3},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if history := mockLsifStore.GetTypeDefinitionLocationsFunc.History(); len(history) != 2 {
+		t.Fatalf("unexpected call count for lsifstore.GetTypeDefinitionLocations. want=%d have=%d", 2, len(history))
+	}
+	if history := mockLsifStore.GetBulkMonikerLocationsFunc.History(); len(history) != 0 {
+		t.Fatalf("unexpected call count for lsifstore.GetBulkMonikerLocations. want=%d have=%d", 0, len(history))
+	}
+}
+
+func TestTypeDefinitionsRemote(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), mockDBStore, &observation.TestContext)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	err := mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &types.Repo{ID: 42}, mockCommit, mockPath, 50)
+	if err != nil {
+		t.Fatalf("unexpected error setting local git tree translator: %s", err)
+	}
+	mockRequestState.GitTreeTranslator = mockedGitTreeTranslator()
+	uploads := []shared.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	dumps := []uploadsShared.Dump{
+		{ID: 151, Commit: "deadbeef2", Root: "sub2/"},
+	}
+	mockUploadSvc.GetDumpsWithDefinitionsForMonikersFunc.PushReturn(dumps, nil)
+	mockGitserverClient.CommitsExistFunc.SetDefaultHook(func(ctx context.Context, rcs []codeintelgitserver.RepositoryCommit) (exists []bool, _ error) {
+		for range rcs {
+			exists = append(exists, true)
+		}
+		return
+	})
+
+	// The type definition result points at a use of a type imported from another index
+	mockLsifStore.GetTypeDefinitionLocationsFunc.PushReturn([]shared.Location{
+		{DumpID: 50, Path: "a.go", Range: testRange1},
+	}, 1, nil)
+
+	moniker := precise.MonikerData{Kind: "import", Scheme: "gomod", Identifier: "leftpad:Options", PackageInformationID: "51"}
+	mockLsifStore.GetMonikersByPositionFunc.PushReturn([][]precise.MonikerData{{moniker}}, nil)
+	packageInformation := precise.PackageInformationData{Name: "leftpad", Version: "0.1.0"}
+	mockLsifStore.GetPackageInformationFunc.PushReturn(packageInformation, true, nil)
+
+	// The moniker search may also return the local location, which is only reported once.
+	locations := []shared.Location{
+		{DumpID: 50, Path: "a.go", Range: testRange1},
+		{DumpID: 151, Path: "options.go", Range: testRange2},
+	}
+	mockLsifStore.GetBulkMonikerLocationsFunc.PushReturn(locations, len(locations), nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+	}
+	remoteUploads := updateSvcDumpToSharedDump(dumps)
+	adjustedLocations, err := svc.GetTypeDefinitions(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying type definitions: %s", err)
+	}
+
+	// The local use of the type is kept alongside its definition in the remote index.
+	xLocations := []shared.UploadLocation{
+		{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testRange1},
+		{Dump: remoteUploads[0], Path: "sub2/options.go", TargetCommit: "deadbeef2", TargetRange: testRange2},
+	}
+	expectedLocations := uploadLocationsToAdjustedLocations(xLocations)
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if history := mockLsifStore.GetMonikersByPositionFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected call count for lsifstore.GetMonikersByPosition. want=%d have=%d", 1, len(history))
+	} else {
+		if history[0].Arg1 != 50 || history[0].Arg2 != "a.go" || history[0].Arg3 != testRange1.Start.Line || history[0].Arg4 != testRange1.Start.Character {
+			t.Errorf("unexpected moniker position. want=%d:%s:%d:%d have=%d:%s:%d:%d",
+				50, "a.go", testRange1.Start.Line, testRange1.Start.Character,
+				history[0].Arg1, history[0].Arg2, history[0].Arg3, history[0].Arg4,
+			)
+		}
+	}
+
+	if history := mockLsifStore.GetBulkMonikerLocationsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected call count for lsifstore.GetBulkMonikerLocations. want=%d have=%d", 1, len(history))
+	} else {
+		if history[0].Arg1 != "definitions" {
+			t.Errorf("unexpected table name. want=%q have=%q", "definitions", history[0].Arg1)
+		}
+		if diff := cmp.Diff([]precise.MonikerData{moniker}, history[0].Arg3); diff != "" {
+			t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -23,6 +23,23 @@ type Range struct {
 	End   Position
 }
 
+// DefinitionRange is the range of a definition within a file along with its extent, such as the
+// body of a function. Extent is nil if the index does not provide the extent of the definition.
+type DefinitionRange struct {
+	Range  Range
+	Extent *Range
+}
+
+// ExtentOrRange returns the extent of the definition, or the range of the definition itself if its
+// extent is unknown.
+func (r DefinitionRange) ExtentOrRange() Range {
+	if r.Extent != nil {
+		return *r.Extent
+	}
+
+	return r.Range
+}
+
 // Position is a unique position within a file.
 type Position struct {
 	Line      int
@@ -48,6 +65,14 @@ type UploadLocation struct {
 	TargetRange  Range
 }
 
+// CallHierarchyCall is a single edge of a call hierarchy. For incoming calls, the definition is the
+// caller that encloses the call sites. For outgoing calls, the definition is the callee referenced at
+// each of the call sites, which are located within the requested definition.
+type CallHierarchyCall struct {
+	Definition UploadLocation
+	CallSites  []UploadLocation
+}
+
 // DiagnosticAtUpload is a diagnostic from within a particular upload. The adjusted commit denotes
 // the target commit for which the location was adjusted (the originally requested commit).
 type DiagnosticAtUpload struct {
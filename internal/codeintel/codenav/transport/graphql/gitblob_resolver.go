@@ -24,8 +24,11 @@ type GitBlobLSIFDataResolver interface {
 	Diagnostics(ctx context.Context, limit int) ([]shared.DiagnosticAtUpload, int, error)
 	Hover(ctx context.Context, line, character int) (string, shared.Range, bool, error)
 	Definitions(ctx context.Context, line, character int) ([]shared.UploadLocation, error)
+	TypeDefinitions(ctx context.Context, line, character int) ([]shared.UploadLocation, error)
 	References(ctx context.Context, line, character, limit int, rawCursor string) ([]shared.UploadLocation, string, error)
 	Implementations(ctx context.Context, line, character, limit int, rawCursor string) ([]shared.UploadLocation, string, error)
+	IncomingCalls(ctx context.Context, line, character, limit int, rawCursor string) ([]shared.CallHierarchyCall, string, error)
+	OutgoingCalls(ctx context.Context, line, character, limit int, rawCursor string) ([]shared.CallHierarchyCall, string, error)
 }
 
 type gitBlobLSIFDataResolver struct {
@@ -71,6 +74,20 @@ func (r *gitBlobLSIFDataResolver) Definitions(ctx context.Context, line, charact
 	return def, nil
 }
 
+// TypeDefinitions returns the list of source locations that define the type of the symbol at the given position.
+func (r *gitBlobLSIFDataResolver) TypeDefinitions(ctx context.Context, line, character int) (_ []shared.UploadLocation, err error) {
+	args := shared.RequestArgs{RepositoryID: r.repositoryID, Commit: r.commit, Path: r.path, Line: line, Character: character}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.typeDefinitions, time.Second, getObservationArgs(args))
+	defer endObservation()
+
+	def, err := r.svc.GetTypeDefinitions(ctx, args, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "svc.GetTypeDefinitions")
+	}
+
+	return def, nil
+}
+
 // Diagnostics returns the diagnostics for documents with the given path prefix.
 func (r *gitBlobLSIFDataResolver) Diagnostics(ctx context.Context, limit int) (diagnosticsAtUploads []shared.DiagnosticAtUpload, _ int, err error) {
 	args := shared.RequestArgs{RepositoryID: r.repositoryID, Commit: r.commit, Path: r.path, Limit: limit}
@@ -126,6 +143,55 @@ func (r *gitBlobLSIFDataResolver) Implementations(ctx context.Context, line, cha
 	return impls, nextCursor, nil
 }
 
+// IncomingCalls returns the list of definitions that call the symbol at the given position, along
+// with the call sites within each definition.
+func (r *gitBlobLSIFDataResolver) IncomingCalls(ctx context.Context, line, character, limit int, rawCursor string) (_ []shared.CallHierarchyCall, nextCursor string, err error) {
+	args := shared.RequestArgs{RepositoryID: r.repositoryID, Commit: r.commit, Path: r.path, Line: line, Character: character, Limit: limit, RawCursor: rawCursor}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.incomingCalls, time.Second, getObservationArgs(args))
+	defer endObservation()
+
+	// Incoming calls are resolved from pages of references, so we share the references cursor
+	cursor, err := decodeReferencesCursor(args.RawCursor)
+	if err != nil {
+		return nil, "", errors.Wrap(err, fmt.Sprintf("invalid cursor: %q", args.RawCursor))
+	}
+
+	calls, callsCursor, err := r.svc.GetIncomingCalls(ctx, args, r.requestState, cursor)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "svc.GetIncomingCalls")
+	}
+
+	if callsCursor.Phase != "done" {
+		nextCursor = encodeReferencesCursor(callsCursor)
+	}
+
+	return calls, nextCursor, nil
+}
+
+// OutgoingCalls returns the list of definitions called from the definition enclosing the given position,
+// along with the call sites of each of them.
+func (r *gitBlobLSIFDataResolver) OutgoingCalls(ctx context.Context, line, character, limit int, rawCursor string) (_ []shared.CallHierarchyCall, nextCursor string, err error) {
+	args := shared.RequestArgs{RepositoryID: r.repositoryID, Commit: r.commit, Path: r.path, Line: line, Character: character, Limit: limit, RawCursor: rawCursor}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.outgoingCalls, time.Second, getObservationArgs(args))
+	defer endObservation()
+
+	cursor, err := decodeReferencesCursor(args.RawCursor)
+	if err != nil {
+		return nil, "", errors.Wrap(err, fmt.Sprintf("invalid cursor: %q", args.RawCursor))
+	}
+
+	calls, callsCursor, err := r.svc.GetOutgoingCalls(ctx, args, r.requestState, cursor)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "svc.GetOutgoingCalls")
+	}
+
+	if callsCursor.Phase != "done" {
+		nextCursor = encodeReferencesCursor(callsCursor)
+	}
+
+	return calls, nextCursor, nil
+}
+
 // LSIFUploads returns the list of dbstore.Uploads for the store.Dumps determined to be applicable
 // for answering code-intel queries.
 func (r *gitBlobLSIFDataResolver) LSIFUploads(ctx context.Context) (uploads []shared.Dump, err error) {
@@ -16,8 +16,11 @@ import (
 type operations struct {
 	hover           *observation.Operation
 	definitions     *observation.Operation
+	typeDefinitions *observation.Operation
 	references      *observation.Operation
 	implementations *observation.Operation
+	incomingCalls   *observation.Operation
+	outgoingCalls   *observation.Operation
 	diagnostics     *observation.Operation
 	stencil         *observation.Operation
 	ranges          *observation.Operation
@@ -47,8 +50,11 @@ func newOperations(observationContext *observation.Context) *operations {
 	return &operations{
 		hover:           op("Hover"),
 		definitions:     op("Definitions"),
+		typeDefinitions: op("TypeDefinitions"),
 		references:      op("References"),
 		implementations: op("Implementations"),
+		incomingCalls:   op("IncomingCalls"),
+		outgoingCalls:   op("OutgoingCalls"),
 		diagnostics:     op("Diagnostics"),
 		stencil:         op("Stencil"),
 		ranges:          op("Ranges"),
@@ -16,6 +16,18 @@ type visibleUpload struct {
 	TargetPathWithoutRoot string
 }
 
+// documentKey identifies a single document within an upload.
+type documentKey struct {
+	uploadID int
+	path     string
+}
+
+// callKey identifies a definition range within an upload.
+type callKey struct {
+	documentKey
+	rn shared.Range
+}
+
 type qualifiedMonikerSet struct {
 	monikers       []precise.QualifiedMonikerData
 	monikerHashMap map[string]struct{}
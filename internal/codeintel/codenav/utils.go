@@ -71,6 +71,48 @@ func rangeContainsPosition(r shared.Range, pos shared.Position) bool {
 	return true
 }
 
+// comparePositions returns a negative value if a occurs before b, a positive value if a occurs
+// after b, and zero if the positions are equal.
+func comparePositions(a, b shared.Position) int {
+	if a.Line != b.Line {
+		return a.Line - b.Line
+	}
+
+	return a.Character - b.Character
+}
+
+// enclosingDefinitionIndex returns the index of the innermost definition in the given slice, ordered by
+// the start of their extents, whose extent encloses the given position. A definition whose extent is
+// unknown is assumed to span until the next definition in the document. If no definition encloses the
+// given position, -1 is returned.
+func enclosingDefinitionIndex(definitions []shared.DefinitionRange, pos shared.Position) int {
+	index := sort.Search(len(definitions), func(i int) bool {
+		return comparePositions(definitions[i].ExtentOrRange().Start, pos) > 0
+	}) - 1
+
+	// Definitions which start later are nested within the ones which start earlier, so the first
+	// definition enclosing the position is the innermost one. Nested definitions which end before
+	// the position are skipped.
+	for ; index >= 0; index-- {
+		if definitions[index].Extent == nil || rangeContainsPosition(*definitions[index].Extent, pos) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// isDefinitionRange returns true if the given range is the range of one of the given definitions.
+func isDefinitionRange(definitions []shared.DefinitionRange, r shared.Range) bool {
+	for _, definition := range definitions {
+		if definition.Range == r {
+			return true
+		}
+	}
+
+	return false
+}
+
 func sortRanges(ranges []shared.Range) []shared.Range {
 	sort.Slice(ranges, func(i, j int) bool {
 		iStart := ranges[i].Start
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	codeintelupload "github.com/sourcegraph/sourcegraph/lib/codeintel/upload"
 )
 
 // GetUploadByID returns an upload by its identifier and boolean flag indicating its existence.
@@ -59,7 +60,8 @@ SELECT
 	u.upload_size,
 	u.associated_index_id,
 	s.rank,
-	u.uncompressed_size
+	u.uncompressed_size,
+	u.content_type
 FROM lsif_uploads u
 LEFT JOIN (` + uploadRankQueryFragment + `) s
 ON u.id = s.id
@@ -102,6 +104,7 @@ type Upload struct {
 	UncompressedSize  *int64
 	Rank              *int
 	AssociatedIndexID *int
+	ContentType       string
 }
 
 func (u Upload) RecordID() int {
@@ -133,6 +136,7 @@ func scanUpload(s dbutil.Scanner) (upload Upload, _ error) {
 		&upload.AssociatedIndexID,
 		&upload.Rank,
 		&upload.UncompressedSize,
+		&upload.ContentType,
 	); err != nil {
 		return upload, err
 	}
@@ -183,6 +187,9 @@ func (s *Store) InsertUpload(ctx context.Context, upload Upload) (id int, err er
 	if upload.UploadedParts == nil {
 		upload.UploadedParts = []int{}
 	}
+	if upload.ContentType == "" {
+		upload.ContentType = codeintelupload.LSIFContentType
+	}
 
 	id, _, err = basestore.ScanFirstInt(s.Store.Query(
 		ctx,
@@ -199,6 +206,7 @@ func (s *Store) InsertUpload(ctx context.Context, upload Upload) (id int, err er
 			upload.UploadSize,
 			upload.AssociatedIndexID,
 			upload.UncompressedSize,
+			upload.ContentType,
 		),
 	))
 
@@ -218,8 +226,9 @@ INSERT INTO lsif_uploads (
 	uploaded_parts,
 	upload_size,
 	associated_index_id,
-	uncompressed_size
-) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+	uncompressed_size,
+	content_type
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id
 `
 
@@ -306,6 +315,7 @@ var uploadColumnsWithNullRank = []*sqlf.Query{
 	sqlf.Sprintf("u.associated_index_id"),
 	sqlf.Sprintf("NULL"),
 	sqlf.Sprintf("u.uncompressed_size"),
+	sqlf.Sprintf("u.content_type"),
 }
 
 // HardDeleteUploadByID deletes the upload record with the given identifier.
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	codeintelupload "github.com/sourcegraph/sourcegraph/lib/codeintel/upload"
 )
 
 func TestInsertUploadUploading(t *testing.T) {
@@ -52,6 +53,7 @@ func TestInsertUploadUploading(t *testing.T) {
 		Indexer:        "lsif-go",
 		NumParts:       3,
 		UploadedParts:  []int{},
+		ContentType:    codeintelupload.LSIFContentType,
 	}
 
 	if upload, exists, err := store.GetUploadByID(context.Background(), id); err != nil {
@@ -105,6 +107,62 @@ func TestInsertUploadQueued(t *testing.T) {
 		NumParts:       1,
 		UploadedParts:  []int{0},
 		Rank:           &rank,
+		ContentType:    codeintelupload.LSIFContentType,
+	}
+
+	if upload, exists, err := store.GetUploadByID(context.Background(), id); err != nil {
+		t.Fatalf("unexpected error getting upload: %s", err)
+	} else if !exists {
+		t.Fatal("expected record to exist")
+	} else {
+		// Update auto-generated timestamp
+		expected.UploadedAt = upload.UploadedAt
+
+		if diff := cmp.Diff(expected, upload); diff != "" {
+			t.Errorf("unexpected upload (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestInsertUploadSCIP(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := testStore(db)
+
+	insertRepo(t, db, 50, "")
+
+	id, err := store.InsertUpload(context.Background(), Upload{
+		Commit:        makeCommit(1),
+		Root:          "sub/",
+		State:         "queued",
+		RepositoryID:  50,
+		Indexer:       "scip-typescript",
+		NumParts:      1,
+		UploadedParts: []int{0},
+		ContentType:   codeintelupload.SCIPContentType,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error enqueueing upload: %s", err)
+	}
+
+	rank := 1
+	expected := Upload{
+		ID:             id,
+		Commit:         makeCommit(1),
+		Root:           "sub/",
+		VisibleAtTip:   false,
+		UploadedAt:     time.Time{},
+		State:          "queued",
+		FailureMessage: nil,
+		StartedAt:      nil,
+		FinishedAt:     nil,
+		RepositoryID:   50,
+		RepositoryName: "n-50",
+		Indexer:        "scip-typescript",
+		NumParts:       1,
+		UploadedParts:  []int{0},
+		Rank:           &rank,
+		ContentType:    codeintelupload.SCIPContentType,
 	}
 
 	if upload, exists, err := store.GetUploadByID(context.Background(), id); err != nil {
@@ -162,6 +220,7 @@ func TestInsertUploadWithAssociatedIndexID(t *testing.T) {
 		UploadedParts:     []int{0},
 		Rank:              &rank,
 		AssociatedIndexID: &associatedIndexIDResult,
+		ContentType:       codeintelupload.LSIFContentType,
 	}
 
 	if upload, exists, err := store.GetUploadByID(context.Background(), id); err != nil {
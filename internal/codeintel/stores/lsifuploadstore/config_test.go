@@ -106,6 +106,33 @@ func TestConfigGCS(t *testing.T) {
 	}
 }
 
+func TestConfigLocal(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":   "Local",
+		"PRECISE_CODE_INTEL_UPLOAD_BUCKET":    "lsif-uploads",
+		"PRECISE_CODE_INTEL_UPLOAD_TTL":       "8h",
+		"PRECISE_CODE_INTEL_UPLOAD_LOCAL_DIR": "/data/uploads",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if config.Bucket != "lsif-uploads" {
+		t.Errorf("unexpected value for Local.Bucket. want=%s have=%s", "lsif-uploads", config.Bucket)
+	}
+	if config.TTL != 8*time.Hour {
+		t.Errorf("unexpected value for Local.TTL. want=%v have=%v", 8*time.Hour, config.TTL)
+	}
+	if config.LocalDir != "/data/uploads" {
+		t.Errorf("unexpected value for Local.Dir. want=%s have=%s", "/data/uploads", config.LocalDir)
+	}
+}
+
 func mapGetter(env map[string]string) func(name, defaultValue, description string) string {
 	return func(name, defaultValue, description string) string {
 		if v, ok := env[name]; ok {
@@ -26,6 +26,9 @@ func New(ctx context.Context, conf *Config, observationContext *observation.Cont
 			CredentialsFile:         conf.GCSCredentialsFile,
 			CredentialsFileContents: conf.GCSCredentialsFileContents,
 		},
+		Local: uploadstore.LocalConfig{
+			Dir: conf.LocalDir,
+		},
 	}
 
 	return uploadstore.CreateLazy(ctx, c, uploadstore.NewOperations(observationContext, "codeintel", "uploadstore"))
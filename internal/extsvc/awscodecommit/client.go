@@ -50,7 +50,20 @@ var ErrNotFound = errors.New("AWS CodeCommit repository not found")
 // IsNotFound reports whether err is a AWS CodeCommit API not-found error or the
 // equivalent cached response error.
 func IsNotFound(err error) bool {
-	return errors.Is(err, ErrNotFound) || errors.HasType(err, &codecommittypes.RepositoryDoesNotExistException{})
+	return errors.Is(err, ErrNotFound) ||
+		errors.HasType(err, &codecommittypes.RepositoryDoesNotExistException{}) ||
+		errors.HasType(err, &codecommittypes.PullRequestDoesNotExistException{})
+}
+
+// IsNotMergeable reports whether err is a AWS CodeCommit API error returned
+// when a pull request can't be merged in its current state, such as a merge
+// conflict or unsatisfied approval rules.
+func IsNotMergeable(err error) bool {
+	return errors.HasType(err, &codecommittypes.ManualMergeRequiredException{}) ||
+		errors.HasType(err, &codecommittypes.TipOfSourceReferenceIsDifferentException{}) ||
+		errors.HasType(err, &codecommittypes.TipsDivergenceExceededException{}) ||
+		errors.HasType(err, &codecommittypes.PullRequestApprovalRulesNotSatisfiedException{}) ||
+		errors.HasType(err, &codecommittypes.PullRequestAlreadyClosedException{})
 }
 
 // IsUnauthorized reports whether err is a AWS CodeCommit API unauthorized error.
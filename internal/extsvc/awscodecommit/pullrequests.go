@@ -0,0 +1,329 @@
+package awscodecommit
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codecommit"
+	codecommittypes "github.com/aws/aws-sdk-go-v2/service/codecommit/types"
+)
+
+// PullRequestStatus is the status of a pull request. A pull request can only
+// go from open to closed.
+type PullRequestStatus string
+
+const (
+	PullRequestStatusOpen   PullRequestStatus = "OPEN"
+	PullRequestStatusClosed PullRequestStatus = "CLOSED"
+)
+
+// PullRequest is an AWS CodeCommit pull request.
+type PullRequest struct {
+	ID               string              // the ID of the pull request
+	Title            string              // the title of the pull request
+	Description      string              // the description of the pull request
+	Status           PullRequestStatus   // the status of the pull request
+	AuthorARN        string              // the ARN of the user who created the pull request
+	RevisionID       string              // the ID of the current revision of the pull request
+	CreationDate     time.Time           // the date the pull request was created
+	LastActivityDate time.Time           // the date of the last activity on the pull request
+	Targets          []PullRequestTarget // the branches the pull request merges
+	ApprovalRules    []string            // the names of the approval rules of the pull request
+}
+
+// Target returns the first target of the pull request, which is the only one
+// for pull requests with a single source and destination branch.
+func (pr *PullRequest) Target() PullRequestTarget {
+	if len(pr.Targets) == 0 {
+		return PullRequestTarget{}
+	}
+	return pr.Targets[0]
+}
+
+// PullRequestTarget describes a source and destination branch of a pull request.
+type PullRequestTarget struct {
+	RepositoryName       string // the name of the repository of the branches
+	SourceReference      string // the source branch
+	SourceCommit         string // the tip of the source branch
+	DestinationReference string // the destination branch
+	DestinationCommit    string // the tip of the destination branch
+	MergeBase            string // the common ancestor of the source and destination branch
+	IsMerged             bool   // whether the pull request has been merged
+	MergeCommitID        string // the commit the pull request was merged with
+}
+
+// ApprovalState is the state of an approval of a pull request.
+type ApprovalState string
+
+const (
+	ApprovalStateApprove ApprovalState = "APPROVE"
+	ApprovalStateRevoke  ApprovalState = "REVOKE"
+)
+
+// Approval is the approval of a pull request revision by a user.
+type Approval struct {
+	UserARN string        // the ARN of the approving user
+	State   ApprovalState // the state of the approval
+}
+
+// ApprovalEvaluation is the result of evaluating the approval rules of a pull
+// request revision.
+type ApprovalEvaluation struct {
+	Approved          bool     // whether all approval rules are satisfied
+	Overridden        bool     // whether the approval rules have been overridden
+	RulesSatisfied    []string // the names of the satisfied approval rules
+	RulesNotSatisfied []string // the names of the unsatisfied approval rules
+}
+
+// CreatePullRequestInput defines the payload of CreatePullRequest method calls.
+type CreatePullRequestInput struct {
+	RepositoryName       string
+	Title                string
+	Description          string
+	SourceReference      string
+	DestinationReference string
+
+	// ClientRequestToken makes the request idempotent: a request repeated with
+	// the same token returns the pull request created by the first request.
+	ClientRequestToken string
+}
+
+// CreatePullRequest creates a pull request.
+func (c *Client) CreatePullRequest(ctx context.Context, input CreatePullRequestInput) (*PullRequest, error) {
+	req := codecommit.CreatePullRequestInput{
+		Title:       aws.String(input.Title),
+		Description: aws.String(input.Description),
+		Targets: []codecommittypes.Target{{
+			RepositoryName:       aws.String(input.RepositoryName),
+			SourceReference:      aws.String(input.SourceReference),
+			DestinationReference: aws.String(input.DestinationReference),
+		}},
+	}
+	if input.ClientRequestToken != "" {
+		req.ClientRequestToken = aws.String(input.ClientRequestToken)
+	}
+
+	result, err := c.svc().CreatePullRequest(ctx, &req)
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return fromPullRequest(result.PullRequest), nil
+}
+
+// GetPullRequest returns the pull request with the given ID.
+func (c *Client) GetPullRequest(ctx context.Context, id string) (*PullRequest, error) {
+	result, err := c.svc().GetPullRequest(ctx, &codecommit.GetPullRequestInput{PullRequestId: aws.String(id)})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return fromPullRequest(result.PullRequest), nil
+}
+
+// ListOpenPullRequests returns the open pull requests of the repository with
+// the given name.
+func (c *Client) ListOpenPullRequests(ctx context.Context, repositoryName string) ([]*PullRequest, error) {
+	var (
+		prs       []*PullRequest
+		nextToken *string
+	)
+	for {
+		result, err := c.svc().ListPullRequests(ctx, &codecommit.ListPullRequestsInput{
+			RepositoryName:    aws.String(repositoryName),
+			PullRequestStatus: codecommittypes.PullRequestStatusEnumOpen,
+			NextToken:         nextToken,
+		})
+		if err != nil {
+			return nil, &wrappedError{err: err}
+		}
+
+		// The list only contains the IDs of the pull requests, so each of them
+		// has to be fetched separately.
+		for _, id := range result.PullRequestIds {
+			pr, err := c.GetPullRequest(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			prs = append(prs, pr)
+		}
+
+		if result.NextToken == nil || *result.NextToken == "" {
+			return prs, nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+// UpdatePullRequestTitle replaces the title of the pull request with the given ID.
+func (c *Client) UpdatePullRequestTitle(ctx context.Context, id, title string) (*PullRequest, error) {
+	result, err := c.svc().UpdatePullRequestTitle(ctx, &codecommit.UpdatePullRequestTitleInput{
+		PullRequestId: aws.String(id),
+		Title:         aws.String(title),
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return fromPullRequest(result.PullRequest), nil
+}
+
+// UpdatePullRequestDescription replaces the description of the pull request
+// with the given ID.
+func (c *Client) UpdatePullRequestDescription(ctx context.Context, id, description string) (*PullRequest, error) {
+	result, err := c.svc().UpdatePullRequestDescription(ctx, &codecommit.UpdatePullRequestDescriptionInput{
+		PullRequestId: aws.String(id),
+		Description:   aws.String(description),
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return fromPullRequest(result.PullRequest), nil
+}
+
+// ClosePullRequest closes the pull request with the given ID. Closed pull
+// requests cannot be reopened.
+func (c *Client) ClosePullRequest(ctx context.Context, id string) (*PullRequest, error) {
+	result, err := c.svc().UpdatePullRequestStatus(ctx, &codecommit.UpdatePullRequestStatusInput{
+		PullRequestId:     aws.String(id),
+		PullRequestStatus: codecommittypes.PullRequestStatusEnumClosed,
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return fromPullRequest(result.PullRequest), nil
+}
+
+// MergePullRequest merges the given pull request with a three-way merge, or a
+// squash merge if squash is true. The merge fails if the source branch has
+// changed since the pull request was last fetched.
+func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash bool) (*PullRequest, error) {
+	target := pr.Target()
+
+	var (
+		merged *codecommittypes.PullRequest
+		err    error
+	)
+	if squash {
+		var result *codecommit.MergePullRequestBySquashOutput
+		result, err = c.svc().MergePullRequestBySquash(ctx, &codecommit.MergePullRequestBySquashInput{
+			PullRequestId:  aws.String(pr.ID),
+			RepositoryName: aws.String(target.RepositoryName),
+			SourceCommitId: aws.String(target.SourceCommit),
+		})
+		if err == nil {
+			merged = result.PullRequest
+		}
+	} else {
+		var result *codecommit.MergePullRequestByThreeWayOutput
+		result, err = c.svc().MergePullRequestByThreeWay(ctx, &codecommit.MergePullRequestByThreeWayInput{
+			PullRequestId:  aws.String(pr.ID),
+			RepositoryName: aws.String(target.RepositoryName),
+			SourceCommitId: aws.String(target.SourceCommit),
+		})
+		if err == nil {
+			merged = result.PullRequest
+		}
+	}
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return fromPullRequest(merged), nil
+}
+
+// CreatePullRequestComment posts a comment on the given pull request.
+func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest, content string) error {
+	target := pr.Target()
+
+	_, err := c.svc().PostCommentForPullRequest(ctx, &codecommit.PostCommentForPullRequestInput{
+		PullRequestId:  aws.String(pr.ID),
+		RepositoryName: aws.String(target.RepositoryName),
+		BeforeCommitId: aws.String(target.DestinationCommit),
+		AfterCommitId:  aws.String(target.SourceCommit),
+		Content:        aws.String(content),
+	})
+	if err != nil {
+		return &wrappedError{err: err}
+	}
+	return nil
+}
+
+// GetPullRequestApprovals returns the approvals of the current revision of the
+// given pull request.
+func (c *Client) GetPullRequestApprovals(ctx context.Context, pr *PullRequest) ([]*Approval, error) {
+	result, err := c.svc().GetPullRequestApprovalStates(ctx, &codecommit.GetPullRequestApprovalStatesInput{
+		PullRequestId: aws.String(pr.ID),
+		RevisionId:    aws.String(pr.RevisionID),
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+
+	approvals := make([]*Approval, 0, len(result.Approvals))
+	for _, a := range result.Approvals {
+		approvals = append(approvals, &Approval{
+			UserARN: aws.ToString(a.UserArn),
+			State:   ApprovalState(a.ApprovalState),
+		})
+	}
+	return approvals, nil
+}
+
+// EvaluatePullRequestApprovalRules evaluates the approval rules of the current
+// revision of the given pull request.
+func (c *Client) EvaluatePullRequestApprovalRules(ctx context.Context, pr *PullRequest) (*ApprovalEvaluation, error) {
+	result, err := c.svc().EvaluatePullRequestApprovalRules(ctx, &codecommit.EvaluatePullRequestApprovalRulesInput{
+		PullRequestId: aws.String(pr.ID),
+		RevisionId:    aws.String(pr.RevisionID),
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	if result.Evaluation == nil {
+		return &ApprovalEvaluation{}, nil
+	}
+
+	return &ApprovalEvaluation{
+		Approved:          result.Evaluation.Approved,
+		Overridden:        result.Evaluation.Overridden,
+		RulesSatisfied:    result.Evaluation.ApprovalRulesSatisfied,
+		RulesNotSatisfied: result.Evaluation.ApprovalRulesNotSatisfied,
+	}, nil
+}
+
+func (c *Client) svc() *codecommit.Client {
+	return codecommit.NewFromConfig(c.aws)
+}
+
+func fromPullRequest(pr *codecommittypes.PullRequest) *PullRequest {
+	out := PullRequest{
+		ID:               aws.ToString(pr.PullRequestId),
+		Title:            aws.ToString(pr.Title),
+		Description:      aws.ToString(pr.Description),
+		Status:           PullRequestStatus(pr.PullRequestStatus),
+		AuthorARN:        aws.ToString(pr.AuthorArn),
+		RevisionID:       aws.ToString(pr.RevisionId),
+		CreationDate:     aws.ToTime(pr.CreationDate),
+		LastActivityDate: aws.ToTime(pr.LastActivityDate),
+	}
+
+	for _, t := range pr.PullRequestTargets {
+		target := PullRequestTarget{
+			RepositoryName:       aws.ToString(t.RepositoryName),
+			SourceReference:      aws.ToString(t.SourceReference),
+			SourceCommit:         aws.ToString(t.SourceCommit),
+			DestinationReference: aws.ToString(t.DestinationReference),
+			DestinationCommit:    aws.ToString(t.DestinationCommit),
+			MergeBase:            aws.ToString(t.MergeBase),
+		}
+		if m := t.MergeMetadata; m != nil {
+			target.IsMerged = m.IsMerged
+			target.MergeCommitID = aws.ToString(m.MergeCommitId)
+		}
+		out.Targets = append(out.Targets, target)
+	}
+
+	for _, r := range pr.ApprovalRules {
+		out.ApprovalRules = append(out.ApprovalRules, aws.ToString(r.ApprovalRuleName))
+	}
+
+	return &out
+}
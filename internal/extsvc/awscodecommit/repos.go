@@ -230,6 +230,10 @@ func (w *wrappedError) Error() string {
 	return ""
 }
 
+func (w *wrappedError) Unwrap() error {
+	return w.err
+}
+
 func (w *wrappedError) NotFound() bool {
 	return IsNotFound(w.err)
 }
@@ -0,0 +1,275 @@
+//nolint:bodyclose // Body is closed in Client.Do, but the response is still returned to provide access to the headers
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// apiVersion is the version of the Azure DevOps REST API used by the client. It is
+// supported by both Azure DevOps Services and Azure DevOps Server 2022.
+const apiVersion = "7.0"
+
+// maxRetries is the number of times a request that was rejected because of rate
+// limiting is retried before giving up.
+const maxRetries = 2
+
+// Client access Azure DevOps via the REST API.
+type Client struct {
+	// Config is the code host connection config for this client
+	Config *schema.AzureDevOpsConnection
+
+	// URL is the base URL of Azure DevOps, which is either https://dev.azure.com
+	// or the URL of an Azure DevOps Server collection.
+	URL *url.URL
+
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// auth is used to authenticate requests with the username and personal
+	// access token from the config.
+	auth *auth.BasicAuth
+
+	// rateLimit is the self-imposed rate limiter configured by the rateLimit
+	// property of the connection config.
+	rateLimit *ratelimit.InstrumentedLimiter
+
+	// rateLimitMonitor tracks the X-RateLimit-* and Retry-After headers that Azure
+	// DevOps sends once the usage of the account approaches its limits.
+	rateLimitMonitor *ratelimit.Monitor
+}
+
+// NewClient returns an authenticated Azure DevOps API client with
+// the provided configuration. If a nil httpClient is provided, http.DefaultClient
+// will be used.
+func NewClient(urn string, config *schema.AzureDevOpsConnection, httpClient httpcli.Doer) (*Client, error) {
+	u, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve API paths relative to the collection of Azure DevOps Server instances
+	u = extsvc.NormalizeBaseURL(u)
+
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer
+	}
+
+	a := &auth.BasicAuth{Username: config.Username, Password: config.Token}
+
+	return &Client{
+		Config:           config,
+		URL:              u,
+		httpClient:       httpClient,
+		auth:             a,
+		rateLimit:        ratelimit.DefaultRegistry.Get(urn),
+		rateLimitMonitor: ratelimit.DefaultMonitorRegistry.GetOrSet(u.String(), a.Hash(), "rest", &ratelimit.Monitor{HeaderPrefix: "X-"}),
+	}, nil
+}
+
+// RateLimitMonitor returns the rate limit monitor of this client.
+func (c *Client) RateLimitMonitor() *ratelimit.Monitor {
+	return c.rateLimitMonitor
+}
+
+// ListProjectsArgs defines options to be set on ListProjects method calls.
+type ListProjectsArgs struct {
+	// Org is the name of the organization whose projects are listed.
+	Org string
+	// PerPage is the maximum number of projects returned per page.
+	PerPage int
+	// ContinuationToken is the token returned with the previous page of results.
+	// It is empty when requesting the first page.
+	ContinuationToken string
+}
+
+// ListProjectsResponse defines a response struct returned from ListProjects method calls.
+type ListProjectsResponse struct {
+	Count    int        `json:"count"`
+	Projects []*Project `json:"value"`
+
+	// ContinuationToken is the token to request the next page of results with. It
+	// is empty if this is the last page.
+	ContinuationToken string `json:"-"`
+}
+
+// ListProjects returns a page of the well-formed projects of the given organization.
+func (c *Client) ListProjects(ctx context.Context, opts ListProjectsArgs) (*ListProjectsResponse, error) {
+	qs := make(url.Values)
+	if opts.PerPage > 0 {
+		qs.Set("$top", strconv.Itoa(opts.PerPage))
+	}
+	if opts.ContinuationToken != "" {
+		qs.Set("continuationToken", opts.ContinuationToken)
+	}
+
+	u := url.URL{Path: opts.Org + "/_apis/projects", RawQuery: qs.Encode()}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ListProjectsResponse
+	httpResp, err := c.do(ctx, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	resp.ContinuationToken = httpResp.Header.Get("X-Ms-Continuationtoken")
+
+	return &resp, nil
+}
+
+// listRepositoriesResponse defines a response struct returned from ListRepositoriesByProject
+// method calls.
+type listRepositoriesResponse struct {
+	Count        int           `json:"count"`
+	Repositories []*Repository `json:"value"`
+}
+
+// ListRepositoriesByProject returns all Git repositories of the given project. The
+// Azure DevOps API does not paginate this endpoint.
+func (c *Client) ListRepositoriesByProject(ctx context.Context, org, project string) ([]*Repository, error) {
+	u := url.URL{Path: fmt.Sprintf("%s/%s/_apis/git/repositories", org, project)}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp listRepositoriesResponse
+	if _, err = c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Repositories, nil
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result any) (*http.Response, error) {
+	req.URL = c.URL.ResolveReference(req.URL)
+	qs := req.URL.Query()
+	qs.Set("api-version", apiVersion)
+	req.URL.RawQuery = qs.Encode()
+	req.Header.Set("Accept", "application/json")
+
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.rateLimit.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		bs, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		c.rateLimitMonitor.Update(resp.Header)
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			// Azure DevOps rejects requests once the usage of the account exceeds its
+			// limits and tells us how long to back off for via the Retry-After header.
+			if err := c.waitForRetry(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return nil, errors.WithStack(&httpError{
+				URL:        req.URL,
+				StatusCode: resp.StatusCode,
+				Body:       bs,
+			})
+		}
+
+		return resp, json.Unmarshal(bs, result)
+	}
+}
+
+// waitForRetry blocks until the back-off period requested by the last response has
+// elapsed or the given context is canceled.
+func (c *Client) waitForRetry(ctx context.Context) error {
+	_, _, retry, _ := c.rateLimitMonitor.Get()
+	if retry <= 0 {
+		retry = time.Second
+	}
+
+	timer := time.NewTimer(retry)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Project is a project of an Azure DevOps organization.
+type Project struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	State       string `json:"state"`
+	Visibility  string `json:"visibility"`
+}
+
+// Repository is a Git repository of an Azure DevOps project.
+type Repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	URL           string  `json:"url"`
+	Project       Project `json:"project"`
+	DefaultBranch string  `json:"defaultBranch,omitempty"`
+	Size          int64   `json:"size"`
+	RemoteURL     string  `json:"remoteUrl"`
+	SSHURL        string  `json:"sshUrl"`
+	WebURL        string  `json:"webUrl"`
+	IsDisabled    bool    `json:"isDisabled"`
+	IsFork        bool    `json:"isFork"`
+}
+
+// IsPrivate returns true if the repository belongs to a private project.
+func (r *Repository) IsPrivate() bool {
+	return r.Project.Visibility != "public"
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Azure DevOps API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
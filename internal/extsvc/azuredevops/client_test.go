@@ -0,0 +1,59 @@
+package azuredevops
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestClient_ListProjects(t *testing.T) {
+	cli, save := NewTestClient(t, "ListProjects", *update)
+	defer save()
+
+	ctx := context.Background()
+
+	args := ListProjectsArgs{
+		Org:     "sgtest",
+		PerPage: 2,
+	}
+
+	resp, err := cli.ListProjects(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "2", resp.ContinuationToken; want != have {
+		t.Errorf("unexpected continuation token. want=%q have=%q", want, have)
+	}
+
+	testutil.AssertGolden(t, "testdata/golden/ListProjects.json", *update, resp)
+}
+
+func TestClient_ListRepositoriesByProject(t *testing.T) {
+	cli, save := NewTestClient(t, "ListRepositoriesByProject", *update)
+	defer save()
+
+	ctx := context.Background()
+
+	repos, err := cli.ListRepositoriesByProject(ctx, "sgtest", "sgtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertGolden(t, "testdata/golden/ListRepositoriesByProject.json", *update, repos)
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if !testing.Verbose() {
+		log15.Root().SetHandler(log15.LvlFilterHandler(log15.LvlError, log15.Root().GetHandler()))
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,288 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// changeOptions are the additional fields requested whenever a change is
+// fetched, so that the review and check states of the change can be derived
+// from the response.
+var changeOptions = []string{
+	"CURRENT_REVISION",
+	"CURRENT_COMMIT",
+	"DETAILED_LABELS",
+	"DETAILED_ACCOUNTS",
+	"SUBMITTABLE",
+}
+
+// GetChange returns the change with the given identifier, which may be the
+// change number, the Change-Id or the "project~branch~Change-Id" triplet.
+func (c *Client) GetChange(ctx context.Context, changeID string) (*Change, error) {
+	qs := make(url.Values)
+	for _, o := range changeOptions {
+		qs.Add("o", o)
+	}
+
+	req, err := http.NewRequest("GET", changePath(changeID)+"?"+qs.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var change Change
+	if _, err = c.do(ctx, req, &change); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// AbandonChange abandons the change with the given identifier.
+func (c *Client) AbandonChange(ctx context.Context, changeID string) (*Change, error) {
+	return c.changeAction(ctx, changeID, "abandon", struct{}{})
+}
+
+// RestoreChange restores the abandoned change with the given identifier.
+func (c *Client) RestoreChange(ctx context.Context, changeID string) (*Change, error) {
+	return c.changeAction(ctx, changeID, "restore", struct{}{})
+}
+
+// SubmitChange submits the change with the given identifier, merging it into
+// its destination branch.
+func (c *Client) SubmitChange(ctx context.Context, changeID string) (*Change, error) {
+	return c.changeAction(ctx, changeID, "submit", struct{}{})
+}
+
+// SetReadyForReview marks the work in progress change with the given
+// identifier as ready for review.
+func (c *Client) SetReadyForReview(ctx context.Context, changeID string) error {
+	return c.changeFlag(ctx, changeID, "ready")
+}
+
+// MoveChangeInput defines the payload of MoveChange method calls.
+type MoveChangeInput struct {
+	DestinationBranch string `json:"destination_branch"`
+}
+
+// MoveChange moves the change with the given identifier to another branch.
+func (c *Client) MoveChange(ctx context.Context, changeID string, input MoveChangeInput) (*Change, error) {
+	return c.changeAction(ctx, changeID, "move", input)
+}
+
+// SetCommitMessage creates a new patch set of the change with the given
+// identifier that only differs in its commit message. The message must retain
+// the Change-Id footer of the change.
+func (c *Client) SetCommitMessage(ctx context.Context, changeID, message string) error {
+	req, err := newJSONRequest("PUT", changePath(changeID)+"/message", struct {
+		Message string `json:"message"`
+	}{Message: message})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, req, nil)
+	return err
+}
+
+// ReviewInput defines the payload of SetReview method calls.
+type ReviewInput struct {
+	Message string         `json:"message,omitempty"`
+	Labels  map[string]int `json:"labels,omitempty"`
+}
+
+// SetReview posts a review, such as a comment, on the current revision of the
+// change with the given identifier.
+func (c *Client) SetReview(ctx context.Context, changeID string, input ReviewInput) error {
+	req, err := newJSONRequest("POST", changePath(changeID)+"/revisions/current/review", input)
+	if err != nil {
+		return err
+	}
+
+	var resp json.RawMessage
+	_, err = c.do(ctx, req, &resp)
+	return err
+}
+
+// GetAuthenticatedUserAccount returns the account of the user the client is
+// authenticated as.
+func (c *Client) GetAuthenticatedUserAccount(ctx context.Context) (*Account, error) {
+	req, err := http.NewRequest("GET", "a/accounts/self", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if _, err = c.do(ctx, req, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (c *Client) changeAction(ctx context.Context, changeID, action string, input any) (*Change, error) {
+	req, err := newJSONRequest("POST", changePath(changeID)+"/"+action, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var change Change
+	if _, err = c.do(ctx, req, &change); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// changeFlag toggles a flag of a change. These endpoints don't return the
+// updated change.
+func (c *Client) changeFlag(ctx context.Context, changeID, flag string) error {
+	req, err := newJSONRequest("POST", changePath(changeID)+"/"+flag, struct{}{})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, req, nil)
+	return err
+}
+
+// changePath returns the escaped path of the change with the given identifier.
+// Slashes in project names have to be escaped when they are part of a
+// "project~branch~Change-Id" triplet.
+func changePath(changeID string) string {
+	return "a/changes/" + url.PathEscape(changeID)
+}
+
+func newJSONRequest(method, path string, body any) (*http.Request, error) {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	return req, nil
+}
+
+// ChangeStatus is the status of a change.
+type ChangeStatus string
+
+const (
+	ChangeStatusNew       ChangeStatus = "NEW"
+	ChangeStatusMerged    ChangeStatus = "MERGED"
+	ChangeStatusAbandoned ChangeStatus = "ABANDONED"
+)
+
+// Change is a change as returned by the changes endpoints of the Gerrit REST API.
+type Change struct {
+	// ID is the "project~branch~Change-Id" triplet identifying the change.
+	ID              string                  `json:"id"`
+	Project         string                  `json:"project"`
+	Branch          string                  `json:"branch"`
+	Topic           string                  `json:"topic,omitempty"`
+	ChangeID        string                  `json:"change_id"`
+	Subject         string                  `json:"subject"`
+	Status          ChangeStatus            `json:"status"`
+	Created         Timestamp               `json:"created"`
+	Updated         Timestamp               `json:"updated"`
+	Submittable     bool                    `json:"submittable,omitempty"`
+	WorkInProgress  bool                    `json:"work_in_progress,omitempty"`
+	Number          int                     `json:"_number"`
+	Owner           Account                 `json:"owner"`
+	Labels          map[string]LabelInfo    `json:"labels,omitempty"`
+	CurrentRevision string                  `json:"current_revision,omitempty"`
+	Revisions       map[string]RevisionInfo `json:"revisions,omitempty"`
+}
+
+// CurrentCommit returns the commit of the current revision of the change, if it
+// was requested.
+func (c *Change) CurrentCommit() *CommitInfo {
+	if rev, ok := c.Revisions[c.CurrentRevision]; ok {
+		return &rev.Commit
+	}
+	return nil
+}
+
+// LabelInfo describes the votes cast on a review label of a change, such as
+// Code-Review or Verified.
+type LabelInfo struct {
+	Optional     bool           `json:"optional,omitempty"`
+	Approved     *Account       `json:"approved,omitempty"`
+	Rejected     *Account       `json:"rejected,omitempty"`
+	Recommended  *Account       `json:"recommended,omitempty"`
+	Disliked     *Account       `json:"disliked,omitempty"`
+	All          []ApprovalInfo `json:"all,omitempty"`
+	DefaultValue int            `json:"default_value,omitempty"`
+}
+
+// ApprovalInfo is a single vote on a review label.
+type ApprovalInfo struct {
+	Account
+	Value int       `json:"value"`
+	Date  Timestamp `json:"date"`
+}
+
+// RevisionInfo describes a patch set of a change.
+type RevisionInfo struct {
+	Number int        `json:"_number"`
+	Ref    string     `json:"ref"`
+	Commit CommitInfo `json:"commit"`
+}
+
+// CommitInfo describes the commit of a patch set.
+type CommitInfo struct {
+	Parents []ParentCommit `json:"parents"`
+	Author  GitPersonInfo  `json:"author"`
+	Subject string         `json:"subject"`
+	Message string         `json:"message"`
+}
+
+// ParentCommit identifies a parent commit of a patch set.
+type ParentCommit struct {
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+}
+
+// GitPersonInfo is the author or committer of a commit.
+type GitPersonInfo struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// timestampLayout is the format Gerrit uses for timestamps, which are always
+// in UTC.
+const timestampLayout = "2006-01-02 15:04:05.000000000"
+
+// Timestamp is a point in time as formatted by the Gerrit REST API.
+type Timestamp struct {
+	time.Time
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(t.UTC().Format(timestampLayout))
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	ts, err := time.Parse(timestampLayout, strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	t.Time = ts
+	return nil
+}
@@ -1739,12 +1739,25 @@ type Repository struct {
 	StargazerCount int `json:",omitempty"`
 	ForkCount      int `json:",omitempty"`
 
+	// Parent is the repository this repository was forked from, if it is a
+	// fork.
+	Parent *ParentRepository `json:",omitempty"`
+
 	// This is available for GitHub Enterprise Cloud and GitHub Enterprise Server 3.3.0+ and is used
 	// to identify if a repository is public or private or internal.
 	// https://developer.github.com/changes/2019-12-03-internal-visibility-changes/#repository-visibility-fields
 	Visibility Visibility `json:",omitempty"`
 }
 
+// ParentRepository identifies the repository a fork was forked from.
+type ParentRepository struct {
+	ID string // ID of repository (GitHub GraphQL ID, not GitHub database ID)
+}
+
+type restParentRepository struct {
+	ID string `json:"node_id"` // GraphQL ID
+}
+
 type restRepositoryPermissions struct {
 	Admin bool `json:"admin"`
 	Push  bool `json:"push"`
@@ -1766,6 +1779,7 @@ type restRepository struct {
 	Stars       int                       `json:"stargazers_count"`
 	Forks       int                       `json:"forks_count"`
 	Visibility  string                    `json:"visibility"`
+	Parent      *restParentRepository     `json:"parent"`
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -1803,6 +1817,10 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		ForkCount:        restRepo.Forks,
 	}
 
+	if restRepo.Parent != nil {
+		repo.Parent = &ParentRepository{ID: restRepo.Parent.ID}
+	}
+
 	if conf.ExperimentalFeatures().EnableGithubInternalRepoVisibility {
 		repo.Visibility = Visibility(restRepo.Visibility)
 	}
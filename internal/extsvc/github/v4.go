@@ -556,6 +556,9 @@ fragment RepositoryFields on Repository {
 	viewerPermission
 	stargazerCount
 	forkCount
+	parent {
+		id
+	}
 }
 	`
 	}
@@ -586,6 +589,9 @@ fragment RepositoryFields on Repository {
 	isLocked
 	isDisabled
 	forkCount
+	parent {
+		id
+	}
 	%s
 }
 	`, strings.Join(conditionalGHEFields, "\n	"))
@@ -0,0 +1,73 @@
+package subversion
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os/exec"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Repository is the metadata of a Subversion repository mirrored by
+// Sourcegraph.
+type Repository struct {
+	// URL is the URL of the repository, without credentials.
+	URL string `json:"url"`
+	// Path is the path of the repository relative to the root URL of the
+	// connection.
+	Path string `json:"path"`
+}
+
+// Client lists Subversion repositories with the svn CLI.
+//
+// IMPORTANT: the svn binary must be installed in the environment of the
+// service invoking the client.
+type Client struct {
+	Username string
+	Password string
+}
+
+// ListDirectories returns the names of the directories directly below url.
+func (c *Client) ListDirectories(ctx context.Context, url string) ([]string, error) {
+	args := []string{"list", "--xml", "--non-interactive", "--no-auth-cache"}
+	if c.Username != "" {
+		args = append(args, "--username", c.Username)
+	}
+	if c.Password != "" {
+		args = append(args, "--password-from-stdin")
+	}
+	args = append(args, "--", url)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "svn", args...)
+	cmd.Stdin = strings.NewReader(c.Password + "\n")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "svn list %s: %s", url, strings.TrimSpace(stderr.String()))
+	}
+	return decodeDirectories(stdout.Bytes())
+}
+
+// decodeDirectories decodes the output of "svn list --xml".
+func decodeDirectories(data []byte) ([]string, error) {
+	var list struct {
+		Entries []struct {
+			Kind string `xml:"kind,attr"`
+			Name string `xml:"name"`
+		} `xml:"list>entry"`
+	}
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return nil, errors.Wrap(err, "decoding svn list output")
+	}
+
+	var dirs []string
+	for _, e := range list.Entries {
+		if e.Kind == "dir" {
+			dirs = append(dirs, e.Name)
+		}
+	}
+	return dirs, nil
+}
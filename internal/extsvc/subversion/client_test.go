@@ -0,0 +1,52 @@
+package subversion
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeDirectories(t *testing.T) {
+	out := `<?xml version="1.0" encoding="UTF-8"?>
+<lists>
+<list
+   path="file:///srv/svn/projects">
+<entry
+   kind="dir">
+<name>project1</name>
+<commit
+   revision="3">
+<author>jdoe</author>
+<date>2022-10-01T10:00:00.000000Z</date>
+</commit>
+</entry>
+<entry
+   kind="file">
+<name>README</name>
+<size>12</size>
+<commit
+   revision="1">
+<author>jdoe</author>
+<date>2022-09-01T10:00:00.000000Z</date>
+</commit>
+</entry>
+<entry
+   kind="dir">
+<name>project2</name>
+<commit
+   revision="2">
+<author>jdoe</author>
+<date>2022-09-15T10:00:00.000000Z</date>
+</commit>
+</entry>
+</list>
+</lists>
+`
+	got, err := decodeDirectories([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"project1", "project2"}; !cmp.Equal(want, got) {
+		t.Fatalf("mismatch (-want, +got):\n%s", cmp.Diff(want, got))
+	}
+}
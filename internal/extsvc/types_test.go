@@ -36,6 +36,11 @@ func TestExtractToken(t *testing.T) {
 			kind:   KindPhabricator,
 			want:   "deadbeef",
 		},
+		{
+			config: `{"token": "deadbeef"}`,
+			kind:   KindAzureDevOps,
+			want:   "deadbeef",
+		},
 	} {
 		t.Run(tc.kind, func(t *testing.T) {
 			have, err := ExtractToken(tc.config, tc.kind)
@@ -111,6 +116,18 @@ func TestExtractRateLimitConfig(t *testing.T) {
 			kind:   KindBitbucketCloud,
 			want:   1.0,
 		},
+		{
+			name:   "Azure DevOps default",
+			config: `{"url": "https://dev.azure.com"}`,
+			kind:   KindAzureDevOps,
+			want:   8.0,
+		},
+		{
+			name:   "Azure DevOps non-default",
+			config: `{"url": "https://dev.azure.com", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:   KindAzureDevOps,
+			want:   1.0,
+		},
 		{
 			name:   "NPM default",
 			config: `{"registry": "https://registry.npmjs.org"}`,
@@ -288,6 +305,21 @@ func TestUniqueCodeHostIdentifier(t *testing.T) {
 			config: `{"url": "https://example.com"}`,
 			want:   "https://example.com/",
 		},
+		{
+			kind:   KindAzureDevOps,
+			config: `{"url": "https://dev.azure.com"}`,
+			want:   "https://dev.azure.com/",
+		},
+		{
+			kind:   KindMercurial,
+			config: `{"url": "https://HG.example.com/repos", "repos": ["project"]}`,
+			want:   "https://hg.example.com/repos/",
+		},
+		{
+			kind:   KindSubversion,
+			config: `{"url": "https://SVN.example.com/repos"}`,
+			want:   "https://svn.example.com/repos/",
+		},
 		{
 			kind:   KindBitbucketServer,
 			config: `{"url": "https://bitbucket.sgdev.org/"}`,
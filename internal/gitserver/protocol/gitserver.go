@@ -19,6 +19,11 @@ type SearchRequest struct {
 	IncludeDiff          bool
 	Limit                int
 	IncludeModifiedFiles bool
+
+	// RangeDiff, if true, searches the aggregated diff of each revision range
+	// (git diff a...b) instead of the commits in the range. Every revision
+	// must then be a range of the form a..b or a...b.
+	RangeDiff bool
 }
 
 type RevisionSpecifier struct {
@@ -176,6 +181,9 @@ type RepoUpdateResponse struct {
 	LastFetched *time.Time `json:",omitempty"`
 	LastChanged *time.Time `json:",omitempty"`
 
+	// Archived is true if the repository is archived and was not updated.
+	Archived bool `json:",omitempty"`
+
 	// Error is an error reported by the update operation, and not a network protocol error.
 	Error string `json:",omitempty"`
 }
@@ -237,6 +245,7 @@ type RepoCloneProgress struct {
 	CloneInProgress bool   // whether the repository is currently being cloned
 	CloneProgress   string // a progress message from the running clone command.
 	Cloned          bool   // whether the repository has been cloned successfully
+	Archived        bool   // whether the repository is archived and restored by its next clone
 }
 
 // RepoCloneProgressResponse is the response to a repository clone progress request
@@ -263,6 +272,11 @@ type CreateCommitFromPatchRequest struct {
 	// Push specifies whether the target ref will be pushed to the code host: if
 	// nil, no push will be attempted, if non-nil, a push will be attempted.
 	Push *PushConfig
+	// PushRef is the ref the commit is pushed to on the code host, if it differs
+	// from TargetRef. This is used for code hosts that create reviews from pushes
+	// to magic refs, such as refs/for/<branch> in Gerrit. TargetRef is still
+	// created in the local repository.
+	PushRef *string
 	// GitApplyArgs are the arguments that will be passed to `git apply` along
 	// with `--cached`.
 	GitApplyArgs []string
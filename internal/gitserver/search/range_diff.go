@@ -0,0 +1,221 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// searchRangeDiffs runs a search against the aggregated diff of each revision
+// range of cs.Revisions rather than against the commits in the range.
+//
+// The diff of a range is matched as a whole by cs.Query, as if it were the diff
+// of the head commit of the range. A match is sent for each matching file of
+// the diff, so that each file is reported once no matter how many commits of
+// the range modified it.
+func (cs *CommitSearcher) searchRangeDiffs(ctx context.Context, onMatch func(*protocol.CommitMatch)) error {
+	filterFunc := getSubRepoFilterFunc(ctx, authz.DefaultSubRepoPermsChecker, cs.RepoName)
+	lowerBuf := make([]byte, 1024)
+
+	for _, rev := range cs.Revisions {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		head, ok := rangeHead(rev.RevSpec)
+		if !ok {
+			return errors.Errorf("range diff search requires a revision range of the form a..b or a...b, got %q", rev.RevSpec)
+		}
+
+		headCommit, err := cs.readCommit(ctx, head)
+		if err != nil {
+			return err
+		} else if headCommit == nil {
+			continue
+		}
+
+		fileDiffs, err := cs.readRangeDiff(ctx, rev.RevSpec)
+		if err != nil {
+			return err
+		}
+		// The matched diff must not mention files the actor cannot see.
+		fileDiffs = filterRawDiff(fileDiffs, filterFunc)
+		if len(fileDiffs) == 0 {
+			continue
+		}
+
+		lc := &LazyCommit{
+			RawCommit: headCommit,
+			diff:      fileDiffs,
+			LowerBuf:  lowerBuf,
+		}
+		mergedResult, highlights, err := cs.Query.Match(lc)
+		if err != nil {
+			return err
+		}
+		if !mergedResult.Satisfies() {
+			continue
+		}
+
+		for _, fileIdx := range matchedFileIndexes(len(fileDiffs), highlights) {
+			fileDiff := fileDiffs[fileIdx]
+			fileCommit := *headCommit
+			if cs.IncludeModifiedFiles {
+				name := fileDiff.NewName
+				if name == "/dev/null" {
+					name = fileDiff.OrigName
+				}
+				fileCommit.ModifiedFiles = [][]byte{[]byte(name)}
+			}
+
+			// Each match only contains the diff of a single file, so that
+			// previews are not truncated and results can be streamed per file.
+			fileLC := &LazyCommit{
+				RawCommit: &fileCommit,
+				diff:      []*diff.FileDiff{fileDiff},
+				LowerBuf:  lowerBuf,
+			}
+			fileHighlights := MatchedCommit{Message: highlights.Message}
+			if fdh, ok := highlights.Diff[fileIdx]; ok {
+				fileHighlights.Diff = map[int]MatchedFileDiff{0: fdh}
+			}
+
+			cm, err := CreateCommitMatch(fileLC, fileHighlights, true, nil)
+			if err != nil {
+				return err
+			}
+			onMatch(cm)
+		}
+	}
+	return nil
+}
+
+// rangeHead returns the head of a revision range of the form a..b or a...b.
+// Like git, an omitted head defaults to HEAD.
+func rangeHead(revSpec string) (string, bool) {
+	sep := "..."
+	i := strings.Index(revSpec, sep)
+	if i == -1 {
+		sep = ".."
+		i = strings.Index(revSpec, sep)
+	}
+	if i == -1 {
+		return "", false
+	}
+
+	if head := revSpec[i+len(sep):]; head != "" {
+		return head, true
+	}
+	return "HEAD", true
+}
+
+// matchedFileIndexes returns the indexes of the single file diffs which have
+// highlights, in order. If no file diff has highlights, because only fields of
+// the commit were matched, all indexes are returned.
+func matchedFileIndexes(numFiles int, highlights MatchedCommit) []int {
+	indexes := make([]int, 0, numFiles)
+	if len(highlights.Diff) == 0 {
+		for i := 0; i < numFiles; i++ {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	for i := range highlights.Diff {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// readCommit returns the metadata of the given revision in the same shape
+// as the commits walked by a regular commit search. It returns nil if the
+// repository does not have any commits yet.
+func (cs *CommitSearcher) readCommit(ctx context.Context, rev string) (*RawCommit, error) {
+	args := append(append([]string{}, logArgs...), "-1", rev, "--")
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = cs.RepoDir
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = &stderrBuf
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, tryInterpretErrorWithStderr(ctx, err, stderrBuf.String(), cs.Logger)
+	}
+
+	scanner := NewCommitScanner(bytes.NewReader(out))
+	if !scanner.Scan() {
+		return nil, scanner.Err()
+	}
+	return scanner.NextRawCommit(), nil
+}
+
+// maxRangeDiffBytes bounds the size of the diff of a revision range we read.
+// The diff of a range is matched as a whole, so it is held in memory.
+const maxRangeDiffBytes = 64 * 1024 * 1024
+
+// readRangeDiff returns the parsed output of git diff for the given revision
+// range. The diff is formatted like the output of the DiffFetcher.
+func (cs *CommitSearcher) readRangeDiff(ctx context.Context, revSpec string) ([]*diff.FileDiff, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git",
+		"diff",
+		"--no-prefix",   // Do not prefix file names with a/ and b/
+		"--no-color",    // Ignore color settings of the repository
+		"--no-ext-diff", // Never run external diff drivers
+		"--no-renames",  // Report renames as deletions and additions, like git diff-tree
+		revSpec,
+		"--",
+	)
+	cmd.Dir = cs.RepoDir
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = &stderrBuf
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// We parse the diff while git writes it, and stop reading once it
+	// exceeds maxRangeDiffBytes.
+	limited := &io.LimitedReader{R: stdout, N: maxRangeDiffBytes + 1}
+	r := diff.NewMultiFileDiffReader(limited)
+	var fileDiffs []*diff.FileDiff
+	var readErr error
+	for {
+		fileDiff, err := r.ReadFile()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			readErr = err
+			break
+		}
+		fileDiffs = append(fileDiffs, fileDiff)
+	}
+
+	if limited.N <= 0 || readErr != nil {
+		cancel()
+		_ = cmd.Wait()
+		if limited.N <= 0 {
+			return nil, errors.Errorf("the diff of %s is larger than %d bytes", revSpec, maxRangeDiffBytes)
+		}
+		return nil, readErr
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, tryInterpretErrorWithStderr(ctx, err, stderrBuf.String(), cs.Logger)
+	}
+	return fileDiffs, nil
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestSearchRangeDiffs(t *testing.T) {
+	commit := func(name, msg string) string {
+		return "GIT_COMMITTER_NAME=" + name + " " +
+			"GIT_COMMITTER_EMAIL=" + name + "@example.com " +
+			"GIT_COMMITTER_DATE=2006-01-02T15:04:05Z " +
+			"GIT_AUTHOR_NAME=" + name + " " +
+			"GIT_AUTHOR_EMAIL=" + name + "@example.com " +
+			"GIT_AUTHOR_DATE=2006-01-02T15:04:05Z " +
+			"git commit -m " + msg
+	}
+	cmds := []string{
+		"echo needle zero > file1",
+		"git add -A",
+		commit("alice", "commit1"),
+		"git tag release-1",
+		"echo needle one > file2",
+		"git add -A",
+		commit("bob", "commit2"),
+		"echo needle two >> file2",
+		"echo needle three > file3",
+		"git add -A",
+		commit("carol", "commit3"),
+		"git tag release-2",
+	}
+	dir := initGitRepository(t, cmds...)
+
+	search := func(t *testing.T, query protocol.Node, revSpec string) ([]*protocol.CommitMatch, error) {
+		tree, err := ToMatchTree(query)
+		require.NoError(t, err)
+		searcher := &CommitSearcher{
+			RepoDir:              dir,
+			Query:                tree,
+			Revisions:            []protocol.RevisionSpecifier{{RevSpec: revSpec}},
+			IncludeDiff:          true,
+			IncludeModifiedFiles: true,
+			RangeDiff:            true,
+		}
+		var matches []*protocol.CommitMatch
+		err = searcher.Search(context.Background(), func(match *protocol.CommitMatch) {
+			matches = append(matches, match)
+		})
+		return matches, err
+	}
+
+	t.Run("one match per file", func(t *testing.T) {
+		matches, err := search(t, &protocol.DiffMatches{Expr: "needle"}, "release-1...release-2")
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+
+		// file2 was modified by two commits, but is reported once with the
+		// lines added by both.
+		require.Equal(t, []string{"file2"}, matches[0].ModifiedFiles)
+		require.Equal(t, "/dev/null file2\n@@ -0,0 +1,2 @@ \n+needle one\n+needle two\n", matches[0].Diff.Content)
+		require.Len(t, matches[0].Diff.MatchedRanges, 2)
+		require.Equal(t, []string{"file3"}, matches[1].ModifiedFiles)
+
+		// Matches carry the metadata of the head of the range.
+		for _, match := range matches {
+			require.Equal(t, "carol", match.Author.Name)
+			require.Equal(t, "commit3", match.Message.Content)
+		}
+	})
+
+	t.Run("predicates apply to files", func(t *testing.T) {
+		query := protocol.NewAnd(
+			&protocol.DiffModifiesFile{Expr: "file3"},
+			&protocol.DiffMatches{Expr: "needle"},
+		)
+		matches, err := search(t, query, "release-1..release-2")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, []string{"file3"}, matches[0].ModifiedFiles)
+	})
+
+	t.Run("omitted head", func(t *testing.T) {
+		matches, err := search(t, &protocol.DiffMatches{Expr: "two"}, "release-1..")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.Equal(t, []string{"file2"}, matches[0].ModifiedFiles)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		matches, err := search(t, &protocol.DiffMatches{Expr: "zero"}, "release-1..release-2")
+		require.NoError(t, err)
+		require.Empty(t, matches)
+	})
+
+	t.Run("not a range", func(t *testing.T) {
+		_, err := search(t, &protocol.DiffMatches{Expr: "needle"}, "release-2")
+		require.Error(t, err)
+	})
+}
@@ -89,6 +89,10 @@ type CommitSearcher struct {
 	IncludeDiff          bool
 	IncludeModifiedFiles bool
 	RepoName             api.RepoName
+
+	// RangeDiff, if true, searches the aggregated diff of each revision range
+	// instead of the commits in it. See searchRangeDiffs.
+	RangeDiff bool
 }
 
 // Search runs a search for commits matching the given predicate across the revisions passed in as revisionArgs.
@@ -100,6 +104,10 @@ type CommitSearcher struct {
 // This allows our worker pool to run the jobs in parallel, but we still emit matches in the same order that
 // git log outputs them.
 func (cs *CommitSearcher) Search(ctx context.Context, onMatch func(*protocol.CommitMatch)) error {
+	if cs.RangeDiff {
+		return cs.searchRangeDiffs(ctx, onMatch)
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	jobs := make(chan job, 128)
@@ -0,0 +1,178 @@
+package repos
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// An AzureDevOpsSource yields repositories from a single Azure DevOps connection
+// configured in Sourcegraph via the external services configuration.
+type AzureDevOpsSource struct {
+	svc       *types.ExternalService
+	cli       *azuredevops.Client
+	serviceID string
+	perPage   int
+	exclude   excludeFunc
+}
+
+// NewAzureDevOpsSource returns a new AzureDevOpsSource from the given external service.
+func NewAzureDevOpsSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*AzureDevOpsSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.AzureDevOpsConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	httpCli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := azuredevops.NewClient(svc.URN(), &c, httpCli)
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Exact(r.Id)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureDevOpsSource{
+		svc:       svc,
+		cli:       cli,
+		serviceID: cli.URL.String(),
+		perPage:   100,
+		exclude:   exclude,
+	}, nil
+}
+
+// ListRepos returns all Azure DevOps repositories of the organizations and projects
+// configured with this AzureDevOpsSource's config.
+func (s *AzureDevOpsSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	// Projects may be configured explicitly and through their organization
+	seen := make(map[string]struct{})
+
+	for _, org := range s.cli.Config.Orgs {
+		if err := s.listOrg(ctx, org, seen, results); err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "listing repositories of organization %q", org)}
+		}
+	}
+
+	for _, p := range s.cli.Config.Projects {
+		org, project, ok := strings.Cut(p, "/")
+		if !ok {
+			results <- SourceResult{Source: s, Err: errors.Errorf("invalid project %q, expected \"org/project\"", p)}
+			continue
+		}
+
+		if err := s.listProject(ctx, org, project, seen, results); err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "listing repositories of project %q", p)}
+		}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *AzureDevOpsSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s *AzureDevOpsSource) listOrg(ctx context.Context, org string, seen map[string]struct{}, results chan SourceResult) error {
+	args := azuredevops.ListProjectsArgs{
+		Org:     org,
+		PerPage: s.perPage,
+	}
+
+	for {
+		page, err := s.cli.ListProjects(ctx, args)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range page.Projects {
+			if err := s.listProject(ctx, org, p.Name, seen, results); err != nil {
+				return err
+			}
+		}
+
+		if page.ContinuationToken == "" {
+			return nil
+		}
+
+		args.ContinuationToken = page.ContinuationToken
+	}
+}
+
+func (s *AzureDevOpsSource) listProject(ctx context.Context, org, project string, seen map[string]struct{}, results chan SourceResult) error {
+	repos, err := s.cli.ListRepositoriesByProject(ctx, org, project)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range repos {
+		if _, ok := seen[r.ID]; ok {
+			continue
+		}
+		seen[r.ID] = struct{}{}
+
+		// Disabled repositories cannot be cloned
+		if r.IsDisabled || s.excludes(org, r) {
+			continue
+		}
+
+		results <- SourceResult{Source: s, Repo: s.makeRepo(org, r)}
+	}
+
+	return nil
+}
+
+func (s *AzureDevOpsSource) excludes(org string, r *azuredevops.Repository) bool {
+	return s.exclude(path.Join(org, r.Project.Name, r.Name)) || s.exclude(r.ID)
+}
+
+func (s *AzureDevOpsSource) makeRepo(org string, r *azuredevops.Repository) *types.Repo {
+	urn := s.svc.URN()
+	name := path.Join(s.cli.URL.Host, s.cli.URL.Path, org, r.Project.Name, r.Name)
+
+	return &types.Repo{
+		Name:    api.RepoName(name),
+		URI:     name,
+		Fork:    r.IsFork,
+		Private: r.IsPrivate(),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          r.ID,
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   s.serviceID,
+		},
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: r.RemoteURL,
+			},
+		},
+		Metadata: r,
+	}
+}
@@ -0,0 +1,45 @@
+package repos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAzureDevOpsSource_ListRepos(t *testing.T) {
+	conf := &schema.AzureDevOpsConnection{
+		Url:      "https://dev.azure.com",
+		Username: "testuser",
+		Token:    "testtoken",
+		Orgs:     []string{"sgtest"},
+		Exclude: []*schema.ExcludedAzureDevOpsRepo{
+			{Name: "sgtest/sgtest/jsonrpc2"},
+		},
+	}
+	cf, save := newClientFactory(t, t.Name())
+	defer save(t)
+
+	svc := &types.ExternalService{
+		Kind:   extsvc.KindAzureDevOps,
+		Config: extsvc.NewUnencryptedConfig(marshalJSON(t, conf)),
+	}
+
+	ctx := context.Background()
+	src, err := NewAzureDevOpsSource(ctx, svc, cf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src.perPage = 2 // 2 pages for 3 projects
+
+	repos, err := listAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertGolden(t, "testdata/sources/AZUREDEVOPS/"+t.Name(), update(t.Name()), repos)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
@@ -20,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/pagure"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/phabricator"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/subversion"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -52,6 +54,10 @@ func cloneURL(parsed any, logger log.Logger, kind string, repo *types.Repo) (str
 		if r, ok := repo.Metadata.(*awscodecommit.Repository); ok {
 			return awsCodeCloneURL(logger, r, t), nil
 		}
+	case *schema.AzureDevOpsConnection:
+		if r, ok := repo.Metadata.(*azuredevops.Repository); ok {
+			return azureDevOpsCloneURL(logger, r, t), nil
+		}
 	case *schema.BitbucketServerConnection:
 		if r, ok := repo.Metadata.(*bitbucketserver.Repo); ok {
 			return bitbucketServerCloneURL(r, t), nil
@@ -88,6 +94,14 @@ func cloneURL(parsed any, logger log.Logger, kind string, repo *types.Repo) (str
 		if r, ok := repo.Metadata.(*pagure.Project); ok {
 			return r.FullURL, nil
 		}
+	case *schema.SubversionConnection:
+		if r, ok := repo.Metadata.(*subversion.Repository); ok {
+			return subversionCloneURL(logger, r, t), nil
+		}
+	case *schema.MercurialConnection:
+		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
+			return mercurialCloneURL(r, t)
+		}
 	case *schema.OtherExternalServiceConnection:
 		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
 			return otherCloneURL(repo, r), nil
@@ -126,6 +140,17 @@ func awsCodeCloneURL(logger log.Logger, repo *awscodecommit.Repository, cfg *sch
 	return u.String()
 }
 
+func azureDevOpsCloneURL(logger log.Logger, repo *azuredevops.Repository, cfg *schema.AzureDevOpsConnection) string {
+	u, err := url.Parse(repo.RemoteURL)
+	if err != nil {
+		logger.Warn("Error adding authentication to Azure DevOps repository Git remote URL.", log.String("url", repo.RemoteURL), log.Error(err))
+		return repo.RemoteURL
+	}
+
+	u.User = url.UserPassword(cfg.Username, cfg.Token)
+	return u.String()
+}
+
 func bitbucketServerCloneURL(repo *bitbucketserver.Repo, cfg *schema.BitbucketServerConnection) string {
 	var cloneURL string
 	for _, l := range repo.Links.Clone {
@@ -258,6 +283,36 @@ func perforceCloneURL(depot *perforce.Depot, cfg *schema.PerforceConnection) str
 	return cloneURL.String()
 }
 
+func subversionCloneURL(logger log.Logger, repo *subversion.Repository, cfg *schema.SubversionConnection) string {
+	if cfg.Username == "" {
+		return repo.URL
+	}
+
+	u, err := url.Parse(repo.URL)
+	if err != nil {
+		logger.Warn("Error adding authentication to Subversion repository URL.", log.String("url", repo.URL), log.Error(err))
+		return repo.URL
+	}
+
+	if cfg.Password != "" {
+		u.User = url.UserPassword(cfg.Username, cfg.Password)
+	} else {
+		u.User = url.User(cfg.Username)
+	}
+	return u.String()
+}
+
+// mercurialCloneURL returns the clone URL of the repository below the base URL
+// of the connection, which includes the credentials of the connection.
+func mercurialCloneURL(repo *extsvc.OtherRepoMetadata, cfg *schema.MercurialConnection) (string, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.Url, "/"))
+	if err != nil {
+		return "", err
+	}
+	base.Path += "/" + strings.TrimPrefix(repo.RelativePath, "/")
+	return base.String(), nil
+}
+
 func phabricatorCloneURL(logger log.Logger, repo *phabricator.Repo, _ *schema.PhabricatorConnection) string {
 	var external []*phabricator.URI
 	builtin := make(map[string]*phabricator.URI)
@@ -0,0 +1,118 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A MercurialSource yields repositories from a single Mercurial connection
+// configured in Sourcegraph via the external services configuration.
+type MercurialSource struct {
+	svc    *types.ExternalService
+	config *schema.MercurialConnection
+	// base is the URL of the connection without credentials.
+	base      *url.URL
+	serviceID string
+}
+
+// NewMercurialSource returns a new MercurialSource from the given external
+// service.
+func NewMercurialSource(ctx context.Context, svc *types.ExternalService) (*MercurialSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.MercurialConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newMercurialSource(svc, &c)
+}
+
+func newMercurialSource(svc *types.ExternalService, c *schema.MercurialConnection) (*MercurialSource, error) {
+	base, err := url.Parse(strings.TrimSuffix(c.Url, "/"))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse url")
+	}
+	base.User = nil
+	serviceID := *base
+	return &MercurialSource{
+		svc:       svc,
+		config:    c,
+		base:      base,
+		serviceID: extsvc.NormalizeBaseURL(&serviceID).String(),
+	}, nil
+}
+
+// ListRepos returns all Mercurial repositories configured in repos.
+func (s MercurialSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	for _, p := range s.config.Repos {
+		repo, err := s.makeRepo(p)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: repo}
+	}
+}
+
+func (s MercurialSource) makeRepo(repoPath string) (*types.Repo, error) {
+	// Like for other connections, repos may be absolute URLs as long as they
+	// are below the base URL.
+	dir := *s.base
+	dir.Path += "/"
+	u, err := dir.Parse(repoPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse repository %q", repoPath)
+	}
+	if u.Scheme != s.base.Scheme || u.Host != s.base.Host || !strings.HasPrefix(u.Path, dir.Path) {
+		return nil, errors.Errorf("repository %q is not below %q", repoPath, s.base)
+	}
+	repoPath = strings.Trim(strings.TrimPrefix(u.Path, s.base.Path), "/")
+	if repoPath == "" {
+		return nil, errors.Errorf("repository %q is the base URL", s.base)
+	}
+
+	urn := s.svc.URN()
+	u.User, u.RawQuery, u.Fragment = nil, "", ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	base := strings.Trim(s.base.Host+s.base.Path, "/")
+
+	return &types.Repo{
+		Name: api.RepoName(reposource.OtherRepoName(
+			s.config.RepositoryPathPattern,
+			base,
+			repoPath,
+		)),
+		URI: reposource.OtherRepoName("", base, repoPath),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          repoPath,
+			ServiceType: extsvc.TypeMercurial,
+			ServiceID:   s.serviceID,
+		},
+		Private: true,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: u.String(),
+			},
+		},
+		Metadata: &extsvc.OtherRepoMetadata{
+			RelativePath: repoPath,
+		},
+	}, nil
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s MercurialSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
@@ -231,7 +231,11 @@ func (s *UpdateScheduler) runUpdateLoop(ctx context.Context) {
 					return
 				}
 
-				if err != nil || (resp != nil && resp.Error != "") {
+				if resp != nil && resp.Archived {
+					// Archived repositories are restored when they are used,
+					// not by scheduled updates.
+					s.schedule.updateInterval(repo, maxDelay)
+				} else if err != nil || (resp != nil && resp.Error != "") {
 					// On error we will double the current interval so that we back off and don't
 					// get stuck with problematic repos with low intervals.
 					if currentInterval, ok := s.schedule.getCurrentInterval(repo); ok {
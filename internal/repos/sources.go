@@ -61,8 +61,14 @@ func NewSource(ctx context.Context, logger log.Logger, db database.DB, svc *type
 		return NewPhabricatorSource(ctx, logger.Scoped("PhabricatorSource", "phabricator repo source"), svc, cf)
 	case extsvc.KindAWSCodeCommit:
 		return NewAWSCodeCommitSource(ctx, svc, cf)
+	case extsvc.KindAzureDevOps:
+		return NewAzureDevOpsSource(ctx, svc, cf)
 	case extsvc.KindPerforce:
 		return NewPerforceSource(ctx, svc)
+	case extsvc.KindSubversion:
+		return NewSubversionSource(ctx, svc)
+	case extsvc.KindMercurial:
+		return NewMercurialSource(ctx, svc)
 	case extsvc.KindGoPackages:
 		return NewGoPackagesSource(ctx, svc, cf)
 	case extsvc.KindJVMPackages:
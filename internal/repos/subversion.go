@@ -0,0 +1,117 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/subversion"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A SubversionSource yields repositories from a single Subversion connection
+// configured in Sourcegraph via the external services configuration.
+type SubversionSource struct {
+	svc       *types.ExternalService
+	config    *schema.SubversionConnection
+	root      *url.URL
+	serviceID string
+	cli       *subversion.Client
+}
+
+// NewSubversionSource returns a new SubversionSource from the given external
+// service.
+func NewSubversionSource(ctx context.Context, svc *types.ExternalService) (*SubversionSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.SubversionConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newSubversionSource(svc, &c)
+}
+
+func newSubversionSource(svc *types.ExternalService, c *schema.SubversionConnection) (*SubversionSource, error) {
+	root, err := url.Parse(strings.TrimSuffix(c.Url, "/"))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse url")
+	}
+	serviceID := *root
+	return &SubversionSource{
+		svc:       svc,
+		config:    c,
+		root:      root,
+		serviceID: extsvc.NormalizeBaseURL(&serviceID).String(),
+		cli: &subversion.Client{
+			Username: c.Username,
+			Password: c.Password,
+		},
+	}, nil
+}
+
+// ListRepos returns all Subversion repositories below the root URL of the
+// connection, or the repositories configured in repos.
+func (s SubversionSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	paths := s.config.Repos
+	if len(paths) == 0 {
+		var err error
+		paths, err = s.cli.ListDirectories(ctx, s.root.String())
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+	}
+
+	for _, p := range paths {
+		results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+	}
+}
+
+func (s SubversionSource) makeRepo(repoPath string) *types.Repo {
+	repoPath = strings.Trim(repoPath, "/")
+	urn := s.svc.URN()
+
+	u := *s.root
+	u.Path += "/" + repoPath
+	repoURL := u.String()
+
+	base := strings.Trim(s.root.Host+s.root.Path, "/")
+
+	return &types.Repo{
+		Name: api.RepoName(reposource.OtherRepoName(
+			s.config.RepositoryPathPattern,
+			base,
+			repoPath,
+		)),
+		URI: reposource.OtherRepoName("", base, repoPath),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          repoPath,
+			ServiceType: extsvc.TypeSubversion,
+			ServiceID:   s.serviceID,
+		},
+		Private: true,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: repoURL,
+			},
+		},
+		Metadata: &subversion.Repository{
+			URL:  repoURL,
+			Path: repoPath,
+		},
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s SubversionSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
@@ -0,0 +1,349 @@
+package blame
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Filters are the conditions the blame of a matched line has to satisfy for
+// the line to be kept.
+type Filters struct {
+	// IncludeAuthors are patterns the name or email of the author of a line
+	// must match (blame.author:).
+	IncludeAuthors []string
+	// ExcludeAuthors are patterns the name or email of the author of a line
+	// must not match (-blame.author:).
+	ExcludeAuthors []string
+	// Before is the time a line must have been last changed before
+	// (blame.before:). The zero value does not filter lines.
+	Before time.Time
+	// CaseSensitive is true if author patterns are case-sensitive.
+	CaseSensitive bool
+}
+
+// blameConcurrency is the maximum number of files of an event we blame
+// concurrently.
+const blameConcurrency = 8
+
+// New returns a job that joins the line matches of the file matches streamed
+// by child with `git blame` data, and only keeps the lines whose blame satisfies
+// the given filters. File matches without any remaining line match are dropped.
+// If selectAuthors is true, the distinct authors of the remaining lines are
+// streamed instead of the file matches (select:blame.author). Once limit file
+// matches are streamed, no more files are blamed and child is canceled. Authors
+// are only deduplicated by the select job, so selecting authors is bounded by
+// the limit job canceling the search instead.
+func New(child job.Job, filters Filters, selectAuthors bool, limit int) job.Job {
+	compile := func(patterns []string) []*regexp.Regexp {
+		res := make([]*regexp.Regexp, 0, len(patterns))
+		for _, pattern := range patterns {
+			if !filters.CaseSensitive {
+				pattern = "(?i:" + pattern + ")"
+			}
+			res = append(res, regexp.MustCompile(pattern)) // Invariant: patterns already validated
+		}
+		return res
+	}
+
+	return &blameJob{
+		child:          child,
+		filters:        filters,
+		includeAuthors: compile(filters.IncludeAuthors),
+		excludeAuthors: compile(filters.ExcludeAuthors),
+		selectAuthors:  selectAuthors,
+		limit:          limit,
+	}
+}
+
+type blameJob struct {
+	child job.Job
+
+	filters        Filters
+	includeAuthors []*regexp.Regexp
+	excludeAuthors []*regexp.Regexp
+	selectAuthors  bool
+	limit          int
+}
+
+func (j *blameJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := j.limit
+	if j.selectAuthors || limit <= 0 {
+		limit = math.MaxInt
+	}
+
+	var (
+		mu       sync.Mutex
+		errs     error
+		sent     int
+		limitHit bool
+	)
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		mu.Lock()
+		remaining := limit - sent
+		mu.Unlock()
+
+		results, err := j.applyBlameFiltering(ctx, clients.Gitserver, event.Results, remaining)
+
+		mu.Lock()
+		if err != nil {
+			errs = errors.Append(errs, err)
+		}
+		// Events are filtered concurrently, so the results of this event may
+		// exceed what remains of the limit by now.
+		if n := limit - sent; len(results) > n {
+			results = results[:n]
+		}
+		sent += len(results)
+		if sent >= limit && !limitHit {
+			limitHit = true
+			event.Stats.IsLimitHit = true
+			cancel()
+		}
+		mu.Unlock()
+
+		event.Results = results
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, filteredStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	if limitHit {
+		// Searches and blames canceled because the limit was hit are not
+		// errors.
+		errs = errors.Ignore(errs, errors.IsPred(context.Canceled))
+	}
+	return alert, errs
+}
+
+func (j *blameJob) Name() string {
+	return "BlameFilterJob"
+}
+
+func (j *blameJob) Fields(v job.Verbosity) (res []otlog.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			trace.Strings("includeAuthors", j.filters.IncludeAuthors),
+			trace.Strings("excludeAuthors", j.filters.ExcludeAuthors),
+			otlog.Bool("selectAuthors", j.selectAuthors),
+			otlog.Int("limit", j.limit),
+		)
+		if !j.filters.Before.IsZero() {
+			res = append(res, otlog.String("before", j.filters.Before.Format(time.RFC3339)))
+		}
+	}
+	return res
+}
+
+func (j *blameJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *blameJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+// applyBlameFiltering blames the file matches of matches concurrently and
+// returns the matches which satisfy the filters, in order. It stops blaming
+// files once limit matches are kept.
+func (j *blameJob) applyBlameFiltering(ctx context.Context, client gitserver.Client, matches []result.Match, limit int) ([]result.Match, error) {
+	// Blame data only exists for lines of files.
+	fileMatches := make([]*result.FileMatch, 0, len(matches))
+	for _, m := range matches {
+		if fm, ok := m.(*result.FileMatch); ok && len(fm.ChunkMatches) > 0 {
+			fileMatches = append(fileMatches, fm)
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		sem  = semaphore.NewWeighted(blameConcurrency)
+		mu   sync.Mutex
+		errs error
+		kept int
+		// results contains the matches kept for each file match. Selecting
+		// authors may yield several matches per file match.
+		results = make([][]result.Match, len(fileMatches))
+	)
+	for i, fm := range fileMatches {
+		mu.Lock()
+		done := kept >= limit
+		mu.Unlock()
+		if done {
+			break
+		}
+		if err := sem.Acquire(ctx, 1); err != nil {
+			errs = errors.Append(errs, err)
+			break
+		}
+
+		i, fm := i, fm
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sem.Release(1)
+
+			hunks, err := blameMatchedLines(ctx, client, fm)
+			if err != nil {
+				mu.Lock()
+				errs = errors.Append(errs, err)
+				mu.Unlock()
+				return
+			}
+
+			authors := j.filterChunkMatches(fm, hunks)
+			if len(fm.ChunkMatches) == 0 {
+				return
+			}
+
+			if !j.selectAuthors {
+				results[i] = []result.Match{fm}
+			} else {
+				results[i] = make([]result.Match, 0, len(authors))
+				for _, author := range authors {
+					results[i] = append(results[i], author)
+				}
+			}
+			mu.Lock()
+			kept += len(results[i])
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	filtered := make([]result.Match, 0, len(matches))
+	for _, r := range results {
+		filtered = append(filtered, r...)
+	}
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+
+	return filtered, errs
+}
+
+// filterChunkMatches removes all ranges of the chunk matches of the given file
+// match that start on a line whose blame does not satisfy the filters, as well
+// as chunk matches without remaining ranges. It returns the distinct authors of
+// the lines of the remaining ranges, in order of appearance.
+func (j *blameJob) filterChunkMatches(fm *result.FileMatch, hunks []*gitserver.Hunk) (authors []*result.PersonMatch) {
+	seen := map[string]struct{}{}
+
+	filteredChunks := fm.ChunkMatches[:0]
+	for _, chunk := range fm.ChunkMatches {
+		filteredRanges := chunk.Ranges[:0]
+		for _, rr := range chunk.Ranges {
+			hunk := hunkForLine(hunks, rr.Start.Line+1)
+			if hunk == nil || !j.matches(hunk) {
+				continue
+			}
+			filteredRanges = append(filteredRanges, rr)
+
+			author := &result.PersonMatch{Name: hunk.Author.Name, Email: hunk.Author.Email, Repo: fm.Repo, Path: fm.Path}
+			if _, ok := seen[author.Identifier()]; !ok {
+				seen[author.Identifier()] = struct{}{}
+				authors = append(authors, author)
+			}
+		}
+		if len(filteredRanges) == 0 {
+			continue
+		}
+		chunk.Ranges = filteredRanges
+		filteredChunks = append(filteredChunks, chunk)
+	}
+	fm.ChunkMatches = filteredChunks
+
+	return authors
+}
+
+// matches returns true if the given hunk satisfies the filters of the job.
+func (j *blameJob) matches(hunk *gitserver.Hunk) bool {
+	if !j.filters.Before.IsZero() && !hunk.Author.Date.Before(j.filters.Before) {
+		return false
+	}
+
+	authorMatches := func(re *regexp.Regexp) bool {
+		return re.MatchString(hunk.Author.Name) || re.MatchString(hunk.Author.Email)
+	}
+	for _, re := range j.includeAuthors {
+		if !authorMatches(re) {
+			return false
+		}
+	}
+	for _, re := range j.excludeAuthors {
+		if authorMatches(re) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// blameMatchedLines blames the lines of the given file match spanned by its
+// chunk matches at the commit the file was matched at.
+func blameMatchedLines(ctx context.Context, client gitserver.Client, fm *result.FileMatch) ([]*gitserver.Hunk, error) {
+	startLine, endLine := -1, -1
+	for _, chunk := range fm.ChunkMatches {
+		for _, rr := range chunk.Ranges {
+			if startLine == -1 || rr.Start.Line < startLine {
+				startLine = rr.Start.Line
+			}
+			if rr.Start.Line > endLine {
+				endLine = rr.Start.Line
+			}
+		}
+	}
+
+	if startLine == -1 {
+		return nil, nil
+	}
+
+	hunks, err := client.BlameFile(ctx, authz.DefaultSubRepoPermsChecker, fm.Repo.Name, fm.Path, &gitserver.BlameOptions{
+		NewestCommit: fm.CommitID,
+		StartLine:    startLine + 1,
+		EndLine:      endLine + 1,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "blaming %s in %s", fm.Path, fm.Repo.Name)
+	}
+	return hunks, nil
+}
+
+// hunkForLine returns the hunk containing the given 1-indexed line, or nil if
+// there is none.
+func hunkForLine(hunks []*gitserver.Hunk, line int) *gitserver.Hunk {
+	for _, hunk := range hunks {
+		if hunk.StartLine <= line && line < hunk.EndLine {
+			return hunk
+		}
+	}
+	return nil
+}
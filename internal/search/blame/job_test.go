@@ -0,0 +1,153 @@
+package blame
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func Test_applyBlameFiltering(t *testing.T) {
+	alice := gitdomain.Signature{Name: "Alice", Email: "alice@example.com", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	bob := gitdomain.Signature{Name: "Bob", Email: "bob@example.com", Date: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Lines 1-2 were last changed by alice, lines 3-4 by bob.
+	hunks := []*gitserver.Hunk{
+		{StartLine: 1, EndLine: 3, Author: alice},
+		{StartLine: 3, EndLine: 5, Author: bob},
+	}
+
+	repo := types.MinimalRepo{ID: 1, Name: "repo"}
+	chunk := func(line int) result.ChunkMatch {
+		return result.ChunkMatch{
+			ContentStart: result.Location{Line: line},
+			Ranges: result.Ranges{{
+				Start: result.Location{Line: line, Column: 0},
+				End:   result.Location{Line: line, Column: 3},
+			}},
+		}
+	}
+	fileMatch := func() *result.FileMatch {
+		return &result.FileMatch{
+			File:         result.File{Repo: repo, CommitID: "deadbeef", Path: "main.go"},
+			ChunkMatches: result.ChunkMatches{chunk(0), chunk(3)},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		filters       Filters
+		selectAuthors bool
+		matches       []result.Match
+		want          []result.Match
+	}{
+		{
+			name:    "include author",
+			filters: Filters{IncludeAuthors: []string{"alice"}},
+			matches: []result.Match{fileMatch()},
+			want: []result.Match{&result.FileMatch{
+				File:         result.File{Repo: repo, CommitID: "deadbeef", Path: "main.go"},
+				ChunkMatches: result.ChunkMatches{chunk(0)},
+			}},
+		},
+		{
+			name:    "case-sensitive author",
+			filters: Filters{IncludeAuthors: []string{"ALICE"}, CaseSensitive: true},
+			matches: []result.Match{fileMatch()},
+			want:    []result.Match{},
+		},
+		{
+			name:    "exclude author by email",
+			filters: Filters{ExcludeAuthors: []string{"alice@"}},
+			matches: []result.Match{fileMatch()},
+			want: []result.Match{&result.FileMatch{
+				File:         result.File{Repo: repo, CommitID: "deadbeef", Path: "main.go"},
+				ChunkMatches: result.ChunkMatches{chunk(3)},
+			}},
+		},
+		{
+			name:    "before",
+			filters: Filters{Before: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			matches: []result.Match{fileMatch()},
+			want: []result.Match{&result.FileMatch{
+				File:         result.File{Repo: repo, CommitID: "deadbeef", Path: "main.go"},
+				ChunkMatches: result.ChunkMatches{chunk(0)},
+			}},
+		},
+		{
+			name:    "drops files without remaining lines and non-content matches",
+			filters: Filters{IncludeAuthors: []string{"carol"}},
+			matches: []result.Match{
+				fileMatch(),
+				&result.FileMatch{File: result.File{Repo: repo, Path: "README.md"}},
+				&result.RepoMatch{Name: "repo", ID: 1},
+			},
+			want: []result.Match{},
+		},
+		{
+			name:          "select authors",
+			selectAuthors: true,
+			matches:       []result.Match{fileMatch()},
+			want: []result.Match{
+				&result.PersonMatch{Name: "Alice", Email: "alice@example.com", Repo: repo, Path: "main.go"},
+				&result.PersonMatch{Name: "Bob", Email: "bob@example.com", Repo: repo, Path: "main.go"},
+			},
+		},
+		{
+			name:          "select filtered authors",
+			filters:       Filters{IncludeAuthors: []string{"bob"}},
+			selectAuthors: true,
+			matches:       []result.Match{fileMatch()},
+			want: []result.Match{
+				&result.PersonMatch{Name: "Bob", Email: "bob@example.com", Repo: repo, Path: "main.go"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gsClient := gitserver.NewMockClient()
+			gsClient.BlameFileFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, repo api.RepoName, path string, opt *gitserver.BlameOptions) ([]*gitserver.Hunk, error) {
+				require.Equal(t, api.RepoName("repo"), repo)
+				require.Equal(t, "main.go", path)
+				require.Equal(t, &gitserver.BlameOptions{NewestCommit: "deadbeef", StartLine: 1, EndLine: 4}, opt)
+				return hunks, nil
+			})
+
+			j := New(nil, tt.filters, tt.selectAuthors, 0).(*blameJob)
+			matches, err := j.applyBlameFiltering(context.Background(), gsClient, tt.matches, math.MaxInt)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, matches)
+		})
+	}
+}
+
+func Test_applyBlameFiltering_limit(t *testing.T) {
+	alice := gitdomain.Signature{Name: "Alice", Email: "alice@example.com"}
+	gsClient := gitserver.NewMockClient()
+	gsClient.BlameFileFunc.SetDefaultReturn([]*gitserver.Hunk{{StartLine: 1, EndLine: 2, Author: alice}}, nil)
+
+	var matches []result.Match
+	for _, path := range []string{"a.go", "b.go", "c.go"} {
+		matches = append(matches, &result.FileMatch{
+			File: result.File{Repo: types.MinimalRepo{ID: 1, Name: "repo"}, CommitID: "deadbeef", Path: path},
+			ChunkMatches: result.ChunkMatches{{
+				Ranges: result.Ranges{{End: result.Location{Column: 3}}},
+			}},
+		})
+	}
+
+	j := New(nil, Filters{IncludeAuthors: []string{"alice"}}, false, 2).(*blameJob)
+	filtered, err := j.applyBlameFiltering(context.Background(), gsClient, matches, 2)
+	require.NoError(t, err)
+	require.Equal(t, matches[:2], filtered)
+}
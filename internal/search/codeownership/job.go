@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/hmarr/codeowners"
 	otlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -12,14 +13,20 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
-func New(child job.Job, includeOwners, excludeOwners []string) job.Job {
+// New returns a job that filters the file matches streamed by child by their
+// owners according to CODEOWNERS. If selectOwners is true, the owners of the
+// remaining file matches are streamed instead of the file matches
+// (select:file.owners).
+func New(child job.Job, includeOwners, excludeOwners []string, selectOwners bool) job.Job {
 	return &codeownershipJob{
 		child:         child,
 		includeOwners: includeOwners,
 		excludeOwners: excludeOwners,
+		selectOwners:  selectOwners,
 	}
 }
 
@@ -28,6 +35,7 @@ type codeownershipJob struct {
 
 	includeOwners []string
 	excludeOwners []string
+	selectOwners  bool
 }
 
 func (s *codeownershipJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
@@ -49,6 +57,14 @@ func (s *codeownershipJob) Run(ctx context.Context, clients job.RuntimeClients,
 			errs = errors.Append(errs, err)
 			mu.Unlock()
 		}
+		if s.selectOwners {
+			event.Results, err = selectCodeOwners(ctx, clients.Gitserver, &rules, event.Results)
+			if err != nil {
+				mu.Lock()
+				errs = errors.Append(errs, err)
+				mu.Unlock()
+			}
+		}
 		stream.Send(event)
 	})
 
@@ -71,6 +87,7 @@ func (s *codeownershipJob) Fields(v job.Verbosity) (res []otlog.Field) {
 		res = append(res,
 			trace.Strings("includeOwners", s.includeOwners),
 			trace.Strings("excludeOwners", s.excludeOwners),
+			otlog.Bool("selectOwners", s.selectOwners),
 		)
 	}
 	return res
@@ -128,6 +145,49 @@ matchesLoop:
 	return filtered, errs
 }
 
+// selectCodeOwners replaces each file match by the owners of the file, as
+// assigned by CODEOWNERS. File matches without owners are dropped.
+func selectCodeOwners(
+	ctx context.Context,
+	gitserver gitserver.Client,
+	rules *RulesCache,
+	matches []result.Match) ([]result.Match, error) {
+	var errs error
+
+	// A file match may have several owners, so results cannot be replaced in
+	// place.
+	selected := make([]result.Match, 0, len(matches))
+	for _, m := range matches {
+		mm, ok := m.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+
+		ruleset, err := rules.GetFromCacheOrFetch(ctx, gitserver, mm.Repo.Name, mm.CommitID)
+		if err != nil {
+			errs = errors.Append(errs, err)
+		}
+
+		owners, err := ruleset.Match(mm.File.Path)
+		if err != nil {
+			errs = errors.Append(errs, err)
+		}
+
+		for _, owner := range owners {
+			selected = append(selected, newOwnerMatch(owner, mm.Repo, mm.Path))
+		}
+	}
+
+	return selected, errs
+}
+
+func newOwnerMatch(owner codeowners.Owner, repo types.MinimalRepo, path string) *result.PersonMatch {
+	if owner.Type == codeowners.EmailOwner {
+		return &result.PersonMatch{Email: owner.Value, Repo: repo, Path: path}
+	}
+	return &result.PersonMatch{Handle: owner.String(), Repo: repo, Path: path}
+}
+
 func containsOwner(owners Owners, owner string) bool {
 	for _, o := range owners {
 		if o.String() == owner {
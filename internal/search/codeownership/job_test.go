@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func Test_applyCodeOwnershipFiltering(t *testing.T) {
@@ -121,3 +122,89 @@ func Test_applyCodeOwnershipFiltering(t *testing.T) {
 		})
 	}
 }
+
+func Test_selectCodeOwners(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}
+
+	tests := []struct {
+		name        string
+		matches     []result.Match
+		repoContent map[string]string
+		want        autogold.Value
+	}{
+		{
+			name: "drops all matches if there is no code owners file",
+			matches: []result.Match{
+				&result.FileMatch{
+					File: result.File{
+						Repo: repo,
+						Path: "README.md",
+					},
+				},
+			},
+			want: autogold.Want("no owners", []result.Match{}),
+		},
+		{
+			name: "selects owners of matched files",
+			matches: []result.Match{
+				&result.FileMatch{
+					File: result.File{
+						Repo: repo,
+						Path: "README.md",
+					},
+				},
+				&result.FileMatch{
+					File: result.File{
+						Repo: repo,
+						Path: "package.json",
+					},
+				},
+				&result.RepoMatch{
+					Name: repo.Name,
+					ID:   repo.ID,
+				},
+			},
+			repoContent: map[string]string{
+				"CODEOWNERS": "README.md @sqs alice@example.com\n*.json @sourcegraph/frontend\n",
+			},
+			want: autogold.Want("owners", []result.Match{
+				&result.PersonMatch{
+					Handle: "@sqs",
+					Repo:   repo,
+					Path:   "README.md",
+				},
+				&result.PersonMatch{
+					Email: "alice@example.com",
+					Repo:  repo,
+					Path:  "README.md",
+				},
+				&result.PersonMatch{
+					Handle: "@sourcegraph/frontend",
+					Repo:   repo,
+					Path:   "package.json",
+				},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := database.NewMockDB()
+			rules := NewRulesCache()
+
+			gitserver.Mocks.ReadFile = func(_ api.CommitID, file string) ([]byte, error) {
+				content, ok := tt.repoContent[file]
+				if !ok {
+					return nil, errors.New("file does not exist")
+				}
+				return []byte(content), nil
+			}
+			t.Cleanup(func() { gitserver.Mocks.ReadFile = nil })
+
+			matches, _ := selectCodeOwners(ctx, gitserver.NewClient(db), &rules, tt.matches)
+
+			tt.want.Equal(t, matches)
+		})
+	}
+}
@@ -33,6 +33,11 @@ type SearchJob struct {
 	IncludeModifiedFiles bool
 	Concurrency          int
 
+	// RangeDiff, if true, searches the aggregated diff of each revision range
+	// instead of the commits in it, and streams one CommitDiffMatch per
+	// matching file. It requires Diff to be true.
+	RangeDiff bool
+
 	// CodeMonitorSearchWrapper, if set, will wrap the commit search with extra logic specific to code monitors.
 	CodeMonitorSearchWrapper CodeMonitorHook `json:"-"`
 }
@@ -66,12 +71,20 @@ func (j *SearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 			IncludeDiff:          j.Diff,
 			Limit:                j.Limit,
 			IncludeModifiedFiles: j.IncludeModifiedFiles,
+			RangeDiff:            j.RangeDiff,
 		}
 
 		onMatches := func(in []protocol.CommitMatch) {
 			res := make([]result.Match, 0, len(in))
 			for _, protocolMatch := range in {
-				res = append(res, protocolMatchToCommitMatch(repoRev.Repo, j.Diff, protocolMatch))
+				cm := protocolMatchToCommitMatch(repoRev.Repo, j.Diff, protocolMatch)
+				if j.RangeDiff {
+					for _, dm := range cm.CommitToDiffMatches() {
+						res = append(res, dm)
+					}
+					continue
+				}
+				res = append(res, cm)
 			}
 			stream.Send(streaming.SearchEvent{
 				Results: res,
@@ -110,6 +123,9 @@ func (j *SearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 }
 
 func (j SearchJob) Name() string {
+	if j.RangeDiff {
+		return "RangeDiffSearchJob"
+	}
 	if j.Diff {
 		return "DiffSearchJob"
 	}
@@ -130,6 +146,9 @@ func (j *SearchJob) Fields(v job.Verbosity) (res []log.Field) {
 			log.Bool("diff", j.Diff),
 			log.Int("limit", j.Limit),
 		)
+		if j.RangeDiff {
+			res = append(res, log.Bool("rangeDiff", j.RangeDiff))
+		}
 	}
 	return res
 }
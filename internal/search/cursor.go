@@ -0,0 +1,205 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Cursor is the position of a search after a page of results. Streaming
+// clients receive it once a search hits its result limit, and pass it back to
+// fetch the next page of results of the same query.
+//
+// A cursor records the position of every backend of the search: the page of
+// repositories every repository pager of the search was at, so that
+// repositories of earlier pages are not searched again, and the number of
+// results each backend already returned per repository, such as the document
+// offset of a Zoekt search in the shards of a repository. Backends stream the
+// results of a repository in a stable order, so later pages skip exactly the
+// results returned by earlier pages, no matter how the results of different
+// repositories are interleaved.
+type Cursor struct {
+	// Query identifies the query the cursor was created for.
+	Query uint64 `json:"q"`
+
+	// Pagers is the page of repositories each repository pager of the search
+	// was at, keyed by the ID of the pager.
+	Pagers map[int][]*types.Cursor `json:"p,omitempty"`
+
+	// Offsets is the number of results of each repository and result type
+	// already returned, keyed by offsetKey.
+	Offsets map[string]int `json:"o,omitempty"`
+}
+
+// maxCursorLength is the maximum length of an encoded cursor. Cursors grow
+// with the number of repositories with results, not with the number of
+// results, so this is only reached by malformed cursors.
+const maxCursorLength = 512 * 1024
+
+// NewCursor returns an empty cursor for the first page of the given query.
+func NewCursor(query string) *Cursor {
+	return &Cursor{Query: queryHash(query)}
+}
+
+// DecodeCursor decodes a cursor returned by Encode. It returns an error if
+// the cursor is malformed or was created for a different query.
+func DecodeCursor(s, query string) (*Cursor, error) {
+	if len(s) > maxCursorLength {
+		return nil, errors.New("cursor is too large")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed cursor")
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errors.Wrap(err, "malformed cursor")
+	}
+	if c.Query != queryHash(query) {
+		return nil, errors.New("cursor was created for a different query")
+	}
+	return &c, nil
+}
+
+// Encode returns the opaque string representation of c.
+func (c *Cursor) Encode() string {
+	b, _ := json.Marshal(c) // Invariant: cursors can always be marshaled
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func queryHash(query string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(query))
+	return h.Sum64()
+}
+
+// offsetKey returns the key of the offset of m in cursors. Results of
+// different types are streamed by different backends, so each type has its
+// own offset.
+func offsetKey(m result.Match) string {
+	return strconv.Itoa(int(m.RepoName().ID)) + "/" + strconv.Itoa(m.Key().TypeRank)
+}
+
+// Paginator computes the cursor of the next page of a search while it runs.
+// It is shared by all jobs of a search through its context.
+type Paginator struct {
+	resume *Cursor
+
+	mu       sync.Mutex
+	skipped  map[string]int
+	next     *Cursor
+	limitHit bool
+}
+
+// NewPaginator returns a paginator for the page of results after cursor.
+func NewPaginator(cursor *Cursor) *Paginator {
+	next := &Cursor{
+		Query:   cursor.Query,
+		Pagers:  make(map[int][]*types.Cursor, len(cursor.Pagers)),
+		Offsets: make(map[string]int, len(cursor.Offsets)),
+	}
+	for id, pos := range cursor.Pagers {
+		next.Pagers[id] = pos
+	}
+	for k, offset := range cursor.Offsets {
+		next.Offsets[k] = offset
+	}
+
+	return &Paginator{
+		resume:  cursor,
+		skipped: make(map[string]int, len(cursor.Offsets)),
+		next:    next,
+	}
+}
+
+// PagerStart returns the page of repositories the pager with the given ID
+// should start at. It is nil for the first page.
+func (p *Paginator) PagerStart(id int) []*types.Cursor {
+	return p.resume.Pagers[id]
+}
+
+// PagerAt records that the pager with the given ID is about to search the
+// page of repositories starting at pos. All results of earlier pages must
+// have been sent before.
+func (p *Paginator) PagerAt(id int, pos []*types.Cursor) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.next.Pagers[id] = pos
+}
+
+// Skip returns true if m was returned by an earlier page. It must be called
+// for every result of the search in the order the backends stream them.
+func (p *Paginator) Skip(m result.Match) bool {
+	k := offsetKey(m)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.skipped[k] >= p.resume.Offsets[k] {
+		return false
+	}
+	p.skipped[k]++
+	return true
+}
+
+// Record records that the given results are returned by this page.
+func (p *Paginator) Record(matches result.Matches) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, m := range matches {
+		p.next.Offsets[offsetKey(m)]++
+	}
+}
+
+// LimitHit records that the search stopped at its result limit before finding
+// all results, so that there is a next page.
+func (p *Paginator) LimitHit() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limitHit = true
+}
+
+// Next returns the cursor of the next page, or nil if there are no further
+// results.
+func (p *Paginator) Next() *Cursor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.limitHit {
+		return nil
+	}
+
+	next := &Cursor{
+		Query:   p.next.Query,
+		Pagers:  make(map[int][]*types.Cursor, len(p.next.Pagers)),
+		Offsets: make(map[string]int, len(p.next.Offsets)),
+	}
+	for id, pos := range p.next.Pagers {
+		next.Pagers[id] = pos
+	}
+	for k, offset := range p.next.Offsets {
+		next.Offsets[k] = offset
+	}
+	return next
+}
+
+type paginatorKey struct{}
+
+// WithPaginator returns a context for a search which computes the cursor of
+// its next page with p.
+func WithPaginator(ctx context.Context, p *Paginator) context.Context {
+	return context.WithValue(ctx, paginatorKey{}, p)
+}
+
+// PaginatorFromContext returns the paginator of the search running with ctx,
+// or nil if the search is not paginated.
+func PaginatorFromContext(ctx context.Context) *Paginator {
+	p, _ := ctx.Value(paginatorKey{}).(*Paginator)
+	return p
+}
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCursor(t *testing.T) {
+	fileMatch := func(repo int, path string) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Repo: types.MinimalRepo{ID: api.RepoID(repo), Name: "repo"}, Path: path}}
+	}
+
+	p := NewPaginator(NewCursor("foo"))
+	require.Nil(t, p.PagerStart(1))
+
+	pos := []*types.Cursor{{Column: "stars", Value: "42@7", Direction: "next"}}
+	p.PagerAt(1, pos)
+	a := fileMatch(1, "a.go")
+	require.False(t, p.Skip(a), "results of the first page are not skipped")
+	p.Record(result.Matches{a})
+
+	require.Nil(t, p.Next(), "no next page before the limit is hit")
+	p.LimitHit()
+	cursor := p.Next()
+	require.NotNil(t, cursor)
+
+	t.Run("decode", func(t *testing.T) {
+		decoded, err := DecodeCursor(cursor.Encode(), "foo")
+		require.NoError(t, err)
+		require.Equal(t, cursor, decoded)
+
+		next := NewPaginator(decoded)
+		require.Equal(t, pos, next.PagerStart(1))
+		require.True(t, next.Skip(a), "first result of the repository was returned")
+		require.False(t, next.Skip(fileMatch(1, "b.go")))
+	})
+
+	t.Run("offsets per type", func(t *testing.T) {
+		next := NewPaginator(cursor)
+		require.False(t, next.Skip(&result.RepoMatch{ID: 1, Name: "repo"}))
+		require.True(t, next.Skip(a))
+	})
+
+	t.Run("offsets accumulate", func(t *testing.T) {
+		next := NewPaginator(cursor)
+		require.True(t, next.Skip(a))
+		b := fileMatch(1, "b.go")
+		require.False(t, next.Skip(b))
+		next.Record(result.Matches{b})
+		next.LimitHit()
+
+		last := NewPaginator(next.Next())
+		require.True(t, last.Skip(a))
+		require.True(t, last.Skip(b))
+		require.False(t, last.Skip(fileMatch(1, "c.go")))
+	})
+
+	t.Run("different query", func(t *testing.T) {
+		_, err := DecodeCursor(cursor.Encode(), "bar")
+		require.Error(t, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := DecodeCursor("not a cursor", "foo")
+		require.Error(t, err)
+	})
+}
@@ -1,17 +1,24 @@
 package filter
 
 import (
+	"strconv"
 	"strings"
 
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 const (
+	Blame      = "blame"
 	Commit     = "commit"
 	Content    = "content"
 	File       = "file"
 	Repository = "repo"
 	Symbol     = "symbol"
+
+	// Group is the field of content which selects the values of a capture
+	// group of the pattern, as in content.group(1) or content.group(name).
+	Group = "group"
 )
 
 // SelectPath represents a parsed and validated select value
@@ -30,18 +37,58 @@ func (sp SelectPath) Root() string {
 	return ""
 }
 
+// CaptureGroup returns the number or name of the capture group selected by
+// content.group(...), and whether sp selects a capture group.
+func (sp SelectPath) CaptureGroup() (string, bool) {
+	if len(sp) != 2 || sp[0] != Content {
+		return "", false
+	}
+	if m := groupSelector.FindStringSubmatch(sp[1]); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// groupSelector matches the field selecting a capture group by number or name.
+var groupSelector = lazyregexp.New(`^group\((\w+)\)$`)
+
+// CaptureGroupIndex returns the index of the capture group selected by
+// content.group(...) in a pattern with the given subexpression names, as
+// returned by Regexp.SubexpNames. Groups are selected by number, where 0 is
+// the whole match, or by name.
+func CaptureGroupIndex(subexpNames []string, group string) (int, error) {
+	if i, err := strconv.Atoi(group); err == nil {
+		if i < 0 || i >= len(subexpNames) {
+			return 0, errors.Errorf("the pattern has no capture group %d, it has %d capture groups", i, len(subexpNames)-1)
+		}
+		return i, nil
+	}
+	for i, name := range subexpNames {
+		if i > 0 && name == group {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("the pattern has no capture group named %q", group)
+}
+
 type object map[string]object
 
 var validSelectors = object{
+	Blame: object{
+		"author": nil,
+	},
 	Commit: object{
 		"diff": object{
 			"added":   nil,
 			"removed": nil,
 		},
 	},
-	Content: nil,
+	Content: object{
+		Group: nil,
+	},
 	File: {
 		"directory": nil,
+		"owners":    nil,
 		"path":      nil,
 	},
 	Repository: nil,
@@ -80,7 +127,13 @@ func SelectPathFromString(s string) (SelectPath, error) {
 	fields := strings.Split(s, ".")
 	cur := validSelectors
 	for _, field := range fields {
-		child, ok := cur[field]
+		key := field
+		if m := groupSelector.FindStringSubmatch(field); m != nil {
+			key = Group
+		} else if field == Group {
+			return SelectPath{}, errors.Errorf("field %q on select path %q requires a capture group, like content.group(1)", field, s)
+		}
+		child, ok := cur[key]
 		if !ok {
 			return SelectPath{}, errors.Errorf("invalid field %q on select path %q", field, s)
 		}
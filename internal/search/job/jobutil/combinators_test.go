@@ -2,17 +2,20 @@ package jobutil
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -100,6 +103,61 @@ func TestLimitJob(t *testing.T) {
 		require.Equal(t, 5, len(sent))
 	})
 
+	t.Run("paginate", func(t *testing.T) {
+		// Every backend streams the results of a repository in the same
+		// order, but the results of different repositories are interleaved
+		// differently by every page.
+		reverse := false
+		mockJob := mockjob.NewMockJob()
+		mockJob.RunFunc.SetDefaultHook(func(ctx context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			for i := 0; i < 10; i++ {
+				if ctx.Err() != nil {
+					return nil, nil
+				}
+				repo := api.RepoID(1 + i%2)
+				if reverse {
+					repo = api.RepoID(2 - i%2)
+				}
+				s.Send(streaming.SearchEvent{
+					Results: []result.Match{&result.FileMatch{File: result.File{
+						Repo: types.MinimalRepo{ID: repo},
+						Path: strconv.Itoa(i / 2),
+					}}},
+				})
+			}
+			return nil, nil
+		})
+
+		runPage := func(cursor *search.Cursor) ([]string, *search.Cursor) {
+			var sent []string
+			stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+				for _, m := range e.Results {
+					fm := m.(*result.FileMatch)
+					sent = append(sent, fmt.Sprintf("%d:%s", fm.Repo.ID, fm.Path))
+				}
+			})
+
+			paginator := search.NewPaginator(cursor)
+			ctx := search.WithPaginator(context.Background(), paginator)
+			_, err := NewLimitJob(4, mockJob).Run(ctx, job.RuntimeClients{}, stream)
+			require.NoError(t, err)
+			return sent, paginator.Next()
+		}
+
+		sent, cursor := runPage(search.NewCursor("foo"))
+		require.Equal(t, []string{"1:0", "2:0", "1:1", "2:1"}, sent)
+		require.NotNil(t, cursor)
+
+		reverse = true
+		sent, cursor = runPage(cursor)
+		require.Equal(t, []string{"2:2", "1:2", "2:3", "1:3"}, sent)
+		require.NotNil(t, cursor)
+
+		sent, cursor = runPage(cursor)
+		require.Equal(t, []string{"2:4", "1:4"}, sent)
+		require.Nil(t, cursor)
+	})
+
 	t.Run("NewLimitJob propagates noop", func(t *testing.T) {
 		job := NewLimitJob(10, NewNoopJob())
 		require.Equal(t, NewNoopJob(), job)
@@ -0,0 +1,204 @@
+package jobutil
+
+import (
+	"context"
+
+	zoektapi "github.com/sourcegraph/zoekt"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/search/structural"
+	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxExplainRepos is the maximum number of repositories we resolve to
+// estimate the cost of a job. Estimates of jobs which search more
+// repositories are lower bounds.
+const maxExplainRepos = 10000
+
+// JobEstimate is the estimated cost of a job of a planned search which
+// searches repositories.
+type JobEstimate struct {
+	// Job is the name of the job.
+	Job string
+
+	// Repos is the number of repositories the job searches. It is a lower
+	// bound if ReposLimitHit is true.
+	Repos         int
+	ReposLimitHit bool
+
+	// Indexed and Unindexed split Repos by whether the job searches them
+	// with Zoekt or by reading their contents with searcher or gitserver.
+	Indexed   int
+	Unindexed int
+
+	// Global is true if the job searches all indexed repositories with a
+	// single request to Zoekt, rather than resolving repositories first.
+	Global bool
+
+	// Fanout is the number of requests the job sends to backends which
+	// search a single repository, searcher and gitserver. Jobs with a large
+	// fan out are slow.
+	Fanout int
+}
+
+// Explain estimates the cost of running the planned job tree j, without
+// running it. Estimates are returned for the jobs of j which search
+// repositories, in the order they appear in j.
+func Explain(ctx context.Context, clients job.RuntimeClients, j job.Job) ([]*JobEstimate, error) {
+	e := &explainer{
+		clients:  clients,
+		resolver: searchrepos.NewResolver(clients.Logger, clients.DB, clients.SearcherURLs, clients.Zoekt),
+		resolved: map[string]*searchrepos.Resolved{},
+	}
+
+	var (
+		estimates []*JobEstimate
+		errs      error
+	)
+	job.Visit(j, func(d job.Describer) {
+		estimate, err := e.estimate(ctx, d)
+		if err != nil {
+			errs = errors.Append(errs, err)
+		} else if estimate != nil {
+			estimates = append(estimates, estimate)
+		}
+	})
+	return estimates, errs
+}
+
+type explainer struct {
+	clients  job.RuntimeClients
+	resolver *searchrepos.Resolver
+
+	// resolved caches the first page of repositories resolved for each
+	// set of repository options, since jobs of the same query usually
+	// share them.
+	resolved map[string]*searchrepos.Resolved
+}
+
+// estimate returns the estimated cost of d, or nil if d does not search
+// repositories itself.
+func (e *explainer) estimate(ctx context.Context, d job.Describer) (*JobEstimate, error) {
+	switch v := d.(type) {
+	case *repoPagerJob:
+		estimate, err := e.partition(ctx, v, v.repoOpts, v.repoOpts.UseIndex, v.containsRefGlobs)
+		if err != nil {
+			return nil, err
+		}
+		fuzzy := job.HasDescendent[*zoekt.FuzzyPathSearchJob](v)
+		if job.HasDescendent[*searcher.TextSearchJob](v) || job.HasDescendent[*searcher.SymbolSearchJob](v) || fuzzy {
+			estimate.Fanout = estimate.Unindexed
+		}
+		if !job.HasDescendent[*zoekt.RepoSubsetTextSearchJob](v) && !job.HasDescendent[*zoekt.SymbolSearchJob](v) && !fuzzy {
+			// Only the unindexed repositories are searched.
+			estimate.Repos = estimate.Unindexed
+			estimate.Indexed = 0
+		}
+		return estimate, nil
+
+	case *structural.SearchJob:
+		estimate, err := e.partition(ctx, v, v.RepoOpts, v.UseIndex, v.ContainsRefGlobs)
+		if err != nil {
+			return nil, err
+		}
+		// Structural search runs on searcher for every repository. Zoekt
+		// only narrows down the files of indexed repositories.
+		estimate.Fanout = estimate.Repos
+		return estimate, nil
+
+	case *commit.SearchJob:
+		resolved, err := e.resolve(ctx, v.RepoOpts)
+		if err != nil {
+			return nil, err
+		}
+		// Commit and diff searches run on gitserver for every repository.
+		return &JobEstimate{
+			Job:           v.Name(),
+			Repos:         len(resolved.RepoRevs),
+			ReposLimitHit: resolved.Next != nil,
+			Unindexed:     len(resolved.RepoRevs),
+			Fanout:        len(resolved.RepoRevs),
+		}, nil
+
+	case *zoekt.GlobalTextSearchJob:
+		return e.global(ctx, v, v.GlobalZoektQuery, v.RepoOpts)
+
+	case *zoekt.GlobalSymbolSearchJob:
+		return e.global(ctx, v, v.GlobalZoektQuery, v.RepoOpts)
+	}
+	return nil, nil
+}
+
+// global counts the indexed repositories a global search with q searches.
+// Like the job d, it only counts the repositories the actor of ctx may
+// search.
+func (e *explainer) global(ctx context.Context, d job.Describer, q *zoekt.GlobalZoektQuery, opts search.RepoOptions) (*JobEstimate, error) {
+	scope := zoekt.GlobalRepoScope(ctx, e.clients.Logger, e.clients.DB, q, opts)
+	list, err := e.clients.Zoekt.List(ctx, scope, &zoektapi.ListOptions{Minimal: true})
+	if err != nil {
+		return nil, err
+	}
+	return &JobEstimate{
+		Job:     d.Name(),
+		Repos:   len(list.Minimal),
+		Indexed: len(list.Minimal),
+		Global:  true,
+	}, nil
+}
+
+// partition resolves the repositories of opts and splits them into indexed
+// and unindexed repositories like the job d will.
+func (e *explainer) partition(ctx context.Context, d job.Describer, opts search.RepoOptions, useIndex query.YesNoOnly, containsRefGlobs bool) (*JobEstimate, error) {
+	resolved, err := e.resolve(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed, unindexed, err := zoekt.PartitionRepos(
+		ctx,
+		e.clients.Logger,
+		resolved.RepoRevs,
+		e.clients.Zoekt,
+		search.TextRequest,
+		useIndex,
+		containsRefGlobs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	numIndexed := 0
+	if indexed != nil {
+		numIndexed = len(indexed.RepoRevs)
+	}
+	return &JobEstimate{
+		Job:           d.Name(),
+		Repos:         numIndexed + len(unindexed),
+		ReposLimitHit: resolved.Next != nil,
+		Indexed:       numIndexed,
+		Unindexed:     len(unindexed),
+	}, nil
+}
+
+// resolve returns the first page of up to maxExplainRepos repositories of
+// opts.
+func (e *explainer) resolve(ctx context.Context, opts search.RepoOptions) (*searchrepos.Resolved, error) {
+	opts.Limit = maxExplainRepos
+	key := opts.String()
+	if resolved, ok := e.resolved[key]; ok {
+		return resolved, nil
+	}
+
+	resolved, err := e.resolver.Resolve(ctx, opts)
+	if err != nil && !errors.Is(err, &searchrepos.MissingRepoRevsError{}) { // Non-fatal errors
+		return nil, err
+	}
+	e.resolved[key] = &resolved
+	return &resolved, nil
+}
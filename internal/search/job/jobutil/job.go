@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	blamejob "github.com/sourcegraph/sourcegraph/internal/search/blame"
 	codeownershipjob "github.com/sourcegraph/sourcegraph/internal/search/codeownership"
 	"github.com/sourcegraph/sourcegraph/internal/search/commit"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
@@ -55,7 +56,7 @@ func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
 		}
 	}
 
-	return NewAlertJob(inputs, jobTree), nil
+	return assignPagerIDs(NewAlertJob(inputs, jobTree)), nil
 }
 
 // NewBasicJob converts a query.Basic into its job tree representation.
@@ -99,7 +100,26 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 			selector:       selector,
 		}
 
-		if resultTypes.Has(result.TypeFile | result.TypePath) {
+		if b.IsFuzzy() {
+			// Fuzzy searches score the paths of the resolved repositories
+			// instead of running the text searches below. Each page of
+			// repositories is ranked on its own, so the results of all
+			// pages are ranked together.
+			job, err := builder.newFuzzyPathSearch()
+			if err != nil {
+				return nil, err
+			}
+			pattern, _ := b.Pattern.(query.Pattern) // Invariant: fuzzy:yes requires a single pattern
+			addJob(&zoekt.FuzzyPathRankJob{
+				Pattern: pattern.Value,
+				Limit:   int(fileMatchLimit),
+				Child: &repoPagerJob{
+					child:            &reposPartialJob{job},
+					repoOpts:         repoOptions,
+					containsRefGlobs: query.ContainsRefGlobs(b.ToParseTree()),
+				},
+			})
+		} else if resultTypes.Has(result.TypeFile | result.TypePath) {
 			// Create Global Text Search jobs.
 			if repoUniverseSearch {
 				job, err := builder.newZoektGlobalSearch(search.TextRequest)
@@ -156,6 +176,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 				Limit:                int(fileMatchLimit),
 				IncludeModifiedFiles: authz.SubRepoEnabled(authz.DefaultSubRepoPermsChecker),
 				Concurrency:          4,
+				RangeDiff:            isRangeDiffSearch(b, resultTypes),
 			})
 		}
 
@@ -183,26 +204,74 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
-	{ // Apply code ownership post-search filter
-		if includeOwners, excludeOwners := b.FileHasOwner(); inputs.Features.CodeOwnershipFilters == true && (len(includeOwners) > 0 || len(excludeOwners) > 0) {
-			basicJob = codeownershipjob.New(basicJob, includeOwners, excludeOwners)
+	{ // Apply code ownership post-search filter and select:file.owners
+		includeOwners, excludeOwners := b.FileHasOwner()
+		v, _ := b.ToParseTree().StringValue(query.FieldSelect)
+		selectOwners := v == filter.File+".owners"
+		if selectOwners && !inputs.Features.CodeOwnershipFilters {
+			// Without the code ownership job, select:file.owners would
+			// silently return no results.
+			return nil, errors.New("select:file.owners requires the experimental code-ownership feature flag")
+		}
+		if inputs.Features.CodeOwnershipFilters == true && (len(includeOwners) > 0 || len(excludeOwners) > 0 || selectOwners) {
+			basicJob = codeownershipjob.New(basicJob, includeOwners, excludeOwners, selectOwners)
 		}
 	}
 
-	{ // Apply selectors
-		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
-			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
-			basicJob = NewSelectJob(sp, basicJob)
+	{ // Apply blame post-search filter and select:blame.author
+		includeAuthors, excludeAuthors := b.IncludeExcludeValues(query.FieldBlameAuthor)
+		before := b.FindValue(query.FieldBlameBefore)
+		v, _ := b.ToParseTree().StringValue(query.FieldSelect)
+		selectAuthors := strings.HasPrefix(v, filter.Blame)
+		if len(includeAuthors) > 0 || len(excludeAuthors) > 0 || before != "" || selectAuthors {
+			filters := blamejob.Filters{
+				IncludeAuthors: includeAuthors,
+				ExcludeAuthors: excludeAuthors,
+				CaseSensitive:  b.IsCaseSensitive(),
+			}
+			if before != "" {
+				filters.Before, _ = query.ParseGitDate(before, time.Now) // Invariant: blame.before already validated
+			}
+			maxResults := b.ToParseTree().MaxResults(inputs.DefaultLimit())
+			basicJob = blamejob.New(basicJob, filters, selectAuthors, maxResults)
 		}
 	}
 
 	{ // Apply subrepo permissions checks
+		// Checked before selectors, since they deduplicate people selected
+		// from several files of a repository by whichever file comes first.
 		checker := authz.DefaultSubRepoPermsChecker
 		if authz.SubRepoEnabled(checker) {
 			basicJob = NewFilterJob(basicJob)
 		}
 	}
 
+	{ // Apply selectors
+		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
+			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
+			if _, ok := sp.CaptureGroup(); !ok {
+				basicJob = NewSelectJob(sp, basicJob)
+			}
+		}
+	}
+
+	{ // Apply select:content.group()
+		// Like other selectors, capture group values are selected after
+		// subrepo permissions are checked, since they no longer belong to a
+		// file which can be checked.
+		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
+			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
+			if _, ok := sp.CaptureGroup(); ok {
+				pattern, _ := originalQuery.Pattern.(query.Pattern) // Invariant: select:content.group() requires a single pattern
+				var err error
+				basicJob, err = NewCaptureGroupSelectJob(sp, pattern.Value, b.IsCaseSensitive(), basicJob)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
 	{ // Apply limit
 		maxResults := b.ToParseTree().MaxResults(inputs.DefaultLimit())
 		basicJob = NewLimitJob(maxResults, basicJob)
@@ -308,8 +377,9 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 		// of the above logic should be used to create search jobs
 		// across all of Sourcegraph.
 
-		// Create Text Search Jobs
-		if resultTypes.Has(result.TypeFile | result.TypePath) {
+		// Create Text Search Jobs. Fuzzy searches list the paths of
+		// unindexed repos themselves.
+		if resultTypes.Has(result.TypeFile|result.TypePath) && !f.IsFuzzy() {
 			// Create Text Search jobs over repo set.
 			if !skipRepoSubsetSearch {
 				searcherJob := &searcher.TextSearchJob{
@@ -600,6 +670,7 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 		Languages:                    langInclude,
 		PathPatternsAreCaseSensitive: b.IsCaseSensitive(),
 		CombyRule:                    b.FindValue(query.FieldCombyRule),
+		StructuralEngine:             b.FindValue(query.FieldEngine),
 		Index:                        b.Index(),
 		Select:                       selector,
 	}
@@ -612,7 +683,9 @@ func computeResultTypes(types []string, b query.Basic, searchType query.SearchTy
 	if searchType == query.SearchTypeStructural && !b.IsEmptyPattern() {
 		rts = result.TypeStructural
 	} else {
-		if len(types) == 0 {
+		if len(types) == 0 && b.IsFuzzy() {
+			rts = result.TypePath
+		} else if len(types) == 0 {
 			rts = result.TypeFile | result.TypePath | result.TypeRepo
 		} else {
 			for _, t := range types {
@@ -623,6 +696,16 @@ func computeResultTypes(types []string, b query.Basic, searchType query.SearchTy
 	return rts
 }
 
+// isRangeDiffSearch returns true if b is a diff search over revision ranges,
+// such as `repo:foo@v1...v2 type:diff bar`. Such searches match the aggregated
+// diff of each range (git diff v1...v2) rather than each commit in it.
+func isRangeDiffSearch(b query.Basic, resultTypes result.Types) bool {
+	if !resultTypes.Has(result.TypeDiff) {
+		return false
+	}
+	return query.ContainsRevRanges(b.ToParseTree())
+}
+
 func toRepoOptions(b query.Basic, userSettings *schema.Settings) search.RepoOptions {
 	repoFilters, minusRepoFilters := b.Repositories()
 
@@ -768,6 +851,56 @@ func (b *jobBuilder) newZoektSearch(typ search.IndexedRequestType) (job.Job, err
 	return nil, errors.Errorf("attempt to create unrecognized zoekt search with value %v", typ)
 }
 
+// newFuzzyPathSearch creates the job of a fuzzy:yes search. The file:
+// and lang: filters are applied by Zoekt for indexed repos, and to the
+// listed paths for unindexed repos.
+func (b *jobBuilder) newFuzzyPathSearch() (job.Job, error) {
+	pattern, _ := b.query.Pattern.(query.Pattern) // Invariant: fuzzy:yes requires a single pattern
+
+	filters := b.query
+	filters.Pattern = nil
+	zoektQuery, err := zoekt.QueryToZoektQuery(filters, b.resultTypes, b.features, search.TextRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	compile := func(pattern string) (*regexp.Regexp, error) {
+		if !b.query.IsCaseSensitive() {
+			pattern = "(?i)" + pattern
+		}
+		return regexp.Compile(pattern)
+	}
+
+	filesInclude, filesExclude := b.query.IncludeExcludeValues(query.FieldFile)
+	langInclude, langExclude := b.query.IncludeExcludeValues(query.FieldLang)
+	filesInclude = append(filesInclude, mapSlice(langInclude, query.LangToFileRegexp)...)
+	filesExclude = append(filesExclude, mapSlice(langExclude, query.LangToFileRegexp)...)
+
+	includePaths := make([]*regexp.Regexp, 0, len(filesInclude))
+	for _, pattern := range filesInclude {
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		includePaths = append(includePaths, re)
+	}
+	var excludePaths *regexp.Regexp
+	if len(filesExclude) > 0 {
+		excludePaths, err = compile(query.UnionRegExps(filesExclude))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &zoekt.FuzzyPathSearchJob{
+		Pattern:      pattern.Value,
+		Query:        zoektQuery,
+		IncludePaths: includePaths,
+		ExcludePaths: excludePaths,
+		Limit:        int(b.fileMatchLimit),
+	}, nil
+}
+
 func zoektQueryPatternsAsRegexps(q zoektquery.Q) (res []*regexp.Regexp) {
 	zoektquery.VisitAtoms(q, func(zoektQ zoektquery.Q) {
 		switch typedQ := zoektQ.(type) {
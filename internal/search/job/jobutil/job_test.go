@@ -683,6 +683,25 @@ func TestNewPlanJob(t *testing.T) {
 	}
 }
 
+func TestNewPlanJob_selectOwners(t *testing.T) {
+	plan, err := query.Pipeline(query.Init("foo select:file.owners", query.SearchTypeLiteral))
+	require.NoError(t, err)
+
+	newPlanJob := func(features *search.Features) error {
+		inputs := &search.Inputs{
+			UserSettings: &schema.Settings{},
+			PatternType:  query.SearchTypeLiteral,
+			Protocol:     search.Streaming,
+			Features:     features,
+		}
+		_, err := NewPlanJob(inputs, plan)
+		return err
+	}
+
+	require.Error(t, newPlanJob(&search.Features{}))
+	require.NoError(t, newPlanJob(&search.Features{CodeOwnershipFilters: true}))
+}
+
 func TestToEvaluateJob(t *testing.T) {
 	test := func(input string, protocol search.Protocol) string {
 		q, _ := query.ParseLiteral(input)
@@ -37,10 +37,24 @@ func (l *LimitJob) Run(ctx context.Context, clients job.RuntimeClients, s stream
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+
+	paginator := search.PaginatorFromContext(ctx)
+	if paginator != nil {
+		// Record the results of this page once they are limited.
+		s = newRecordStream(paginator, s)
+	}
 	s = newLimitStream(l.limit, s, func() {
 		tr.LazyPrintf("limit hit, canceling child context")
+		if paginator != nil {
+			paginator.LimitHit()
+		}
 		cancel()
 	})
+	if paginator != nil {
+		// Drop the results of earlier pages before they count towards the
+		// limit.
+		s = newSkipStream(paginator, s)
+	}
 
 	alert, err = l.child.Run(ctx, clients, s)
 	if errors.Is(err, context.Canceled) {
@@ -128,3 +142,27 @@ func newLimitStream(limit int, parent streaming.Sender, onLimitHit func()) strea
 	stream.remaining.Store(int64(limit))
 	return stream
 }
+
+// newRecordStream returns a child Stream of parent which records the results
+// sent to parent with the paginator.
+func newRecordStream(paginator *search.Paginator, parent streaming.Sender) streaming.Sender {
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		paginator.Record(event.Results)
+		parent.Send(event)
+	})
+}
+
+// newSkipStream returns a child Stream of parent which drops results which
+// were returned by earlier pages of the search.
+func newSkipStream(paginator *search.Paginator, parent streaming.Sender) streaming.Sender {
+	return streaming.StreamFunc(func(event streaming.SearchEvent) {
+		filtered := event.Results[:0]
+		for _, m := range event.Results {
+			if !paginator.Skip(m) {
+				filtered = append(filtered, m)
+			}
+		}
+		event.Results = filtered
+		parent.Send(event)
+	})
+}
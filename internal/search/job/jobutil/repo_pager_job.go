@@ -15,6 +15,10 @@ import (
 )
 
 type repoPagerJob struct {
+	// id identifies the pager in cursors of paginated searches. It is zero
+	// for pagers which cannot be resumed. See assignPagerIDs.
+	id int
+
 	repoOpts         search.RepoOptions
 	containsRefGlobs bool                          // whether to include repositories with refs
 	child            job.PartialJob[resolvedRepos] // child job tree that need populating a repos field to run
@@ -71,6 +75,11 @@ func setRepos(j job.Job, indexed *zoekt.IndexedRepoRevs, unindexed []*search.Rep
 			cp := *v
 			cp.Repos = unindexed
 			return &cp
+		case *zoekt.FuzzyPathSearchJob:
+			cp := *v
+			cp.Repos = indexed
+			cp.Unindexed = unindexed
+			return &cp
 		default:
 			return j
 		}
@@ -83,8 +92,22 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 
 	var maxAlerter search.MaxAlerter
 
+	// Paginated searches resume at the page of repositories the previous
+	// page of results ended in. pos is the position of the current page.
+	opts := p.repoOpts
+	paginator := search.PaginatorFromContext(ctx)
+	if paginator != nil && p.id != 0 {
+		opts.Cursors = paginator.PagerStart(p.id)
+	}
+	pos := opts.Cursors
+
 	repoResolver := repos.NewResolver(clients.Logger, clients.DB, clients.SearcherURLs, clients.Zoekt)
 	pager := func(page *repos.Resolved) error {
+		if paginator != nil && p.id != 0 && ctx.Err() == nil {
+			paginator.PagerAt(p.id, pos)
+		}
+		pos = page.Next
+
 		indexed, unindexed, err := zoekt.PartitionRepos(
 			ctx,
 			clients.Logger,
@@ -104,7 +127,7 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 		return err
 	}
 
-	return maxAlerter.Alert, repoResolver.Paginate(ctx, p.repoOpts, pager)
+	return maxAlerter.Alert, repoResolver.Paginate(ctx, opts, pager)
 }
 
 func (p *repoPagerJob) Name() string {
@@ -135,3 +158,15 @@ func (p *repoPagerJob) MapChildren(fn job.MapFunc) job.Job {
 	cp.child = p.child.MapChildren(fn)
 	return &cp
 }
+
+// assignPagerIDs numbers the repository pagers of j, so that paginated
+// searches of the same query can resume them.
+func assignPagerIDs(j job.Job) job.Job {
+	nextID := 1
+	return job.MapType(j, func(p *repoPagerJob) job.Job {
+		cp := *p
+		cp.id = nextID
+		nextID++
+		return &cp
+	})
+}
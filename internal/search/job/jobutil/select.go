@@ -2,6 +2,8 @@ package jobutil
 
 import (
 	"context"
+	"regexp"
+	"sort"
 	"sync"
 
 	"github.com/opentracing/opentracing-go/log"
@@ -12,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 // NewSelectJob creates a job that transforms streamed results with
@@ -96,3 +99,137 @@ func newSelectingStream(parent streaming.Sender, s filter.SelectPath) streaming.
 		parent.Send(e)
 	})
 }
+
+// NewCaptureGroupSelectJob creates a job that selects the distinct values of
+// the capture group group of pattern in the content matches of its child, with
+// the number of times each value matched per repository
+// (select:content.group(...)).
+//
+// Counts are only known once the child is done, so the selected values are
+// streamed when the child returns. The child stops at the file match limit of
+// the query, so the counts only cover the files found up to that limit. The
+// selected values are sent with IsLimitHit in that case, so that clients know
+// the counts are partial.
+func NewCaptureGroupSelectJob(path filter.SelectPath, pattern string, caseSensitive bool, child job.Job) (job.Job, error) {
+	group, _ := path.CaptureGroup()
+	if !caseSensitive {
+		pattern = "(?i:" + pattern + ")"
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	index, err := filter.CaptureGroupIndex(re.SubexpNames(), group)
+	if err != nil {
+		return nil, err
+	}
+	return &captureGroupSelectJob{path: path, pattern: re, index: index, child: child}, nil
+}
+
+type captureGroupSelectJob struct {
+	path    filter.SelectPath
+	pattern *regexp.Regexp
+	index   int
+	child   job.Job
+}
+
+type captureGroupValue struct {
+	repo  types.MinimalRepo
+	value string
+}
+
+func (j *captureGroupSelectJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu       sync.Mutex
+		counts   = map[captureGroupValue]int{}
+		limitHit bool
+	)
+	alert, err = j.child.Run(ctx, clients, streaming.StreamFunc(func(e streaming.SearchEvent) {
+		mu.Lock()
+		limitHit = limitHit || e.Stats.IsLimitHit
+		for _, match := range e.Results {
+			if fm, ok := match.(*result.FileMatch); ok {
+				for _, value := range j.values(fm) {
+					counts[captureGroupValue{repo: fm.Repo, value: value}]++
+				}
+			}
+		}
+		mu.Unlock()
+
+		// Forward the stats of the event, such as skipped repositories.
+		e.Results = nil
+		stream.Send(e)
+	}))
+
+	// Send what we have, even if the search failed or hit a limit.
+	mu.Lock()
+	defer mu.Unlock()
+	matches := make(result.Matches, 0, len(counts))
+	for v, count := range counts {
+		matches = append(matches, &result.CaptureGroupMatch{Value: v.value, Count: count, Repo: v.repo})
+	}
+	sort.Slice(matches, func(i, k int) bool {
+		a, b := matches[i].(*result.CaptureGroupMatch), matches[k].(*result.CaptureGroupMatch)
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Key().Less(b.Key())
+	})
+	if len(matches) > 0 {
+		stream.Send(streaming.SearchEvent{
+			Results: matches,
+			Stats:   streaming.Stats{IsLimitHit: limitHit},
+		})
+	}
+	return alert, err
+}
+
+// values returns the values of the capture group in each matched range of fm.
+func (j *captureGroupSelectJob) values(fm *result.FileMatch) []string {
+	var values []string
+	for _, cm := range fm.ChunkMatches {
+		for _, r := range cm.Ranges {
+			rr := r.Sub(cm.ContentStart)
+			if rr.Start.Offset < 0 || rr.End.Offset > len(cm.Content) || rr.Start.Offset > rr.End.Offset {
+				continue
+			}
+			text := cm.Content[rr.Start.Offset:rr.End.Offset]
+			submatches := j.pattern.FindStringSubmatchIndex(text)
+			if submatches == nil || submatches[2*j.index] < 0 {
+				continue
+			}
+			values = append(values, text[submatches[2*j.index]:submatches[2*j.index+1]])
+		}
+	}
+	return values
+}
+
+func (j *captureGroupSelectJob) Name() string {
+	return "CaptureGroupSelectJob"
+}
+
+func (j *captureGroupSelectJob) Fields(v job.Verbosity) (res []log.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			trace.Printf("select", "%q", j.path),
+			log.String("pattern", j.pattern.String()),
+		)
+	}
+	return res
+}
+
+func (j *captureGroupSelectJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *captureGroupSelectJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
@@ -1,14 +1,22 @@
 package jobutil
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/hexops/autogold"
+	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func TestWithSelect(t *testing.T) {
@@ -221,3 +229,82 @@ func TestWithSelect(t *testing.T) {
   }
 ]`).Equal(t, test("content"))
 }
+
+func TestCaptureGroupSelectJob(t *testing.T) {
+	// fm returns a file match with a chunk match for each line of lines,
+	// where the whole line is matched.
+	fm := func(repo string, lines ...string) *result.FileMatch {
+		var cms result.ChunkMatches
+		for i, line := range lines {
+			cms = append(cms, result.ChunkMatch{
+				Content:      line,
+				ContentStart: result.Location{Line: i},
+				Ranges: result.Ranges{{
+					Start: result.Location{Line: i},
+					End:   result.Location{Offset: len(line), Line: i, Column: len(line)},
+				}},
+			})
+		}
+		return &result.FileMatch{
+			File:         result.File{Repo: types.MinimalRepo{Name: api.RepoName(repo)}},
+			ChunkMatches: cms,
+		}
+	}
+
+	mockJob := mockjob.NewMockJob()
+	mockJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			fm("a", `"lodash": "4.17.21"`, `"react": "18.2.0"`),
+			fm("b", `"Lodash": "4.17.21"`),
+		}})
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			fm("a", `"lodash": "4.17.20"`, `"lodash": "4.17.21"`),
+		}})
+		return nil, nil
+	})
+
+	test := func(selector string, caseSensitive bool) []string {
+		sp, err := filter.SelectPathFromString(selector)
+		require.NoError(t, err)
+		j, err := NewCaptureGroupSelectJob(sp, `"lodash": "(?P<version>[\d.]+)"`, caseSensitive, mockJob)
+		require.NoError(t, err)
+
+		s := streaming.NewAggregatingStream()
+		_, err = j.Run(context.Background(), job.RuntimeClients{}, s)
+		require.NoError(t, err)
+
+		var got []string
+		for _, m := range s.Results {
+			cm := m.(*result.CaptureGroupMatch)
+			got = append(got, fmt.Sprintf("%s %s %d", cm.Repo.Name, cm.Value, cm.Count))
+		}
+		return got
+	}
+
+	autogold.Want("select by number", []string{"a 4.17.21 2", "a 4.17.20 1", "b 4.17.21 1"}).Equal(t, test("content.group(1)", false))
+	autogold.Want("select by name", []string{"a 4.17.21 2", "a 4.17.20 1"}).Equal(t, test("content.group(version)", true))
+
+	_, err := NewCaptureGroupSelectJob(filter.SelectPath{filter.Content, "group(2)"}, `"lodash": "([\d.]+)"`, false, mockJob)
+	require.Error(t, err)
+
+	t.Run("limit hit", func(t *testing.T) {
+		limitedJob := mockjob.NewMockJob()
+		limitedJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{Results: result.Matches{fm("a", `"lodash": "4.17.21"`)}})
+			s.Send(streaming.SearchEvent{Stats: streaming.Stats{IsLimitHit: true}})
+			return nil, nil
+		})
+		sp, err := filter.SelectPathFromString("content.group(1)")
+		require.NoError(t, err)
+		j, err := NewCaptureGroupSelectJob(sp, `"lodash": "([\d.]+)"`, false, limitedJob)
+		require.NoError(t, err)
+
+		var last streaming.SearchEvent
+		_, err = j.Run(context.Background(), job.RuntimeClients{}, streaming.StreamFunc(func(e streaming.SearchEvent) {
+			last = e
+		}))
+		require.NoError(t, err)
+		require.Len(t, last.Results, 1)
+		require.True(t, last.Stats.IsLimitHit)
+	})
+}
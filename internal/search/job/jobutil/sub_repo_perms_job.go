@@ -114,6 +114,26 @@ func applySubRepoFiltering(ctx context.Context, logger log.Logger, checker authz
 					filtered = append(filtered, m)
 				}
 			}
+		case *result.CommitDiffMatch:
+			allowed, err := authz.CanReadAnyPath(ctx, checker, mm.Repo.Name, []string{mm.Path()})
+			if err != nil {
+				errs = errors.Append(errs, err)
+				continue
+			}
+			if allowed {
+				filtered = append(filtered, m)
+			}
+		case *result.PersonMatch:
+			// Blame authors and code owners must not reveal anything about
+			// files the actor cannot read.
+			allowed, err := authz.CanReadAnyPath(ctx, checker, mm.Repo.Name, []string{mm.Path})
+			if err != nil {
+				errs = errors.Append(errs, err)
+				continue
+			}
+			if allowed {
+				filtered = append(filtered, m)
+			}
 		case *result.RepoMatch:
 			// Repo filtering is taking care of by our usual repo filtering logic
 			filtered = append(filtered, m)
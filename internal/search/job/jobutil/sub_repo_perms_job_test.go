@@ -194,6 +194,28 @@ func TestApplySubRepoFiltering(t *testing.T) {
 			},
 			wantMatches: []result.Match{},
 		},
+		{
+			name: "should filter people selected from files the user doesn't have access to",
+			args: args{
+				ctxActor: actor.FromUser(userWithSubRepoPerms),
+				matches: []result.Match{
+					&result.PersonMatch{
+						Handle: "@alice",
+						Path:   unauthorizedFileName,
+					},
+					&result.PersonMatch{
+						Email: "bob@example.com",
+						Path:  "another-file.txt",
+					},
+				},
+			},
+			wantMatches: []result.Match{
+				&result.PersonMatch{
+					Email: "bob@example.com",
+					Path:  "another-file.txt",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,6 +1,12 @@
 package keyword
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/go-enry/go-enry/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
 
 func getFileScore(filePath string, patterns []string) float64 {
 	filePathLowerCase := strings.ToLower(filePath)
@@ -12,3 +18,31 @@ func getFileScore(filePath string, patterns []string) float64 {
 	}
 	return float64(count) / float64(len(patterns))
 }
+
+// getSymbolScore returns the ratio of patterns contained in the name of one of
+// the given symbols.
+func getSymbolScore(symbols []string, patterns []string) float64 {
+	count := 0
+	for _, pattern := range patterns {
+		for _, symbol := range symbols {
+			if strings.Contains(strings.ToLower(symbol), pattern) {
+				count += 1
+				break
+			}
+		}
+	}
+	return float64(count) / float64(len(patterns))
+}
+
+// getFileFeatures returns the features shared by all match groups of fileMatch.
+// symbols are the names of the symbols defined in the file.
+func getFileFeatures(fileMatch *result.FileMatch, patterns []string, symbols []string, fork bool) Features {
+	return Features{
+		PathMatchRatio:   getFileScore(fileMatch.Path, patterns),
+		SymbolMatchRatio: getSymbolScore(symbols, patterns),
+		PathDepth:        strings.Count(fileMatch.Path, "/"),
+		IsTest:           enry.IsTest(fileMatch.Path),
+		RepoStars:        fileMatch.Repo.Stars,
+		RepoFork:         fork,
+	}
+}
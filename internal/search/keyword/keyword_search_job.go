@@ -3,7 +3,6 @@ package keyword
 import (
 	"context"
 	"sort"
-	"sync"
 
 	"github.com/opentracing/opentracing-go/log"
 
@@ -25,12 +24,13 @@ func NewKeywordSearchJob(b query.Basic, newJob func(query.Basic) (job.Job, error
 	if err != nil {
 		return nil, err
 	}
-	return &keywordSearchJob{child: child, patterns: keywordQuery.patterns}, nil
+	return &keywordSearchJob{child: child, patterns: keywordQuery.patterns, ranker: DefaultRanker}, nil
 }
 
 type keywordSearchJob struct {
 	child    job.Job
 	patterns []string
+	ranker   Ranker
 }
 
 func (j *keywordSearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
@@ -38,7 +38,7 @@ func (j *keywordSearchJob) Run(ctx context.Context, clients job.RuntimeClients,
 	defer func() { finish(alert, err) }()
 
 	// TODO(novoselrok): Use NewBatchingStream to batch the events before processing them.
-	keywordSearchStream := newKeywordSearchStream(stream, j.patterns)
+	keywordSearchStream := newKeywordSearchStream(ctx, clients, stream, j.patterns, j.ranker)
 	return j.child.Run(ctx, clients, keywordSearchStream)
 }
 
@@ -68,29 +68,46 @@ func (j *keywordSearchJob) MapChildren(fn job.MapFunc) job.Job {
 	return &cp
 }
 
-func newKeywordSearchStream(parent streaming.Sender, patterns []string) streaming.Sender {
-	var mux sync.Mutex
+func newKeywordSearchStream(ctx context.Context, clients job.RuntimeClients, parent streaming.Sender, patterns []string, ranker Ranker) streaming.Sender {
+	// The events of the stream are ranked independently, so they only share
+	// the ranking signals cached across events. Looking up the signals is
+	// slow, so we must not serialize the events while we do it.
+	signals := newSignalsCache()
 	return streaming.StreamFunc(func(e streaming.SearchEvent) {
-		mux.Lock()
-
 		relevantGroups := []matchGroup{}
+		// fileMatches are the files with relevant groups, whose features we
+		// look up once the groups are known.
+		fileMatches := []*result.FileMatch{}
 		for _, r := range e.Results {
 			fm, isFileMatch := r.(*result.FileMatch)
 			if isFileMatch && len(fm.ChunkMatches) > 0 {
-				fileScore := getFileScore(fm.Path, patterns)
-				groups := groupChunkMatches(fm, fileScore, fm.ChunkMatches, float64(len(patterns)))
+				groups := groupChunkMatches(fm, Features{}, fm.ChunkMatches, float64(len(patterns)))
 
+				hasRelevantGroup := false
 				for _, group := range groups {
 					if group.IsRelevant() {
 						relevantGroups = append(relevantGroups, group)
+						hasRelevantGroup = true
 					}
 				}
+				if hasRelevantGroup {
+					fileMatches = append(fileMatches, fm)
+				}
 			}
 		}
 
-		sort.Slice(relevantGroups, func(i, j int) bool {
-			return relevantGroups[i].Score() > relevantGroups[j].Score()
-		})
+		symbols := signals.definedSymbols(ctx, clients.Logger, fileMatches, patterns)
+		forks := signals.repoForks(ctx, clients.Logger, clients.DB, fileMatches)
+		fileFeatures := make(map[*result.FileMatch]Features, len(fileMatches))
+		for _, fm := range fileMatches {
+			fileSymbols := symbols[repoCommit{fm.Repo.ID, fm.CommitID}][fm.Path]
+			fileFeatures[fm] = getFileFeatures(fm, patterns, fileSymbols, forks[fm.Repo.ID])
+		}
+		for i := range relevantGroups {
+			relevantGroups[i].fileFeatures = fileFeatures[relevantGroups[i].fileMatch]
+		}
+
+		rankGroups(ranker, relevantGroups)
 
 		selected := e.Results[:0]
 		// Flatten valid groups into a result stream (one match group per file).
@@ -103,7 +120,27 @@ func newKeywordSearchStream(parent streaming.Sender, patterns []string) streamin
 		}
 		e.Results = selected
 
-		mux.Unlock()
 		parent.Send(e)
 	})
 }
+
+// rankGroups sorts groups by their score with ranker, best first.
+func rankGroups(ranker Ranker, groups []matchGroup) {
+	scores := make([]float64, len(groups))
+	for i, group := range groups {
+		scores[i] = ranker.Score(group.Features())
+	}
+	sort.Stable(groupsByScore{groups, scores})
+}
+
+type groupsByScore struct {
+	groups []matchGroup
+	scores []float64
+}
+
+func (g groupsByScore) Len() int           { return len(g.groups) }
+func (g groupsByScore) Less(i, j int) bool { return g.scores[i] > g.scores[j] }
+func (g groupsByScore) Swap(i, j int) {
+	g.groups[i], g.groups[j] = g.groups[j], g.groups[i]
+	g.scores[i], g.scores[j] = g.scores[j], g.scores[i]
+}
@@ -10,8 +10,8 @@ import (
 type matchGroup struct {
 	fileMatch *result.FileMatch
 	group     result.ChunkMatches
-	// fileScore is the pre-calculated score based on file metadata (e.g., file name).
-	fileScore float64
+	// fileFeatures are the pre-calculated features of the file and repository of the group (e.g., file name).
+	fileFeatures Features
 	// distinctMatchesRatio is the ratio between the number of distinct pattern matches in the group and
 	// the number of patterns in the query. It is the most basic measure of group relevancy. A relevant match group
 	// should contain a sufficent ratio of matches as defined by `DISTINCT_MATCHES_RATIO_THRESHOLD`.
@@ -33,11 +33,16 @@ func (g matchGroup) IsRelevant() bool {
 	return g.distinctMatchesRatio >= DISTINCT_MATCHES_RATIO_THRESHOLD && g.distinctMatchesPerLineRatio >= DISTINCT_MATCHES_PER_LINE_THRESHOLD
 }
 
-func (g matchGroup) Score() float64 {
-	return g.distinctMatchesRatio + g.distinctMatchesPerLineRatio + g.keywordsPerLineRatio + g.fileScore
+// Features returns the features a Ranker scores the group with.
+func (g matchGroup) Features() Features {
+	f := g.fileFeatures
+	f.DistinctMatchesRatio = g.distinctMatchesRatio
+	f.DistinctMatchesPerLineRatio = g.distinctMatchesPerLineRatio
+	f.KeywordsPerLineRatio = g.keywordsPerLineRatio
+	return f
 }
 
-func newChunkMatchGroup(fileMatch *result.FileMatch, fileScore float64, group result.ChunkMatches, numPatterns float64) matchGroup {
+func newChunkMatchGroup(fileMatch *result.FileMatch, fileFeatures Features, group result.ChunkMatches, numPatterns float64) matchGroup {
 	distinctMatches := stringSet{}
 	distinctMatchesPerLineCount := 0
 	keywordCount := 0
@@ -63,10 +68,10 @@ func newChunkMatchGroup(fileMatch *result.FileMatch, fileScore float64, group re
 	distinctMatchesPerLineRatio := float64(distinctMatchesPerLineCount) / (lineCount * numPatterns)
 	keywordsPerLine := float64(keywordCount) / lineCount
 
-	return matchGroup{fileMatch, group, fileScore, distinctMatchesRatio, distinctMatchesPerLineRatio, keywordsPerLine}
+	return matchGroup{fileMatch, group, fileFeatures, distinctMatchesRatio, distinctMatchesPerLineRatio, keywordsPerLine}
 }
 
-func groupChunkMatches(fileMatch *result.FileMatch, fileScore float64, chunkMatches result.ChunkMatches, numPatterns float64) []matchGroup {
+func groupChunkMatches(fileMatch *result.FileMatch, fileFeatures Features, chunkMatches result.ChunkMatches, numPatterns float64) []matchGroup {
 	// Sort chunks by line number
 	sort.Slice(chunkMatches, func(i, j int) bool {
 		return chunkMatches[i].ContentStart.Line < chunkMatches[j].ContentStart.Line
@@ -77,10 +82,10 @@ func groupChunkMatches(fileMatch *result.FileMatch, fileScore float64, chunkMatc
 	startIndex := 0
 	for i := 0; i < len(chunkMatches)-1; i++ {
 		if chunkMatches[i+1].ContentStart.Line-chunkMatches[i].ContentStart.Line > 2 {
-			groups = append(groups, newChunkMatchGroup(fileMatch, fileScore, chunkMatches[startIndex:i+1], numPatterns))
+			groups = append(groups, newChunkMatchGroup(fileMatch, fileFeatures, chunkMatches[startIndex:i+1], numPatterns))
 			startIndex = i + 1
 		}
 	}
-	groups = append(groups, newChunkMatchGroup(fileMatch, fileScore, chunkMatches[startIndex:], numPatterns))
+	groups = append(groups, newChunkMatchGroup(fileMatch, fileFeatures, chunkMatches[startIndex:], numPatterns))
 	return groups
 }
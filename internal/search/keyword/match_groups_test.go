@@ -67,7 +67,7 @@ func small_abc__abc_() {
 `
 	chunkMatches := annotatedMatchesToChunkMatches(annotatedMatches, "_")
 	fileMatch := result.FileMatch{File: result.File{}, Symbols: []*result.SymbolMatch{}, LimitHit: false, ChunkMatches: chunkMatches}
-	groups := groupChunkMatches(&fileMatch, 0.0, chunkMatches, 3)
+	groups := groupChunkMatches(&fileMatch, Features{}, chunkMatches, 3)
 
 	groupRelevancy := []bool{true, false, true}
 	if len(groups) != len(groupRelevancy) {
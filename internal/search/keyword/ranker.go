@@ -0,0 +1,112 @@
+package keyword
+
+import "math"
+
+// Features are the signals a Ranker scores a match group with.
+type Features struct {
+	// DistinctMatchesRatio, DistinctMatchesPerLineRatio and KeywordsPerLineRatio
+	// describe the matches of the group. See matchGroup for their definitions.
+	DistinctMatchesRatio        float64
+	DistinctMatchesPerLineRatio float64
+	KeywordsPerLineRatio        float64
+
+	// PathMatchRatio is the ratio of query patterns contained in the path of
+	// the file.
+	PathMatchRatio float64
+	// SymbolMatchRatio is the ratio of query patterns contained in the name of
+	// a symbol defined in the file.
+	SymbolMatchRatio float64
+	// PathDepth is the number of directories the file is nested in.
+	PathDepth int
+	// IsTest is true if the file contains tests.
+	IsTest bool
+
+	// RepoStars is the number of stars of the repository of the file.
+	RepoStars int
+	// RepoFork is true if the repository of the file is a fork.
+	RepoFork bool
+}
+
+// Ranker scores the match groups of a keyword search. Groups with higher
+// scores are returned first.
+//
+// Rankers only see the Features of a group, so that they can be compared
+// offline against a labeled corpus (see testdata/ranking_corpus.json).
+type Ranker interface {
+	// Name identifies the ranker in evaluations.
+	Name() string
+	Score(Features) float64
+}
+
+// DefaultRanker is the ranker of keyword searches.
+var DefaultRanker Ranker = SignalRanker{Weights: DefaultSignalWeights}
+
+// BaselineRanker scores groups by their matches and the matches in the path
+// of their file only.
+type BaselineRanker struct{}
+
+func (BaselineRanker) Name() string { return "baseline" }
+
+func (BaselineRanker) Score(f Features) float64 {
+	return f.DistinctMatchesRatio + f.DistinctMatchesPerLineRatio + f.KeywordsPerLineRatio + f.PathMatchRatio
+}
+
+// SignalWeights are the weights of the signals SignalRanker adds to the score
+// of BaselineRanker.
+type SignalWeights struct {
+	// SymbolMatch is added per SymbolMatchRatio.
+	SymbolMatch float64
+	// PathDepth is subtracted per directory the file is nested in, up to
+	// maxPathDepth directories.
+	PathDepth float64
+	// Test is subtracted for test files.
+	Test float64
+	// RepoStars is added per order of magnitude of the stars of the repository.
+	RepoStars float64
+	// Fork is subtracted for files of forks.
+	Fork float64
+}
+
+// DefaultSignalWeights are the weights of DefaultRanker. They were tuned
+// against testdata/ranking_corpus.json.
+var DefaultSignalWeights = SignalWeights{
+	SymbolMatch: 1,
+	PathDepth:   0.05,
+	Test:        1,
+	RepoStars:   0.1,
+	Fork:        0.5,
+}
+
+// maxPathDepth caps the penalty of deeply nested files, so that it does not
+// outweigh their matches.
+const maxPathDepth = 10
+
+// SignalRanker extends BaselineRanker with signals of the file and
+// repository of a group: symbol definitions, path depth, tests, stars and
+// forks.
+type SignalRanker struct {
+	Weights SignalWeights
+}
+
+func (SignalRanker) Name() string { return "signals" }
+
+func (r SignalRanker) Score(f Features) float64 {
+	score := BaselineRanker{}.Score(f)
+	score += r.Weights.SymbolMatch * f.SymbolMatchRatio
+	score -= r.Weights.PathDepth * float64(min(f.PathDepth, maxPathDepth))
+	if f.IsTest {
+		score -= r.Weights.Test
+	}
+	score += r.Weights.RepoStars * math.Log10(float64(1+f.RepoStars))
+	if f.RepoFork {
+		score -= r.Weights.Fork
+	}
+	return score
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
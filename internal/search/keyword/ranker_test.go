@@ -0,0 +1,130 @@
+package keyword
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// rankingQuery is a query of the labeled ranking corpus in
+// testdata/ranking_corpus.json.
+type rankingQuery struct {
+	Query    string
+	Patterns []string
+	Results  []struct {
+		Repo    string
+		Stars   int
+		Fork    bool
+		Path    string
+		Symbols []string
+		// Content is a single match group annotated with "|", see
+		// annotatedMatchesToChunkMatches.
+		Content string
+		// Relevance is the label of the result, from 0 (irrelevant) to 3
+		// (the result the query is looking for).
+		Relevance int
+	}
+}
+
+// ndcg returns the normalized discounted cumulative gain of the results of q
+// ranked by ranker. Results without relevant match groups are not returned.
+func ndcg(q rankingQuery, ranker Ranker) float64 {
+	var groups []matchGroup
+	relevance := map[*result.FileMatch]int{}
+	var labels []int
+	for _, r := range q.Results {
+		chunkMatches := annotatedMatchesToChunkMatches(r.Content, "|")
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{Name: api.RepoName(r.Repo), Stars: r.Stars},
+				CommitID: "deadbeef",
+				Path:     r.Path,
+			},
+			ChunkMatches: chunkMatches,
+		}
+		relevance[fm] = r.Relevance
+		labels = append(labels, r.Relevance)
+
+		fileFeatures := getFileFeatures(fm, q.Patterns, r.Symbols, r.Fork)
+		for _, group := range groupChunkMatches(fm, fileFeatures, chunkMatches, float64(len(q.Patterns))) {
+			if group.IsRelevant() {
+				groups = append(groups, group)
+			}
+		}
+	}
+	rankGroups(ranker, groups)
+
+	dcg := func(labels []int) float64 {
+		var sum float64
+		for i, label := range labels {
+			sum += (math.Pow(2, float64(label)) - 1) / math.Log2(float64(i+2))
+		}
+		return sum
+	}
+
+	var ranked []int
+	for _, group := range groups {
+		ranked = append(ranked, relevance[group.fileMatch])
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(labels)))
+	return dcg(ranked) / dcg(labels)
+}
+
+// TestRankers compares rankers on the labeled ranking corpus. Run it with -v
+// to see the score of each ranker per query when tuning rankers.
+func TestRankers(t *testing.T) {
+	b, err := os.ReadFile("testdata/ranking_corpus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus []rankingQuery
+	if err := json.Unmarshal(b, &corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	meanNDCG := func(ranker Ranker) float64 {
+		var sum float64
+		for _, q := range corpus {
+			score := ndcg(q, ranker)
+			t.Logf("%s: %q: %.3f", ranker.Name(), q.Query, score)
+			sum += score
+		}
+		return sum / float64(len(corpus))
+	}
+
+	baseline := meanNDCG(BaselineRanker{})
+	def := meanNDCG(DefaultRanker)
+	t.Logf("mean NDCG: %s=%.3f %s=%.3f", BaselineRanker{}.Name(), baseline, DefaultRanker.Name(), def)
+
+	if def < baseline {
+		t.Fatalf("default ranker is worse than the baseline: %.3f < %.3f", def, baseline)
+	}
+	if def < 0.95 {
+		t.Fatalf("expected mean NDCG of the default ranker of at least 0.95, got %.3f", def)
+	}
+}
+
+func TestGetFileFeatures(t *testing.T) {
+	fm := &result.FileMatch{File: result.File{
+		Repo: types.MinimalRepo{Stars: 42},
+		Path: "internal/httpcli/client_test.go",
+	}}
+	got := getFileFeatures(fm, []string{"http", "client", "retry"}, []string{"TestRetryingClient"}, true)
+	want := Features{
+		PathMatchRatio:   2.0 / 3,
+		SymbolMatchRatio: 2.0 / 3,
+		PathDepth:        2,
+		IsTest:           true,
+		RepoStars:        42,
+		RepoFork:         true,
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
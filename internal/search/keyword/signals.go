@@ -0,0 +1,194 @@
+package keyword
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/log"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// symbolsTimeout bounds the time spent looking up the symbols of the files of
+// a single repository. Ranking signals are best effort, so a slow symbols
+// service must not hold up the results.
+const symbolsTimeout = time.Second
+
+// maxSymbolsPerRepo is the maximum number of symbols we look up for the files
+// of a single repository in a single event.
+const maxSymbolsPerRepo = 1000
+
+// maxConcurrentSymbolLookups is the maximum number of repositories whose
+// symbols we look up concurrently for a single event.
+const maxConcurrentSymbolLookups = 8
+
+type repoCommit struct {
+	repo   api.RepoID
+	commit api.CommitID
+}
+
+// signalsCache caches the ranking signals looked up for the results of a
+// stream. The same repositories and files show up in many events of a
+// stream, so we only look each of them up once.
+type signalsCache struct {
+	mu sync.Mutex
+	// symbols contains the symbols matching the patterns of the stream
+	// defined in each file looked up so far, keyed by repository, commit and
+	// path.
+	symbols map[repoCommit]map[string][]string
+	// forks contains whether each repository looked up so far is a fork.
+	forks map[api.RepoID]bool
+}
+
+func newSignalsCache() *signalsCache {
+	return &signalsCache{
+		symbols: map[repoCommit]map[string][]string{},
+		forks:   map[api.RepoID]bool{},
+	}
+}
+
+// definedSymbols returns the names of the symbols matching patterns which are
+// defined in the given file matches, keyed by repository, commit and path.
+// Files which are not cached yet are looked up concurrently, one lookup per
+// repository and commit. Lookups which fail are logged and skipped.
+func (c *signalsCache) definedSymbols(ctx context.Context, logger log.Logger, fileMatches []*result.FileMatch, patterns []string) map[repoCommit]map[string][]string {
+	paths := map[repoCommit][]string{}
+	repos := map[repoCommit]api.RepoName{}
+	c.mu.Lock()
+	for _, fm := range fileMatches {
+		key := repoCommit{fm.Repo.ID, fm.CommitID}
+		if _, ok := c.symbols[key][fm.Path]; ok {
+			continue
+		}
+		paths[key] = append(paths[key], fm.Path)
+		repos[key] = fm.Repo.Name
+	}
+	c.mu.Unlock()
+
+	quotedPatterns := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		quotedPatterns = append(quotedPatterns, regexp.QuoteMeta(p))
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = semaphore.NewWeighted(maxConcurrentSymbolLookups)
+	)
+	for key, repoPaths := range paths {
+		key, repoPaths := key, repoPaths
+		if err := sem.Acquire(ctx, 1); err != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sem.Release(1)
+
+			quotedPaths := make([]string, 0, len(repoPaths))
+			for _, p := range repoPaths {
+				quotedPaths = append(quotedPaths, regexp.QuoteMeta(p))
+			}
+			ctx, cancel := context.WithTimeout(ctx, symbolsTimeout)
+			defer cancel()
+			repoSymbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
+				Repo:            repos[key],
+				CommitID:        key.commit,
+				Query:           strings.Join(quotedPatterns, "|"),
+				IsRegExp:        true,
+				IncludePatterns: []string{"^(?:" + strings.Join(quotedPaths, "|") + ")$"},
+				First:           maxSymbolsPerRepo,
+				Timeout:         int(symbolsTimeout.Seconds()),
+			})
+			if err != nil {
+				// We don't cache failed lookups, so that a later event can
+				// still find the symbols of these files.
+				logger.Warn("failed to look up symbols for ranking", log.String("repo", string(repos[key])), log.Error(err))
+				return
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			byPath := c.symbols[key]
+			if byPath == nil {
+				byPath = map[string][]string{}
+				c.symbols[key] = byPath
+			}
+			// Files without symbols are cached with an empty list.
+			for _, p := range repoPaths {
+				if _, ok := byPath[p]; !ok {
+					byPath[p] = []string{}
+				}
+			}
+			for _, symbol := range repoSymbols {
+				byPath[symbol.Path] = append(byPath[symbol.Path], symbol.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	symbols := make(map[repoCommit]map[string][]string)
+	for _, fm := range fileMatches {
+		key := repoCommit{fm.Repo.ID, fm.CommitID}
+		if names, ok := c.symbols[key][fm.Path]; ok {
+			if symbols[key] == nil {
+				symbols[key] = map[string][]string{}
+			}
+			symbols[key][fm.Path] = names
+		}
+	}
+	return symbols
+}
+
+// repoForks returns whether the repositories of fileMatches are forks.
+// Repositories which are not cached yet are looked up. Lookups which fail are
+// logged and skipped.
+func (c *signalsCache) repoForks(ctx context.Context, logger log.Logger, db database.DB, fileMatches []*result.FileMatch) map[api.RepoID]bool {
+	var ids []api.RepoID
+	seen := map[api.RepoID]struct{}{}
+	c.mu.Lock()
+	for _, fm := range fileMatches {
+		if _, ok := c.forks[fm.Repo.ID]; ok {
+			continue
+		}
+		if _, ok := seen[fm.Repo.ID]; !ok {
+			seen[fm.Repo.ID] = struct{}{}
+			ids = append(ids, fm.Repo.ID)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(ids) > 0 && db != nil {
+		repos, err := db.Repos().GetByIDs(ctx, ids...)
+		if err != nil {
+			logger.Warn("failed to look up forks for ranking", log.Error(err))
+		} else {
+			c.mu.Lock()
+			// Repositories which were not found are not forks.
+			for _, id := range ids {
+				c.forks[id] = false
+			}
+			for _, repo := range repos {
+				c.forks[repo.ID] = repo.Fork
+			}
+			c.mu.Unlock()
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	forks := make(map[api.RepoID]bool, len(fileMatches))
+	for _, fm := range fileMatches {
+		forks[fm.Repo.ID] = c.forks[fm.Repo.ID]
+	}
+	return forks
+}
@@ -11,6 +11,15 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
+// ParseRevAtTime returns the date of a revision of the form at.time(date), and
+// whether the revision has that form.
+func ParseRevAtTime(rev string) (string, bool) {
+	if !strings.HasPrefix(rev, "at.time(") || !strings.HasSuffix(rev, ")") {
+		return "", false
+	}
+	return strings.TrimSpace(rev[len("at.time(") : len(rev)-1]), true
+}
+
 // ParseGitDate implements date parsing for before/after arguments.
 // The intent is to replicate the behavior of git CLI's date parsing as documented here:
 // https://github.com/git/git/blob/master/Documentation/date-formats.txt
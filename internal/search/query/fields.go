@@ -27,12 +27,18 @@ const (
 	FieldCommitter = "committer"
 	FieldMessage   = "message"
 
+	// For content search only, filters matched lines by `git blame`:
+	FieldBlameAuthor = "blame.author"
+	FieldBlameBefore = "blame.before"
+
 	// Temporary experimental fields:
 	FieldIndex     = "index"
 	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
 	FieldSelect    = "select"
+	FieldEngine    = "engine" // Selects the matcher of structural searches, see StructuralEngineComby
+	FieldFuzzy     = "fuzzy"  // Fuzzily matches the pattern against file paths, see zoekt.FuzzyPathSearchJob
 )
 
 var allFields = map[string]struct{}{
@@ -64,6 +70,8 @@ var allFields = map[string]struct{}{
 	FieldMessage:            empty,
 	"m":                     empty,
 	"msg":                   empty,
+	FieldBlameAuthor:        empty,
+	FieldBlameBefore:        empty,
 	FieldIndex:              empty,
 	FieldCount:              empty,
 	FieldTimeout:            empty,
@@ -71,6 +79,8 @@ var allFields = map[string]struct{}{
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
+	FieldEngine:             empty,
+	FieldFuzzy:              empty,
 }
 
 var aliases = map[string]string{
@@ -289,6 +289,10 @@ func TestScanField(t *testing.T) {
 	autogold.Want("-repo", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("-repo"))
 	autogold.Want("--repo:", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("--repo:"))
 	autogold.Want(":foo", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test(":foo"))
+	autogold.Want("blame.author:", `{"Field":"blame.author","Negated":false,"Advance":13}`).Equal(t, test("blame.author:"))
+	autogold.Want("-blame.author:", `{"Field":"blame.author","Negated":true,"Advance":14}`).Equal(t, test("-blame.author:"))
+	autogold.Want("blame..author:", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("blame..author:"))
+	autogold.Want("foo.bar:", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("foo.bar:"))
 }
 
 func parseAndOrGrammar(in string) ([]Node, error) {
@@ -518,6 +518,11 @@ func (p Parameters) IsCaseSensitive() bool {
 	return p.boolValue(FieldCase)
 }
 
+// IsFuzzy returns whether the pattern fuzzily matches file paths (fuzzy:yes).
+func (p Parameters) IsFuzzy() bool {
+	return p.boolValue(FieldFuzzy)
+}
+
 func (p Parameters) yesNoOnlyValue(field string) *YesNoOnly {
 	var res *YesNoOnly
 	VisitField(toNodes(p), field, func(value string, _ bool, _ Annotation) {
@@ -191,6 +191,17 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return nil
 	}
 
+	isValidRev := func() error {
+		date, ok := ParseRevAtTime(value)
+		if !ok {
+			return nil
+		}
+		if _, err := ParseGitDate(date, time.Now); err != nil {
+			return errors.Errorf(`invalid value for rev:at.time(): %q is not a valid date (examples: "rev:at.time(2022-01-01)", "rev:at.time(1 year ago)")`, date)
+		}
+		return nil
+	}
+
 	isLanguage := func() error {
 		_, ok := enry.GetLanguageByAlias(value)
 		if !ok {
@@ -207,6 +218,14 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return nil
 	}
 
+	isStructuralEngine := func() error {
+		switch value {
+		case StructuralEngineComby, StructuralEngineTreeSitter:
+			return nil
+		}
+		return errors.Errorf("invalid value %q for field %q. Valid values are: %s, %s", value, field, StructuralEngineComby, StructuralEngineTreeSitter)
+	}
+
 	isUnrecognizedField := func() error {
 		return errors.Errorf("unrecognized field %q", field)
 	}
@@ -235,7 +254,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldDefault:
 		// Search patterns are not validated here, as it depends on the search type.
 	case
-		FieldCase:
+		FieldCase,
+		FieldFuzzy:
 		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepo:
@@ -272,6 +292,12 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldCommitter,
 		FieldMessage:
 		return satisfies(isValidRegexp)
+	case
+		FieldBlameAuthor:
+		return satisfies(isValidRegexp)
+	case
+		FieldBlameBefore:
+		return satisfies(isSingular, isNotNegated, isValidGitDate)
 	case
 		FieldIndex,
 		FieldFork,
@@ -288,10 +314,13 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return satisfies(isSingular, isNotNegated, isDuration)
 	case
 		FieldRev:
-		return satisfies(isSingular, isNotNegated)
+		return satisfies(isSingular, isNotNegated, isValidRev)
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
+	case
+		FieldEngine:
+		return satisfies(isSingular, isNotNegated, isStructuralEngine)
 	default:
 		return isUnrecognizedField()
 	}
@@ -352,6 +381,87 @@ func validateCommitParameters(nodes []Node) error {
 	return nil
 }
 
+// Queries containing blame parameters are only valid for searches over file
+// contents, since blame data is only available for matched lines.
+func validateBlameParameters(nodes []Node) error {
+	var seenBlameParam string
+	var typeNotFile string
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if field == FieldBlameAuthor || field == FieldBlameBefore {
+			seenBlameParam = field
+		}
+		if field == FieldSelect && strings.HasPrefix(value, "blame") {
+			seenBlameParam = "select:" + value
+		}
+		if field == FieldType && value != "file" {
+			typeNotFile = value
+		}
+	})
+	if seenBlameParam != "" && typeNotFile != "" {
+		return errors.Errorf(`your query contains the field '%s', which is not supported with type:%s. Blame data is only available for file contents`, seenBlameParam, typeNotFile)
+	}
+	return nil
+}
+
+// validateSelectCaptureGroup validates that select:content.group(...) selects
+// a capture group of the single regular expression pattern of the query.
+func validateSelectCaptureGroup(nodes []Node) error {
+	var group string
+	var selectsGroup bool
+	VisitField(nodes, FieldSelect, func(value string, _ bool, _ Annotation) {
+		if sp, err := filter.SelectPathFromString(value); err == nil {
+			group, selectsGroup = sp.CaptureGroup()
+		}
+	})
+	if !selectsGroup {
+		return nil
+	}
+
+	var patterns []Pattern
+	VisitPattern(nodes, func(value string, negated bool, annotation Annotation) {
+		patterns = append(patterns, Pattern{Value: value, Negated: negated, Annotation: annotation})
+	})
+	if len(patterns) != 1 || patterns[0].Negated || !patterns[0].Annotation.Labels.IsSet(Regexp) {
+		return errors.Errorf("select:content.group(%s) requires a single regular expression pattern with capture groups, like /version: (\\S+)/", group)
+	}
+	re, err := regexp.Compile(patterns[0].Value)
+	if err != nil {
+		return err
+	}
+	_, err = filter.CaptureGroupIndex(re.SubexpNames(), group)
+	return err
+}
+
+// validateFuzzy validates that a fuzzy:yes query searches file paths for a
+// single literal pattern.
+func validateFuzzy(nodes []Node) error {
+	var fuzzy bool
+	var typeNotPath string
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if field == FieldFuzzy {
+			fuzzy, _ = parseBool(value)
+		}
+		if field == FieldType && value != "path" {
+			typeNotPath = value
+		}
+	})
+	if !fuzzy {
+		return nil
+	}
+	if typeNotPath != "" {
+		return errors.Errorf("fuzzy:yes is not supported with type:%s. Fuzzy search only matches file paths", typeNotPath)
+	}
+
+	var patterns []Pattern
+	VisitPattern(nodes, func(value string, negated bool, annotation Annotation) {
+		patterns = append(patterns, Pattern{Value: value, Negated: negated, Annotation: annotation})
+	})
+	if len(patterns) != 1 || patterns[0].Negated || patterns[0].Value == "" || patterns[0].Annotation.Labels.IsSet(Regexp|Structural) {
+		return errors.New("fuzzy:yes requires a single literal pattern, like fuzzy:yes srchjob")
+	}
+	return nil
+}
+
 func validateTypeStructural(nodes []Node) error {
 	seenStructural := false
 	seenType := false
@@ -376,6 +486,25 @@ func validateTypeStructural(nodes []Node) error {
 	return nil
 }
 
+// validateStructuralEngine validates that the options of a structural search
+// are supported by the engine it selects with engine:.
+func validateStructuralEngine(nodes []Node) error {
+	var engine string
+	VisitField(nodes, FieldEngine, func(value string, _ bool, _ Annotation) {
+		engine = value
+	})
+	if engine != StructuralEngineTreeSitter {
+		return nil
+	}
+	if Exists(nodes, func(node Node) bool {
+		p, ok := node.(Parameter)
+		return ok && p.Field == FieldCombyRule
+	}) {
+		return errors.Errorf("rule: is not supported by engine:%s. Use engine:%s to search with rules", StructuralEngineTreeSitter, StructuralEngineComby)
+	}
+	return nil
+}
+
 func validateRefGlobs(nodes []Node) error {
 	if !ContainsRefGlobs(nodes) {
 		return nil
@@ -390,6 +519,47 @@ func validateRefGlobs(nodes []Node) error {
 	return nil
 }
 
+// validateRevRanges validates that revision ranges are not combined with other
+// revisions of a repository in diff searches, such as in
+// repo:foo@main:v1...v2 type:diff. Such searches diff each range, which has no
+// meaning for a single revision. Commit searches accept ranges like git log, so
+// they may mix ranges and revisions.
+func validateRevRanges(nodes []Node) error {
+	diffSearch := false
+	VisitField(nodes, FieldType, func(value string, negated bool, _ Annotation) {
+		if strings.EqualFold(value, "diff") && !negated {
+			diffSearch = true
+		}
+	})
+	if !diffSearch {
+		return nil
+	}
+
+	var err error
+	validate := func(revs string) {
+		var ranges, others int
+		for _, rev := range strings.Split(revs, ":") {
+			if strings.Contains(rev, "..") {
+				ranges++
+			} else {
+				others++
+			}
+		}
+		if ranges > 0 && others > 0 && err == nil {
+			err = errors.Errorf("invalid revisions %q. Revision ranges such as v1...v2 cannot be combined with other revisions in diff searches, search them in separate queries", revs)
+		}
+	}
+	VisitField(nodes, FieldRepo, func(value string, negated bool, _ Annotation) {
+		if _, revs, ok := strings.Cut(value, "@"); ok && !negated {
+			validate(revs)
+		}
+	})
+	VisitField(nodes, FieldRev, func(value string, _ bool, _ Annotation) {
+		validate(value)
+	})
+	return err
+}
+
 // validatePredicates validates predicate parameters with respect to their validation logic.
 func validatePredicate(field, value string, negated bool) error {
 	name, params := ParseAsPredicate(value)                // guaranteed to succeed
@@ -492,8 +662,13 @@ func validate(nodes []Node) error {
 		validateRepoRevPair,
 		validateRepoHasFile,
 		validateCommitParameters,
+		validateBlameParameters,
+		validateSelectCaptureGroup,
+		validateFuzzy,
 		validateTypeStructural,
+		validateStructuralEngine,
 		validateRefGlobs,
+		validateRevRanges,
 	)
 }
 
@@ -506,6 +681,13 @@ const (
 	Invalid YesNoOnly = "invalid"
 )
 
+// The engines structural search patterns can be matched with, selected with
+// engine:. Comby is the default.
+const (
+	StructuralEngineComby      = "comby"
+	StructuralEngineTreeSitter = "tree-sitter"
+)
+
 func parseYesNoOnly(s string) YesNoOnly {
 	switch s {
 	case "y", "Y", "yes", "YES", "Yes":
@@ -542,3 +724,19 @@ func ContainsRefGlobs(q Q) bool {
 	}
 	return containsRefGlobs
 }
+
+// ContainsRevRanges returns true if a repository of q is searched at a
+// revision range, such as repo:foo@v1..v2 or rev:v1...v2.
+func ContainsRevRanges(q Q) bool {
+	repoFilterValues, _ := q.Repositories()
+	for _, v := range repoFilterValues {
+		repoRev := strings.SplitN(v, "@", 2)
+		if len(repoRev) == 1 { // no revision
+			continue
+		}
+		if strings.Contains(repoRev[1], "..") {
+			return true
+		}
+	}
+	return false
+}
@@ -79,10 +79,65 @@ func TestValidation(t *testing.T) {
 			input: `repo:'' rev:bedge`,
 			want:  "invalid syntax. The query contains `rev:` without `repo:`. Add a `repo:` filter and try again",
 		},
+		{
+			input: "repo:foo rev:at.time(bananas)",
+			want:  `invalid value for rev:at.time(): "bananas" is not a valid date (examples: "rev:at.time(2022-01-01)", "rev:at.time(1 year ago)")`,
+		},
 		{
 			input: "repo:foo author:rob@saucegraph.com",
 			want:  `your query contains the field 'author', which requires type:commit or type:diff in the query`,
 		},
+		{
+			input: "foo blame.before:yesterday blame.before:today",
+			want:  `field "blame.before" may not be used more than once`,
+		},
+		{
+			input: "foo -blame.before:yesterday",
+			want:  `field "blame.before" does not support negation`,
+		},
+		{
+			input: "foo blame.author:alice type:commit",
+			want:  `your query contains the field 'blame.author', which is not supported with type:commit. Blame data is only available for file contents`,
+		},
+		{
+			input: "foo select:blame.author type:symbol",
+			want:  `your query contains the field 'select:blame.author', which is not supported with type:symbol. Blame data is only available for file contents`,
+		},
+		{
+			input: "foo select:content.group(1)",
+			want:  "the pattern has no capture group 1, it has 0 capture groups",
+		},
+		{
+			input: `v(\d+) select:content.group(version)`,
+			want:  `the pattern has no capture group named "version"`,
+		},
+		{
+			input:      "v1 select:content.group(1)",
+			want:       `select:content.group(1) requires a single regular expression pattern with capture groups, like /version: (\S+)/`,
+			searchType: SearchTypeLiteral,
+		},
+		{
+			input: "select:content.group",
+			want:  `field "group" on select path "content.group" requires a capture group, like content.group(1)`,
+		},
+		{
+			input:      "fuzzy:yes type:file srchjob",
+			want:       "fuzzy:yes is not supported with type:file. Fuzzy search only matches file paths",
+			searchType: SearchTypeLiteral,
+		},
+		{
+			input:      "fuzzy:yes",
+			want:       "fuzzy:yes requires a single literal pattern, like fuzzy:yes srchjob",
+			searchType: SearchTypeLiteral,
+		},
+		{
+			input: "fuzzy:yes srch.*job",
+			want:  "fuzzy:yes requires a single literal pattern, like fuzzy:yes srchjob",
+		},
+		{
+			input: "fuzzy:maybe srchjob",
+			want:  `invalid boolean "maybe"`,
+		},
 		{
 			input: "repohasfile:README type:symbol yolo",
 			want:  "repohasfile is not compatible for type:symbol. Subscribe to https://github.com/sourcegraph/sourcegraph/issues/4610 for updates",
@@ -109,6 +164,24 @@ func TestValidation(t *testing.T) {
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input:      "engine:ast foo(:[args])",
+			want:       `invalid value "ast" for field "engine". Valid values are: comby, tree-sitter`,
+			searchType: SearchTypeStructural,
+		},
+		{
+			input:      `engine:tree-sitter rule:'where :[args] == "x"' foo(:[args])`,
+			want:       "rule: is not supported by engine:tree-sitter. Use engine:comby to search with rules",
+			searchType: SearchTypeStructural,
+		},
+		{
+			input: "repo:foo@main:v1...v2 type:diff bar",
+			want:  `invalid revisions "main:v1...v2". Revision ranges such as v1...v2 cannot be combined with other revisions in diff searches, search them in separate queries`,
+		},
+		{
+			input: "repo:foo rev:v1..v2:main type:diff bar",
+			want:  `invalid revisions "v1..v2:main". Revision ranges such as v1...v2 cannot be combined with other revisions in diff searches, search them in separate queries`,
+		},
 	}
 	for _, c := range cases {
 		t.Run("validate and/or query", func(t *testing.T) {
@@ -125,6 +198,25 @@ func TestValidation(t *testing.T) {
 	}
 }
 
+func TestValidateRevRanges(t *testing.T) {
+	for _, input := range []string{
+		"repo:foo@main:v1...v2 type:commit bar",
+		"repo:foo rev:v1..v2:main type:commit bar",
+		"repo:foo@v1...v2:v3..v4 type:diff bar",
+		"repo:foo@main type:diff bar",
+	} {
+		t.Run(input, func(t *testing.T) {
+			nodes, err := Parse(input, SearchTypeLiteral)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := validateRevRanges(nodes); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
 func TestIsCaseSensitive(t *testing.T) {
 	cases := []struct {
 		name  string
@@ -328,3 +420,44 @@ func TestContainsRefGlobs(t *testing.T) {
 		})
 	}
 }
+
+func TestContainsRevRanges(t *testing.T) {
+	cases := []struct {
+		input string
+		want  bool
+	}{
+		{
+			input: "repo:foo",
+			want:  false,
+		},
+		{
+			input: "repo:foo@bar",
+			want:  false,
+		},
+		{
+			input: "repo:foo@v1..v2",
+			want:  true,
+		},
+		{
+			input: "repo:foo@v1...v2:v3..v4",
+			want:  true,
+		},
+		{
+			input: "repo:foo rev:v1...v2 bar",
+			want:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			plan, err := Pipeline(InitLiteral(c.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ContainsRevRanges(plan[0].ToParseTree())
+			if got != c.want {
+				t.Errorf("got %t, expected %t", got, c.want)
+			}
+		})
+	}
+}
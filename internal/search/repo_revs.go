@@ -5,11 +5,13 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
-// RevisionSpecifier represents either a revspec or a ref glob. At most one
-// field is set. The default branch is represented by all fields being empty.
+// RevisionSpecifier represents either a revspec, a ref glob or a point in time.
+// At most one field is set. The default branch is represented by all fields
+// being empty.
 type RevisionSpecifier struct {
 	// RevSpec is a revision range specifier suitable for passing to git. See
 	// the manpage gitrevisions(7).
@@ -22,9 +24,17 @@ type RevisionSpecifier struct {
 	// ExcludeRefGlob is a glob for references to exclude. See the
 	// documentation for "--exclude" in git-log.
 	ExcludeRefGlob string
+
+	// AtTime is a date in any format accepted by query.ParseGitDate. It
+	// refers to the last commit on the default branch before that date, and
+	// is specified as at.time(date).
+	AtTime string
 }
 
 func (r1 RevisionSpecifier) String() string {
+	if r1.AtTime != "" {
+		return "at.time(" + r1.AtTime + ")"
+	}
 	if r1.ExcludeRefGlob != "" {
 		return "*!" + r1.ExcludeRefGlob
 	}
@@ -46,7 +56,10 @@ func (r1 RevisionSpecifier) Less(r2 RevisionSpecifier) bool {
 	if r1.RefGlob != r2.RefGlob {
 		return r1.RefGlob < r2.RefGlob
 	}
-	return r1.ExcludeRefGlob < r2.ExcludeRefGlob
+	if r1.ExcludeRefGlob != r2.ExcludeRefGlob {
+		return r1.ExcludeRefGlob < r2.ExcludeRefGlob
+	}
+	return r1.AtTime < r2.AtTime
 }
 
 // RepositoryRevisions specifies a repository and 0 or more revspecs and ref
@@ -78,8 +91,10 @@ func (r *RepositoryRevisions) Equal(other *RepositoryRevisions) bool {
 //
 // where repo is a repository regex and revs is a ':'-separated list of revspecs
 // and/or ref globs. A ref glob is a revspec prefixed with '*' (which is not a
-// valid revspec or ref itself; see `man git-check-ref-format`). The '@' and revs
-// may be omitted to refer to the default branch.
+// valid revspec or ref itself; see `man git-check-ref-format`). A revspec of
+// the form at.time(date) refers to the last commit on the default branch before
+// date; a ':' inside its parentheses does not separate revspecs. The '@' and
+// revs may be omitted to refer to the default branch.
 //
 // For example:
 //
@@ -90,6 +105,8 @@ func (r *RepositoryRevisions) Equal(other *RepositoryRevisions) bool {
 //   - 'foo@*bar' refers to the 'foo' repo and all refs matching the glob 'bar/*',
 //     because git interprets the ref glob 'bar' as being 'bar/*' (see `man git-log`
 //     section on the --glob flag)
+//   - 'foo@at.time(2022-01-01)' refers to the 'foo' repo at the last commit on its
+//     default branch before 2022-01-01.
 func ParseRepositoryRevisions(repoAndOptionalRev string) (string, []RevisionSpecifier) {
 	i := strings.Index(repoAndOptionalRev, "@")
 	if i == -1 {
@@ -103,7 +120,7 @@ func ParseRepositoryRevisions(repoAndOptionalRev string) (string, []RevisionSpec
 
 	repo := repoAndOptionalRev[:i]
 	var revs []RevisionSpecifier
-	for _, part := range strings.Split(repoAndOptionalRev[i+1:], ":") {
+	for _, part := range splitRevs(repoAndOptionalRev[i+1:]) {
 		if part == "" {
 			continue
 		}
@@ -115,8 +132,36 @@ func ParseRepositoryRevisions(repoAndOptionalRev string) (string, []RevisionSpec
 	return repo, revs
 }
 
+// splitRevs splits a ':'-separated list of revspecs, ignoring separators
+// inside parentheses so that times in at.time(...) may contain colons.
+func splitRevs(revs string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range revs {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ':':
+			if depth == 0 {
+				parts = append(parts, revs[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, revs[start:])
+}
+
 func parseRev(spec string) RevisionSpecifier {
-	if strings.HasPrefix(spec, "*!") {
+	if date, ok := query.ParseRevAtTime(spec); ok {
+		return RevisionSpecifier{AtTime: date}
+	} else if strings.HasPrefix(spec, "*!") {
 		return RevisionSpecifier{ExcludeRefGlob: spec[2:]}
 	} else if strings.HasPrefix(spec, "*") {
 		return RevisionSpecifier{RefGlob: spec[1:]}
@@ -16,6 +16,14 @@ func TestParseRepositoryRevisions(t *testing.T) {
 		"repo@rev1:rev2": {repo: "repo", revs: []RevisionSpecifier{{RevSpec: "rev1"}, {RevSpec: "rev2"}}},
 		"repo@:rev1:":    {repo: "repo", revs: []RevisionSpecifier{{RevSpec: "rev1"}}},
 		"repo@*glob":     {repo: "repo", revs: []RevisionSpecifier{{RefGlob: "glob"}}},
+		"repo@at.time(2022-01-01)": {
+			repo: "repo",
+			revs: []RevisionSpecifier{{AtTime: "2022-01-01"}},
+		},
+		"repo@rev1:at.time(2022-01-01T10:00:00Z):rev2": {
+			repo: "repo",
+			revs: []RevisionSpecifier{{RevSpec: "rev1"}, {AtTime: "2022-01-01T10:00:00Z"}, {RevSpec: "rev2"}},
+		},
 		"repo@rev1:*glob1:^rev2": {
 			repo: "repo",
 			revs: []RevisionSpecifier{{RevSpec: "rev1"}, {RefGlob: "glob1"}, {RevSpec: "^rev2"}},
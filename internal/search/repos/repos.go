@@ -395,6 +395,21 @@ func (r *Resolver) normalizeRepoRefs(
 			globs = append(globs, gitdomain.RefGlob{Include: rev.RefGlob})
 		case rev.ExcludeRefGlob != "":
 			globs = append(globs, gitdomain.RefGlob{Exclude: rev.ExcludeRefGlob})
+		case rev.AtTime != "":
+			commitID, err := r.resolveAtTime(ctx, repo, rev.AtTime)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) || errors.HasType(err, &badRequestError{}) {
+					return nil, err
+				}
+				reportMissing(RepoRevSpecs{Repo: repo, Revs: []search.RevisionSpecifier{rev}})
+				continue
+			}
+			if commitID == "" {
+				// The repository has no commits before the given time.
+				reportMissing(RepoRevSpecs{Repo: repo, Revs: []search.RevisionSpecifier{rev}})
+				continue
+			}
+			revs = append(revs, string(commitID))
 		case rev.RevSpec == "" || rev.RevSpec == "HEAD":
 			// NOTE: HEAD is the only case here that we don't resolve to a
 			// commit ID. We should consider building []gitdomain.Ref here
@@ -440,6 +455,26 @@ func (r *Resolver) normalizeRepoRefs(
 
 }
 
+// resolveAtTime returns the ID of the last commit on the default branch of repo
+// before the given date, or the empty string if there is none.
+func (r *Resolver) resolveAtTime(ctx context.Context, repo types.MinimalRepo, date string) (api.CommitID, error) {
+	t, err := query.ParseGitDate(date, time.Now)
+	if err != nil {
+		return "", &badRequestError{errors.Wrapf(err, "invalid date %q in at.time()", date)}
+	}
+
+	commits, err := r.gitserver.Commits(ctx, repo.Name, gitserver.CommitsOptions{
+		Range:            "HEAD",
+		Before:           t.Format(time.RFC3339),
+		N:                1,
+		NoEnsureRevision: true,
+	}, authz.DefaultSubRepoPermsChecker)
+	if err != nil || len(commits) == 0 {
+		return "", err
+	}
+	return commits[0].ID, nil
+}
+
 // filterHasCommitAfter filters the revisions on each of a set of RepositoryRevisions to ensure that
 // any repo-level filters (e.g. `repo:contains.commit.after()`) apply to this repo/rev combo.
 func (r *Resolver) filterHasCommitAfter(
@@ -975,7 +1010,7 @@ func (r *RepoRevSpecs) RevSpecs() []string {
 		case rev.RefGlob != "":
 		case rev.ExcludeRefGlob != "":
 		default:
-			res = append(res, rev.RevSpec)
+			res = append(res, rev.String())
 		}
 	}
 	return res
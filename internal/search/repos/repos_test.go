@@ -66,6 +66,13 @@ func TestRevisionValidation(t *testing.T) {
 			Name: "refs/heads/revBas",
 		}}, nil
 	})
+	mockGitserver.CommitsFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, opt gitserver.CommitsOptions, _ authz.SubRepoPermissionChecker) ([]*gitdomain.Commit, error) {
+		// The first commit was made on 2020-01-01.
+		if opt.Range != "HEAD" || opt.N != 1 || opt.Before < "2020-01-01" {
+			return nil, nil
+		}
+		return []*gitdomain.Commit{{ID: "c0ffee"}}, nil
+	})
 
 	tests := []struct {
 		repoFilters              []string
@@ -121,6 +128,25 @@ func TestRevisionValidation(t *testing.T) {
 			wantMissingRepoRevisions: nil,
 			wantErr:                  context.DeadlineExceeded,
 		},
+		{
+			repoFilters: []string{"repoFoo@at.time(2022-01-01)"},
+			wantRepoRevs: []*search.RepositoryRevisions{{
+				Repo: types.MinimalRepo{Name: "repoFoo"},
+				Revs: []string{"c0ffee"},
+			}},
+			wantMissingRepoRevisions: []RepoRevSpecs{},
+		},
+		{
+			repoFilters:  []string{"repoFoo@at.time(2019-01-01)"},
+			wantRepoRevs: []*search.RepositoryRevisions{},
+			wantMissingRepoRevisions: []RepoRevSpecs{{
+				Repo: types.MinimalRepo{Name: "repoFoo"},
+				Revs: []search.RevisionSpecifier{{
+					AtTime: "2019-01-01",
+				}},
+			}},
+			wantErr: &MissingRepoRevsError{},
+		},
 		{
 			repoFilters: []string{"repoFoo"},
 			wantRepoRevs: []*search.RepositoryRevisions{{
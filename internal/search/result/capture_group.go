@@ -0,0 +1,56 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CaptureGroupMatch is a distinct value of a capture group of the pattern in
+// the content matches of a repository (select:content.group(...)).
+type CaptureGroupMatch struct {
+	// Value is the text the capture group matched.
+	Value string
+	// Count is the number of times the capture group matched Value in the
+	// repository.
+	Count int
+
+	Repo types.MinimalRepo
+}
+
+func (cm *CaptureGroupMatch) RepoName() types.MinimalRepo {
+	return cm.Repo
+}
+
+func (cm *CaptureGroupMatch) ResultCount() int {
+	return 1
+}
+
+func (cm *CaptureGroupMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (cm *CaptureGroupMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: cm.Repo.Name,
+			ID:   cm.Repo.ID,
+		}
+	case filter.Content:
+		if _, ok := path.CaptureGroup(); ok {
+			return cm
+		}
+	}
+	return nil
+}
+
+func (cm *CaptureGroupMatch) Key() Key {
+	return Key{
+		TypeRank:     rankCaptureGroupMatch,
+		Repo:         cm.Repo.Name,
+		CaptureGroup: cm.Value,
+	}
+}
+
+func (cm *CaptureGroupMatch) searchResultMarker() {}
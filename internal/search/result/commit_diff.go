@@ -110,6 +110,18 @@ func (cm *CommitDiffMatch) Select(path filter.SelectPath) Match {
 	return nil
 }
 
+// ToCommitMatch returns a diff CommitMatch for the commit of cm whose diff
+// only contains the file of cm. It is used by consumers which present diff
+// matches like the results of a diff search.
+func (cm *CommitDiffMatch) ToCommitMatch() *CommitMatch {
+	return &CommitMatch{
+		Commit:      cm.Commit,
+		Repo:        cm.Repo,
+		DiffPreview: cm.Preview,
+		Diff:        []DiffFile{*cm.DiffFile},
+	}
+}
+
 func (cm *CommitDiffMatch) searchResultMarker() {}
 
 // FormatDiffFiles inverts ParseDiffString
@@ -79,6 +79,11 @@ func (fm *FileMatch) Select(selectPath filter.SelectPath) Match {
 			ID:   fm.Repo.ID,
 		}
 	case filter.File:
+		if len(selectPath) > 1 && selectPath[1] == "owners" {
+			// Owners are selected from file matches by the code ownership
+			// job. File matches reaching this point have no known owners.
+			return nil
+		}
 		fm.ChunkMatches = nil
 		fm.Symbols = nil
 		if len(selectPath) > 1 && selectPath[1] == "directory" {
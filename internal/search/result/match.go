@@ -8,7 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
-// Match is *FileMatch | *RepoMatch | *CommitMatch. We have a private method
+// Match is *FileMatch | *RepoMatch | *CommitMatch | *PersonMatch | *CaptureGroupMatch. We have a private method
 // to ensure only those types implement Match.
 type Match interface {
 	ResultCount() int
@@ -34,16 +34,20 @@ var (
 	_ Match = (*RepoMatch)(nil)
 	_ Match = (*CommitMatch)(nil)
 	_ Match = (*CommitDiffMatch)(nil)
+	_ Match = (*PersonMatch)(nil)
+	_ Match = (*CaptureGroupMatch)(nil)
 )
 
 // Match ranks are used for sorting the different match types.
 // Match types with lower ranks will be sorted before match types
 // with higher ranks.
 const (
-	rankFileMatch   = 0
-	rankCommitMatch = 1
-	rankDiffMatch   = 2
-	rankRepoMatch   = 3
+	rankFileMatch         = 0
+	rankCommitMatch       = 1
+	rankDiffMatch         = 2
+	rankRepoMatch         = 3
+	rankPersonMatch       = 4
+	rankCaptureGroupMatch = 5
 )
 
 // Key is a sorting or deduplicating key for a Match. It contains all the
@@ -74,6 +78,14 @@ type Key struct {
 	// Empty if there is no file associated with the match (e.g. RepoMatch or CommitMatch)
 	Path string
 
+	// Person identifies the person a PersonMatch represents.
+	// Empty for all other match types.
+	Person string
+
+	// CaptureGroup is the value of a CaptureGroupMatch.
+	// Empty for all other match types.
+	CaptureGroup string
+
 	// TypeRank is the sorting rank of the type this key belongs to.
 	TypeRank int
 }
@@ -100,6 +112,14 @@ func (k Key) Less(other Key) bool {
 		return k.Path < other.Path
 	}
 
+	if k.Person != other.Person {
+		return k.Person < other.Person
+	}
+
+	if k.CaptureGroup != other.CaptureGroup {
+		return k.CaptureGroup < other.CaptureGroup
+	}
+
 	return k.TypeRank < other.TypeRank
 }
 
@@ -149,6 +149,67 @@ func TestSelect(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("PersonMatch", func(t *testing.T) {
+		testPersonMatch := PersonMatch{
+			Name:  "Alice",
+			Email: "alice@example.com",
+			Repo:  types.MinimalRepo{Name: "testrepo"},
+		}
+
+		cases := []struct {
+			selectPath filter.SelectPath
+			output     Match
+		}{{
+			selectPath: []string{filter.Blame, "author"},
+			output:     &testPersonMatch,
+		}, {
+			selectPath: []string{filter.Repository},
+			output:     &RepoMatch{Name: "testrepo"},
+		}, {
+			selectPath: []string{filter.File},
+			output:     nil,
+		}, {
+			selectPath: []string{filter.Content},
+			output:     nil,
+		}}
+
+		for _, tc := range cases {
+			t.Run(tc.selectPath.String(), func(t *testing.T) {
+				result := testPersonMatch.Select(tc.selectPath)
+				require.Equal(t, tc.output, result)
+			})
+		}
+	})
+
+	t.Run("CaptureGroupMatch", func(t *testing.T) {
+		testCaptureGroupMatch := CaptureGroupMatch{
+			Value: "v1.2.3",
+			Count: 2,
+			Repo:  types.MinimalRepo{Name: "testrepo"},
+		}
+
+		cases := []struct {
+			selectPath filter.SelectPath
+			output     Match
+		}{{
+			selectPath: []string{filter.Content, "group(1)"},
+			output:     &testCaptureGroupMatch,
+		}, {
+			selectPath: []string{filter.Repository},
+			output:     &RepoMatch{Name: "testrepo"},
+		}, {
+			selectPath: []string{filter.Content},
+			output:     nil,
+		}}
+
+		for _, tc := range cases {
+			t.Run(tc.selectPath.String(), func(t *testing.T) {
+				result := testCaptureGroupMatch.Select(tc.selectPath)
+				require.Equal(t, tc.output, result)
+			})
+		}
+	})
 }
 
 func TestKeyEquality(t *testing.T) {
@@ -172,6 +233,22 @@ func TestKeyEquality(t *testing.T) {
 		match1:   &CommitMatch{Commit: gitdomain.Commit{ID: "test1"}},
 		match2:   &CommitMatch{Commit: gitdomain.Commit{ID: "test2"}},
 		areEqual: false,
+	}, {
+		match1:   &PersonMatch{Name: "Alice", Email: "Alice@example.com", Repo: types.MinimalRepo{Name: "repo"}},
+		match2:   &PersonMatch{Name: "alice", Email: "alice@example.com", Repo: types.MinimalRepo{Name: "repo"}},
+		areEqual: true,
+	}, {
+		match1:   &PersonMatch{Email: "alice@example.com", Repo: types.MinimalRepo{Name: "repo1"}},
+		match2:   &PersonMatch{Email: "alice@example.com", Repo: types.MinimalRepo{Name: "repo2"}},
+		areEqual: false,
+	}, {
+		match1:   &CaptureGroupMatch{Value: "v1", Count: 1, Repo: types.MinimalRepo{Name: "repo"}},
+		match2:   &CaptureGroupMatch{Value: "v1", Count: 2, Repo: types.MinimalRepo{Name: "repo"}},
+		areEqual: true,
+	}, {
+		match1:   &CaptureGroupMatch{Value: "v1", Repo: types.MinimalRepo{Name: "repo"}},
+		match2:   &CaptureGroupMatch{Value: "v2", Repo: types.MinimalRepo{Name: "repo"}},
+		areEqual: false,
 	}}
 
 	for _, tc := range cases {
@@ -0,0 +1,77 @@
+package result
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// PersonMatch is a person selected from the results of a search in a
+// repository, such as the author of matched lines as reported by git blame
+// (select:blame.author) or an owner of matched files as assigned by CODEOWNERS
+// (select:file.owners). Owners may also be teams.
+type PersonMatch struct {
+	Name  string
+	Email string
+	// Handle is the code host handle of the person or team, such as @alice or
+	// @org/team, if known.
+	Handle string
+
+	Repo types.MinimalRepo
+	// Path is the path of the file the person was selected from. It is used
+	// to check sub-repository permissions, and is not shown to users.
+	Path string
+}
+
+func (pm *PersonMatch) RepoName() types.MinimalRepo {
+	return pm.Repo
+}
+
+func (pm *PersonMatch) ResultCount() int {
+	return 1
+}
+
+func (pm *PersonMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (pm *PersonMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: pm.Repo.Name,
+			ID:   pm.Repo.ID,
+		}
+	case filter.Blame:
+		return pm
+	case filter.File:
+		if len(path) > 1 && path[1] == "owners" {
+			return pm
+		}
+	}
+	return nil
+}
+
+// Identifier returns the value identifying the person, which is the email
+// address if known, the handle if known, and the name otherwise.
+func (pm *PersonMatch) Identifier() string {
+	if pm.Email != "" {
+		return strings.ToLower(pm.Email)
+	}
+	if pm.Handle != "" {
+		return strings.ToLower(pm.Handle)
+	}
+	return pm.Name
+}
+
+func (pm *PersonMatch) Key() Key {
+	return Key{
+		TypeRank: rankPersonMatch,
+		Repo:     pm.Repo.Name,
+		Person:   pm.Identifier(),
+	}
+}
+
+func (pm *PersonMatch) searchResultMarker() {}
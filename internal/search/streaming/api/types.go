@@ -25,6 +25,11 @@ type Progress struct {
 
 	// Trace is the URL of an associated trace if the query is logging one.
 	Trace string `json:"trace,omitempty"`
+
+	// Cursor is set on the final progress event of a paginated search which
+	// hit its result limit. Passing it as the cursor of a search for the same
+	// query returns the next page of results.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // Skipped is a description of shards or documents that were skipped.
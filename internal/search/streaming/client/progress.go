@@ -17,6 +17,7 @@ type ProgressAggregator struct {
 	Limit        int
 	DisplayLimit int
 	Trace        string // may be empty
+	Cursor       string // the cursor of the next page, may be empty
 
 	RepoNamer api.RepoNamer
 
@@ -92,6 +93,7 @@ func (p *ProgressAggregator) Final() api.Progress {
 
 	event := api.BuildProgressEvent(s, p.RepoNamer)
 	event.Done = true
+	event.Cursor = p.Cursor
 	return event
 }
 
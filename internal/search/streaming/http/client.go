@@ -30,6 +30,7 @@ type FrontendStreamDecoder struct {
 	OnMatches  func([]EventMatch)
 	OnFilters  func([]*EventFilter)
 	OnAlert    func(*EventAlert)
+	OnExplain  func(*EventExplain)
 	OnError    func(*EventError)
 	OnUnknown  func(event, data []byte)
 }
@@ -81,6 +82,15 @@ func (rr FrontendStreamDecoder) ReadAll(r io.Reader) error {
 				return errors.Errorf("failed to decode alert payload: %w", err)
 			}
 			rr.OnAlert(&d)
+		} else if bytes.Equal(event, []byte("explain")) {
+			if rr.OnExplain == nil {
+				continue
+			}
+			var d EventExplain
+			if err := json.Unmarshal(data, &d); err != nil {
+				return errors.Errorf("failed to decode explain payload: %w", err)
+			}
+			rr.OnExplain(&d)
 		} else if bytes.Equal(event, []byte("error")) {
 			if rr.OnError == nil {
 				continue
@@ -127,6 +137,10 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case PersonMatchType:
+		r.EventMatch = &EventPersonMatch{}
+	case CaptureGroupMatchType:
+		r.EventMatch = &EventCaptureGroupMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
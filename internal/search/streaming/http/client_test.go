@@ -48,6 +48,16 @@ func TestFrontendClient(t *testing.T) {
 				Type:   CommitMatchType,
 				Detail: "test",
 			},
+			&EventPersonMatch{
+				Type:  PersonMatchType,
+				Name:  "test",
+				Email: "test@example.com",
+			},
+			&EventCaptureGroupMatch{
+				Type:  CaptureGroupMatchType,
+				Value: "v1.2.3",
+				Count: 2,
+			},
 		},
 	}, {
 		Name: "filters",
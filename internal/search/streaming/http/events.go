@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"encoding/json"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -163,6 +164,36 @@ type EventCommitMatch struct {
 
 func (e *EventCommitMatch) eventMatch() {}
 
+// EventPersonMatch is a person selected from the results of a search, such as
+// the author of matched lines (select:blame.author) or an owner of matched
+// files (select:file.owners).
+type EventPersonMatch struct {
+	// Type is always PersonMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Name         string `json:"name"`
+	Email        string `json:"email,omitempty"`
+	Handle       string `json:"handle,omitempty"`
+	RepositoryID int32  `json:"repositoryID"`
+	Repository   string `json:"repository"`
+}
+
+func (e *EventPersonMatch) eventMatch() {}
+
+// EventCaptureGroupMatch is a distinct value of a capture group of the pattern
+// in the content matches of a repository (select:content.group(...)).
+type EventCaptureGroupMatch struct {
+	// Type is always CaptureGroupMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Value        string `json:"value"`
+	Count        int    `json:"count"`
+	RepositoryID int32  `json:"repositoryID"`
+	Repository   string `json:"repository"`
+}
+
+func (e *EventCaptureGroupMatch) eventMatch() {}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -194,6 +225,35 @@ type Annotation struct {
 	Value string `json:"value"`
 }
 
+// EventExplain describes what a search would do without running it. It is
+// the only event of searches with explain=true.
+type EventExplain struct {
+	// Plan is the planned job tree of the search.
+	Plan json.RawMessage `json:"plan"`
+	// Jobs are the estimated costs of the jobs of Plan which search
+	// repositories.
+	Jobs []EventJobEstimate `json:"jobs"`
+}
+
+// EventJobEstimate is the estimated cost of a job which searches
+// repositories.
+type EventJobEstimate struct {
+	Job string `json:"job"`
+	// Repos is the number of repositories the job searches. It is a lower
+	// bound if ReposLimitHit is true.
+	Repos         int  `json:"repos"`
+	ReposLimitHit bool `json:"reposLimitHit"`
+	// Indexed and Unindexed split Repos by whether they are searched with
+	// the index.
+	Indexed   int `json:"indexed"`
+	Unindexed int `json:"unindexed"`
+	// Global is true if the job searches all indexed repositories at once.
+	Global bool `json:"global"`
+	// Fanout is the number of requests the job sends to backends which
+	// search a single repository.
+	Fanout int `json:"fanout"`
+}
+
 // EventError emulates a JavaScript error with a message property
 // as is returned when the search encounters an error.
 type EventError struct {
@@ -208,6 +268,8 @@ const (
 	SymbolMatchType
 	CommitMatchType
 	PathMatchType
+	PersonMatchType
+	CaptureGroupMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -222,6 +284,10 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"commit"`), nil
 	case PathMatchType:
 		return []byte(`"path"`), nil
+	case PersonMatchType:
+		return []byte(`"person"`), nil
+	case CaptureGroupMatchType:
+		return []byte(`"group"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -238,6 +304,10 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = CommitMatchType
 	} else if bytes.Equal(b, []byte(`"path"`)) {
 		*t = PathMatchType
+	} else if bytes.Equal(b, []byte(`"person"`)) {
+		*t = PersonMatchType
+	} else if bytes.Equal(b, []byte(`"group"`)) {
+		*t = CaptureGroupMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
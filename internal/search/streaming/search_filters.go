@@ -136,6 +136,14 @@ func (s *SearchFilters) Update(event SearchEvent) {
 			// We leave "rev" empty, instead of using "CommitMatch.Commit.ID". This way we
 			// get 1 filter per repo instead of 1 filter per sha in the side-bar.
 			addRepoFilter(v.Repo.Name, v.Repo.ID, "", int32(v.ResultCount()))
+		case *result.CommitDiffMatch:
+			addRepoFilter(v.Repo.Name, v.Repo.ID, "", int32(v.ResultCount()))
+			addLangFilter(v.Path(), int32(v.ResultCount()), false)
+			addFileFilter(v.Path(), int32(v.ResultCount()), false)
+		case *result.PersonMatch:
+			addRepoFilter(v.Repo.Name, v.Repo.ID, "", 1)
+		case *result.CaptureGroupMatch:
+			addRepoFilter(v.Repo.Name, v.Repo.ID, "", 1)
 		}
 	}
 }
@@ -181,16 +181,17 @@ type SearcherParameters struct {
 // TextPatternInfo is the struct used by vscode pass on search queries. Keep it in
 // sync with pkg/searcher/protocol.PatternInfo.
 type TextPatternInfo struct {
-	Pattern         string
-	IsNegated       bool
-	IsRegExp        bool
-	IsStructuralPat bool
-	CombyRule       string
-	IsWordMatch     bool
-	IsCaseSensitive bool
-	FileMatchLimit  int32
-	Index           query.YesNoOnly
-	Select          filter.SelectPath
+	Pattern          string
+	IsNegated        bool
+	IsRegExp         bool
+	IsStructuralPat  bool
+	CombyRule        string
+	StructuralEngine string
+	IsWordMatch      bool
+	IsCaseSensitive  bool
+	FileMatchLimit   int32
+	Index            query.YesNoOnly
+	Select           filter.SelectPath
 
 	// We do not support IsMultiline
 	// IsMultiline     bool
@@ -225,6 +226,9 @@ func (p *TextPatternInfo) Fields() []otlog.Field {
 	if p.CombyRule != "" {
 		add(otlog.String("combyRule", p.CombyRule))
 	}
+	if p.StructuralEngine != "" {
+		add(otlog.String("structuralEngine", p.StructuralEngine))
+	}
 	if p.IsWordMatch {
 		add(otlog.Bool("isWordMatch", p.IsWordMatch))
 	}
@@ -267,6 +271,9 @@ func (p *TextPatternInfo) String() string {
 		} else {
 			args = append(args, "comby")
 		}
+		if p.StructuralEngine != "" {
+			args = append(args, fmt.Sprintf("engine:%s", p.StructuralEngine))
+		}
 	}
 	if p.IsWordMatch {
 		args = append(args, "word")
@@ -0,0 +1,682 @@
+package zoekt
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/grafana/regexp"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/zoekt"
+	zoektquery "github.com/sourcegraph/zoekt/query"
+	"go.uber.org/atomic"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// FuzzyPathSearchJob searches for the file paths that fuzzily match Pattern
+// (fuzzy:yes). Zoekt's file name index provides the candidate paths of
+// indexed repositories, and gitserver lists the files of unindexed
+// repositories. All candidates are scored with fuzzyMatchPath, and the Limit
+// best matches are sent as path results once the search completes.
+type FuzzyPathSearchJob struct {
+	Repos     *IndexedRepoRevs              // the set of indexed repository revisions to search.
+	Unindexed []*search.RepositoryRevisions // the set of repository revisions listed with gitserver.
+
+	Pattern string
+	Query   zoektquery.Q // the file:, lang: and repohasfile: filters of indexed searches.
+
+	// IncludePaths and ExcludePaths are the file: and lang: filters of
+	// unindexed searches.
+	IncludePaths []*regexp.Regexp
+	ExcludePaths *regexp.Regexp
+
+	Limit int
+}
+
+const (
+	// candidatesPerResult is the number of candidate paths we ask Zoekt for
+	// per result, so that the best matches are among the candidates we rank.
+	candidatesPerResult = 10
+
+	// fuzzyPathListConcurrency is the number of unindexed repository
+	// revisions we list files of concurrently.
+	fuzzyPathListConcurrency = 8
+)
+
+func (j *FuzzyPathSearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	tr, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var mu sync.Mutex
+	top := newFuzzyPathTop(j.Limit)
+	collect := func(file result.File) bool {
+		score, positions, ok := fuzzyMatchPath(j.Pattern, file.Path)
+		if !ok {
+			return false
+		}
+
+		mu.Lock()
+		top.add(fuzzyPathMatch{file: file, score: score, positions: positions})
+		mu.Unlock()
+		return true
+	}
+
+	candidates := fuzzyPathCandidates(j.Pattern)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return j.searchIndexed(gctx, clients.Zoekt, candidates, collect, stream)
+	})
+	g.Go(func() error {
+		return j.searchUnindexed(gctx, clients.Gitserver, candidates, collect, stream)
+	})
+	if err := g.Wait(); err != nil {
+		tr.LogFields(log.Error(err))
+		return nil, err
+	}
+
+	sendFuzzyPathMatches(stream, top, false)
+	return nil, nil
+}
+
+// sendFuzzyPathMatches sends the best matches of top as path results.
+func sendFuzzyPathMatches(stream streaming.Sender, top *fuzzyPathTop, limitHit bool) {
+	matches := top.ranked()
+	results := make(result.Matches, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, &result.FileMatch{
+			File:        m.file,
+			PathMatches: fuzzyPathRanges(m.file.Path, m.positions),
+		})
+	}
+	stream.Send(streaming.SearchEvent{
+		Results: results,
+		Stats:   streaming.Stats{IsLimitHit: limitHit || top.dropped},
+	})
+}
+
+// FuzzyPathRankJob ranks the path results of its child together. The
+// repositories of a search are resolved in pages, and the FuzzyPathSearchJob
+// of each page only ranks the paths of its own page. FuzzyPathRankJob
+// re-ranks the results of all pages, so that the Limit best matches across
+// all repositories are returned.
+type FuzzyPathRankJob struct {
+	Pattern string
+	Limit   int
+	Child   job.Job
+}
+
+func (j *FuzzyPathRankJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu       sync.Mutex
+		top      = newFuzzyPathTop(j.Limit)
+		limitHit bool
+	)
+	alert, err = j.Child.Run(ctx, clients, streaming.StreamFunc(func(e streaming.SearchEvent) {
+		mu.Lock()
+		for _, m := range e.Results {
+			fm, ok := m.(*result.FileMatch)
+			if !ok {
+				continue
+			}
+			score, positions, ok := fuzzyMatchPath(j.Pattern, fm.Path)
+			if !ok {
+				continue
+			}
+			top.add(fuzzyPathMatch{file: fm.File, score: score, positions: positions})
+		}
+		limitHit = limitHit || e.Stats.IsLimitHit
+		mu.Unlock()
+
+		// Forward the stats of the event, such as skipped repositories.
+		e.Results = nil
+		stream.Send(e)
+	}))
+
+	// Send what we have, even if the search failed.
+	mu.Lock()
+	defer mu.Unlock()
+	if len(top.matches) > 0 || limitHit {
+		sendFuzzyPathMatches(stream, top, limitHit)
+	}
+	return alert, err
+}
+
+func (j *FuzzyPathRankJob) Name() string {
+	return "FuzzyPathRankJob"
+}
+
+func (j *FuzzyPathRankJob) Fields(v job.Verbosity) (res []log.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			log.String("pattern", j.Pattern),
+			log.Int("limit", j.Limit),
+		)
+	}
+	return res
+}
+
+func (j *FuzzyPathRankJob) Children() []job.Describer { return []job.Describer{j.Child} }
+
+func (j *FuzzyPathRankJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.Child = job.Map(j.Child, fn)
+	return &cp
+}
+
+// searchIndexed collects the paths of indexed repositories that match the
+// candidate patterns. The candidates are searched in order, and broader
+// candidates are only searched if the previous ones found fewer than Limit
+// paths.
+func (j *FuzzyPathSearchJob) searchIndexed(ctx context.Context, client zoekt.Streamer, candidates []string, collect func(result.File) bool, stream streaming.Sender) error {
+	if j.Repos == nil || len(j.Repos.RepoRevs) == 0 {
+		return nil
+	}
+
+	k := ResultCountFactor(len(j.Repos.RepoRevs), int32(j.Limit), false)
+	opts := SearchOpts(ctx, k, int32(j.Limit), filter.SelectPath{})
+	opts.ChunkMatches = false
+	opts.MaxDocDisplayCount = candidatesPerResult * j.Limit
+
+	if deadline, ok := ctx.Deadline(); ok {
+		// See zoektSearch for why zoekt may use all of the remaining timeout.
+		opts.MaxWallTime = time.Until(deadline)
+		if opts.MaxWallTime < 0 {
+			return ctx.Err()
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = contextWithoutDeadline(ctx)
+		defer cancel()
+	}
+
+	branchRepos := &zoektquery.BranchesRepos{List: j.Repos.BranchRepos()}
+	for _, candidate := range candidates {
+		re, err := FileRe(candidate, false)
+		if err != nil {
+			return err
+		}
+		q := zoektquery.NewAnd(branchRepos, re)
+		if j.Query != nil {
+			q = zoektquery.NewAnd(q, j.Query)
+		}
+
+		found := atomic.Int64{}
+		err = client.StreamSearch(ctx, zoektquery.Simplify(q), &opts, backend.ZoektStreamFunc(func(event *zoekt.SearchResult) {
+			for _, file := range event.Files {
+				repo, inputRevs := j.Repos.getRepoInputRev(&file)
+				for _, inputRev := range inputRevs {
+					inputRev := inputRev // copy so we can take the pointer
+					collect(result.File{
+						InputRev: &inputRev,
+						CommitID: api.CommitID(file.Version),
+						Repo:     repo,
+						Path:     file.FileName,
+					})
+				}
+			}
+			found.Add(int64(len(event.Files)))
+			stream.Send(streaming.SearchEvent{
+				Stats: streaming.Stats{IsLimitHit: event.FilesSkipped+event.ShardsSkipped > 0},
+			})
+		}))
+		if err != nil {
+			return err
+		}
+		if found.Load() >= int64(j.Limit) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// searchUnindexed collects the paths of unindexed repositories that match the
+// candidate patterns with git ls-tree. Like in searchIndexed, the candidates
+// are listed in order, and broader candidates are only listed if the previous
+// ones matched fewer than Limit paths.
+func (j *FuzzyPathSearchJob) searchUnindexed(ctx context.Context, client gitserver.Client, candidates []string, collect func(result.File) bool, stream streaming.Sender) error {
+	if len(j.Unindexed) == 0 {
+		return nil
+	}
+
+	for _, candidate := range candidates {
+		re, err := regexp.Compile(`(?i)` + candidate)
+		if err != nil {
+			return err
+		}
+
+		found := atomic.Int64{}
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(fuzzyPathListConcurrency)
+		for _, repoRevs := range j.Unindexed {
+			repo := repoRevs.Repo // capture repo
+			for _, rev := range repoRevs.Revs {
+				rev := rev // capture rev
+				g.Go(func() error {
+					n, err := j.listFiles(gctx, client, repo, rev, re, collect)
+					found.Add(int64(n))
+					status, limitHit, err := search.HandleRepoSearchResult(repo.ID, []string{rev}, false, false, err)
+					stream.Send(streaming.SearchEvent{
+						Stats: streaming.Stats{
+							Status:     status,
+							IsLimitHit: limitHit,
+						},
+					})
+					return err
+				})
+			}
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+		if found.Load() >= int64(j.Limit) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// listFiles collects the paths of repo at rev which match re and returns the
+// number of paths that fuzzily matched.
+func (j *FuzzyPathSearchJob) listFiles(ctx context.Context, client gitserver.Client, repo types.MinimalRepo, rev string, re *regexp.Regexp, collect func(result.File) bool) (int, error) {
+	// Do not trigger a repo-updater lookup, see searcher.TextSearchJob.
+	commit, err := client.ResolveRevision(ctx, repo.Name, rev, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return 0, err
+	}
+
+	paths, err := client.ListFiles(ctx, repo.Name, commit, re, authz.DefaultSubRepoPermsChecker)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	for _, path := range paths {
+		if !j.matchesPathFilters(path) {
+			continue
+		}
+		if collect(result.File{
+			InputRev: &rev,
+			CommitID: commit,
+			Repo:     repo,
+			Path:     path,
+		}) {
+			matched++
+		}
+	}
+	return matched, nil
+}
+
+func (j *FuzzyPathSearchJob) matchesPathFilters(path string) bool {
+	for _, re := range j.IncludePaths {
+		if !re.MatchString(path) {
+			return false
+		}
+	}
+	return j.ExcludePaths == nil || !j.ExcludePaths.MatchString(path)
+}
+
+func (j *FuzzyPathSearchJob) Name() string {
+	return "FuzzyPathSearchJob"
+}
+
+func (j *FuzzyPathSearchJob) Fields(v job.Verbosity) (res []log.Field) {
+	switch v {
+	case job.VerbosityMax:
+		res = append(res,
+			log.Int("limit", j.Limit),
+			log.Object("includePaths", j.IncludePaths),
+			log.Object("excludePaths", j.ExcludePaths),
+		)
+		if j.Repos != nil {
+			res = append(res, log.Int("numRepoRevs", len(j.Repos.RepoRevs)))
+		}
+		res = append(res, log.Int("numUnindexedRepos", len(j.Unindexed)))
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			log.String("pattern", j.Pattern),
+			trace.Stringer("query", j.Query),
+		)
+	}
+	return res
+}
+
+func (j *FuzzyPathSearchJob) Children() []job.Describer       { return nil }
+func (j *FuzzyPathSearchJob) MapChildren(job.MapFunc) job.Job { return j }
+
+type fuzzyPathMatch struct {
+	file      result.File
+	score     int
+	positions []int // the indexes of the runes of the path that matched the pattern.
+}
+
+// rankFuzzyPathMatches sorts matches by descending score. Ties are broken
+// in favor of shorter paths, then by repository and path so that the order
+// is stable.
+func rankFuzzyPathMatches(matches []fuzzyPathMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		return fuzzyPathBetter(matches[i], matches[j])
+	})
+}
+
+// fuzzyPathBetter returns true if a is ranked before b.
+func fuzzyPathBetter(a, b fuzzyPathMatch) bool {
+	if a.score != b.score {
+		return a.score > b.score
+	}
+	if len(a.file.Path) != len(b.file.Path) {
+		return len(a.file.Path) < len(b.file.Path)
+	}
+	if a.file.Repo.Name != b.file.Repo.Name {
+		return a.file.Repo.Name < b.file.Repo.Name
+	}
+	return a.file.Path < b.file.Path
+}
+
+type fuzzyPathKey struct {
+	repo api.RepoID
+	rev  string
+	path string
+}
+
+func newFuzzyPathKey(file result.File) fuzzyPathKey {
+	key := fuzzyPathKey{repo: file.Repo.ID, path: file.Path}
+	if file.InputRev != nil {
+		key.rev = *file.InputRev
+	}
+	return key
+}
+
+// fuzzyPathTop keeps the limit best matches added to it, so that we don't
+// hold on to every path that matches while a search runs.
+type fuzzyPathTop struct {
+	limit   int
+	matches fuzzyPathHeap
+	keys    map[fuzzyPathKey]struct{}
+
+	// dropped is true if a match was dropped because limit better matches
+	// were kept.
+	dropped bool
+}
+
+func newFuzzyPathTop(limit int) *fuzzyPathTop {
+	return &fuzzyPathTop{limit: limit, keys: map[fuzzyPathKey]struct{}{}}
+}
+
+// add keeps m if it is among the limit best matches. A match is only kept
+// once, even if it is added several times.
+func (t *fuzzyPathTop) add(m fuzzyPathMatch) {
+	key := newFuzzyPathKey(m.file)
+	if _, ok := t.keys[key]; ok {
+		return
+	}
+
+	if len(t.matches) < t.limit {
+		heap.Push(&t.matches, m)
+		t.keys[key] = struct{}{}
+		return
+	}
+
+	// A dropped match can't be added again later: it is worse than all kept
+	// matches, and the worst kept match only gets better.
+	t.dropped = true
+	if len(t.matches) == 0 || !fuzzyPathBetter(m, t.matches[0]) {
+		return
+	}
+	delete(t.keys, newFuzzyPathKey(t.matches[0].file))
+	t.matches[0] = m
+	heap.Fix(&t.matches, 0)
+	t.keys[key] = struct{}{}
+}
+
+// ranked returns the kept matches, best first.
+func (t *fuzzyPathTop) ranked() []fuzzyPathMatch {
+	matches := make([]fuzzyPathMatch, len(t.matches))
+	copy(matches, t.matches)
+	rankFuzzyPathMatches(matches)
+	return matches
+}
+
+// fuzzyPathHeap is a heap of matches with the worst match at its root.
+type fuzzyPathHeap []fuzzyPathMatch
+
+func (h fuzzyPathHeap) Len() int           { return len(h) }
+func (h fuzzyPathHeap) Less(i, j int) bool { return fuzzyPathBetter(h[j], h[i]) }
+func (h fuzzyPathHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *fuzzyPathHeap) Push(x any)        { *h = append(*h, x.(fuzzyPathMatch)) }
+func (h *fuzzyPathHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// The scores of fuzzyMatchPath. Every matched rune scores fuzzyScoreMatch
+// plus its bonuses, and runes skipped between two matched runes are
+// penalized.
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyBonusBoundary    = 8 // the rune starts the path, a path segment or a word.
+	fuzzyBonusCamelCase   = 7 // the rune is an upper case rune following a lower case one.
+	fuzzyBonusConsecutive = 8 // the rune follows the previous matched rune.
+	fuzzyBonusBasename    = 4 // the rune is in the file name rather than a directory.
+	fuzzyPenaltyGap       = 1
+	fuzzyPenaltyTypo      = 24
+
+	// fuzzyMinTypoLength is the length a pattern needs for typos to be
+	// tolerated. Shorter patterns would match too many paths.
+	fuzzyMinTypoLength = 4
+)
+
+// fuzzyMatchPath scores how well path matches pattern. The runes of pattern
+// have to appear in path in the same order, ignoring case, but not
+// necessarily next to each other. Patterns of fuzzyMinTypoLength runes or
+// more also match with one typo: one rune of the pattern may be missing
+// from path, which covers extra, wrong and transposed runes. It returns the
+// indexes of the matched runes of path.
+func fuzzyMatchPath(pattern, path string) (score int, positions []int, ok bool) {
+	if score, positions, ok = fuzzySubsequence([]rune(pattern), path); ok {
+		return score, positions, true
+	}
+
+	p := []rune(pattern)
+	if len(p) < fuzzyMinTypoLength {
+		return 0, nil, false
+	}
+	for i := range p {
+		variant := make([]rune, 0, len(p)-1)
+		variant = append(variant, p[:i]...)
+		variant = append(variant, p[i+1:]...)
+		if s, pos, matched := fuzzySubsequence(variant, path); matched && (!ok || s > score) {
+			score, positions, ok = s, pos, true
+		}
+	}
+	if !ok {
+		return 0, nil, false
+	}
+	return score - fuzzyPenaltyTypo, positions, true
+}
+
+// fuzzySubsequence returns the best score of matching the runes of pattern
+// as a subsequence of path, ignoring case.
+func fuzzySubsequence(pattern []rune, path string) (int, []int, bool) {
+	s := []rune(path)
+	n, m := len(pattern), len(s)
+	if n == 0 || n > m {
+		return 0, nil, false
+	}
+
+	p := make([]rune, n)
+	for i, r := range pattern {
+		p[i] = unicode.ToLower(r)
+	}
+	lower := make([]rune, m)
+	for i, r := range s {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	basename := 0
+	for i, r := range s {
+		if r == '/' {
+			basename = i + 1
+		}
+	}
+	bonus := func(j int) (b int) {
+		switch {
+		case j == 0 || isFuzzyDelimiter(s[j-1]):
+			b = fuzzyBonusBoundary
+		case unicode.IsLower(s[j-1]) && unicode.IsUpper(s[j]):
+			b = fuzzyBonusCamelCase
+		}
+		if j >= basename {
+			b += fuzzyBonusBasename
+		}
+		return b
+	}
+
+	// scores[i][j] is the best score of matching pattern[:i+1] with
+	// pattern[i] matched at s[j], and from[i][j] is where pattern[i-1] was
+	// matched for that score.
+	const none = math.MinInt32
+	scores := make([][]int, n)
+	from := make([][]int, n)
+	for i := 0; i < n; i++ {
+		scores[i] = make([]int, m)
+		from[i] = make([]int, m)
+
+		// gapMax is the best score of matching pattern[i-1] at some k <= j-2,
+		// offset by the gap penalty of k so that it can be compared at any j.
+		gapMax, gapArg := none, -1
+		for j := 0; j < m; j++ {
+			if i > 0 && j >= 2 {
+				if k := j - 2; scores[i-1][k] != none && scores[i-1][k]+fuzzyPenaltyGap*k > gapMax {
+					gapMax, gapArg = scores[i-1][k]+fuzzyPenaltyGap*k, k
+				}
+			}
+
+			scores[i][j] = none
+			if lower[j] != p[i] {
+				continue
+			}
+			if i == 0 {
+				scores[i][j] = fuzzyScoreMatch + bonus(j)
+				from[i][j] = -1
+				continue
+			}
+
+			best, arg := none, -1
+			if gapArg >= 0 {
+				best, arg = gapMax-fuzzyPenaltyGap*(j-1), gapArg
+			}
+			if j > 0 && scores[i-1][j-1] != none && scores[i-1][j-1]+fuzzyBonusConsecutive > best {
+				best, arg = scores[i-1][j-1]+fuzzyBonusConsecutive, j-1
+			}
+			if arg < 0 {
+				continue
+			}
+			scores[i][j] = best + fuzzyScoreMatch + bonus(j)
+			from[i][j] = arg
+		}
+	}
+
+	best, end := none, -1
+	for j, score := range scores[n-1] {
+		if score > best {
+			best, end = score, j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, n)
+	for i, j := n-1, end; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+	return best, positions, true
+}
+
+func isFuzzyDelimiter(r rune) bool {
+	switch r {
+	case '/', '_', '-', '.', ' ':
+		return true
+	}
+	return false
+}
+
+// fuzzyPathCandidates returns the regular expressions of the paths that may
+// match pattern, from the most to the least selective. The first one
+// matches the paths that contain pattern as a subsequence. For patterns that
+// tolerate typos, the second one matches the paths that contain either half
+// of pattern as a subsequence: removing one rune from pattern leaves one of
+// its halves intact.
+func fuzzyPathCandidates(pattern string) []string {
+	p := []rune(pattern)
+	candidates := []string{fuzzySubsequenceRegexp(p)}
+	if len(p) >= fuzzyMinTypoLength {
+		half := len(p) / 2
+		candidates = append(candidates, fuzzySubsequenceRegexp(p[:half])+"|"+fuzzySubsequenceRegexp(p[half:]))
+	}
+	return candidates
+}
+
+func fuzzySubsequenceRegexp(p []rune) string {
+	quoted := make([]string, 0, len(p))
+	for _, r := range p {
+		quoted = append(quoted, regexp.QuoteMeta(string(r)))
+	}
+	return strings.Join(quoted, ".*")
+}
+
+// fuzzyPathRanges converts the matched rune positions of path to ranges,
+// merging adjacent positions.
+func fuzzyPathRanges(path string, positions []int) (ranges []result.Range) {
+	offsets := make([]int, 0, utf8.RuneCountInString(path)+1)
+	for offset := range path {
+		offsets = append(offsets, offset)
+	}
+	offsets = append(offsets, len(path))
+
+	location := func(i int) result.Location {
+		// we can treat path matches as a single-line
+		return result.Location{Offset: offsets[i], Column: i}
+	}
+	for i := 0; i < len(positions); {
+		start := i
+		for i+1 < len(positions) && positions[i+1] == positions[i]+1 {
+			i++
+		}
+		ranges = append(ranges, result.Range{
+			Start: location(positions[start]),
+			End:   location(positions[i] + 1),
+		})
+		i++
+	}
+	return ranges
+}
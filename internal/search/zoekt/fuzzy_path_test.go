@@ -0,0 +1,213 @@
+package zoekt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/hexops/autogold"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestFuzzyMatchPath(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "srchjob", path: "internal/search/job/job.go", want: true},
+		{pattern: "SRCHJOB", path: "internal/search/job/job.go", want: true},
+		{pattern: "jobgo", path: "internal/search/job/job.go", want: true},
+		{pattern: "xyjob", path: "internal/search/job/job.go", want: false},
+		// One typo is tolerated.
+		{pattern: "serchx", path: "search.go", want: true},
+		{pattern: "saerch", path: "search.go", want: true},
+		{pattern: "seacrh", path: "search.go", want: true},
+		{pattern: "srxxch", path: "search.go", want: false},
+		// Short patterns must match exactly.
+		{pattern: "sxa", path: "search.go", want: false},
+		{pattern: "", path: "search.go", want: false},
+		{pattern: "readme.md", path: "README.md", want: true},
+		{pattern: "ü", path: "dir/über.go", want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.pattern+" "+c.path, func(t *testing.T) {
+			_, positions, ok := fuzzyMatchPath(c.pattern, c.path)
+			if ok != c.want {
+				t.Fatalf("got %v, want %v", ok, c.want)
+			}
+			for i := 1; i < len(positions); i++ {
+				if positions[i] <= positions[i-1] {
+					t.Fatalf("positions %v are not increasing", positions)
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchPath_ranking(t *testing.T) {
+	rank := func(pattern string, paths ...string) []string {
+		var matches []fuzzyPathMatch
+		for _, path := range paths {
+			if score, _, ok := fuzzyMatchPath(pattern, path); ok {
+				matches = append(matches, fuzzyPathMatch{file: result.File{Path: path}, score: score})
+			}
+		}
+		rankFuzzyPathMatches(matches)
+		ranked := make([]string, 0, len(matches))
+		for _, m := range matches {
+			ranked = append(ranked, m.file.Path)
+		}
+		return ranked
+	}
+
+	autogold.Want("prefers file names and boundaries", []string{
+		"internal/search_jobs_old.go",
+		"internal/search/searcher/job.go",
+		"internal/search/job/job.go",
+		"internal/search/job/jobutil/job.go",
+	}).Equal(t, rank("srchjob",
+		"internal/search_jobs_old.go",
+		"internal/search/searcher/job.go",
+		"internal/search/job/jobutil/job.go",
+		"internal/search/job/job.go",
+		"cmd/frontend/main.go",
+	))
+
+	autogold.Want("prefers camel case", []string{
+		"ui/SearchResults.tsx",
+		"ui/searchresults.tsx",
+	}).Equal(t, rank("sr", "ui/searchresults.tsx", "ui/SearchResults.tsx"))
+
+	autogold.Want("prefers exact over typos", []string{
+		"search.go",
+		"sarch.go",
+	}).Equal(t, rank("search", "sarch.go", "search.go"))
+}
+
+func TestFuzzyPathRanges(t *testing.T) {
+	_, positions, _ := fuzzyMatchPath("jobgo", "a/ü/job.go")
+	autogold.Want("merges adjacent positions", []result.Range{
+		{
+			Start: result.Location{Offset: 5, Column: 4},
+			End:   result.Location{Offset: 8, Column: 7},
+		},
+		{
+			Start: result.Location{Offset: 9, Column: 8},
+			End:   result.Location{Offset: 11, Column: 10},
+		},
+	}).Equal(t, fuzzyPathRanges("a/ü/job.go", positions))
+}
+
+func TestFuzzyPathCandidates(t *testing.T) {
+	candidates := fuzzyPathCandidates("search")
+	autogold.Want("candidates", []string{
+		"s.*e.*a.*r.*c.*h",
+		"s.*e.*a|r.*c.*h",
+	}).Equal(t, candidates)
+
+	// Every path that matches with a typo must be a candidate.
+	broadest := regexp.MustCompile(`(?i)` + candidates[len(candidates)-1])
+	for _, path := range []string{"sarch.go", "seach.go", "searh.go", "SEARC.go"} {
+		if _, _, ok := fuzzyMatchPath("search", path); !ok {
+			t.Fatalf("%s does not match", path)
+		}
+		if !broadest.MatchString(path) {
+			t.Fatalf("%s is not a candidate", path)
+		}
+	}
+}
+
+func TestFuzzyPathSearchJob_unindexed(t *testing.T) {
+	gsClient := gitserver.NewMockClient()
+	gsClient.ResolveRevisionFunc.SetDefaultReturn("deadbeef", nil)
+	gsClient.ListFilesFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, re *regexp.Regexp, _ authz.SubRepoPermissionChecker) ([]string, error) {
+		var paths []string
+		for _, path := range []string{"README.md", "cmd/main.go", "internal/main_test.go", "vendor/main.go", "docs/index.md"} {
+			if re.MatchString(path) {
+				paths = append(paths, path)
+			}
+		}
+		return paths, nil
+	})
+
+	j := &FuzzyPathSearchJob{
+		Unindexed: []*search.RepositoryRevisions{{
+			Repo: types.MinimalRepo{ID: 1, Name: "foo"},
+			Revs: []string{""},
+		}},
+		Pattern:      "main",
+		ExcludePaths: regexp.MustCompile(`^vendor/`),
+		Limit:        1,
+	}
+
+	stream := streaming.NewAggregatingStream()
+	_, err := j.Run(context.Background(), job.RuntimeClients{Gitserver: gsClient}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, m := range stream.Results {
+		fm := m.(*result.FileMatch)
+		if fm.CommitID != "deadbeef" || len(fm.PathMatches) == 0 {
+			t.Fatalf("unexpected match %+v", fm)
+		}
+		paths = append(paths, fm.Path)
+	}
+	autogold.Want("best unindexed path", []string{"cmd/main.go"}).Equal(t, paths)
+	if !stream.Stats.IsLimitHit {
+		t.Fatal("expected limit to be hit")
+	}
+
+	// The strictest candidate found enough paths, so the broader ones are
+	// not listed.
+	var listed []string
+	for _, call := range gsClient.ListFilesFunc.History() {
+		listed = append(listed, call.Arg3.String())
+	}
+	autogold.Want("listed candidates", []string{"(?i)m.*a.*i.*n"}).Equal(t, listed)
+}
+
+func TestFuzzyPathRankJob(t *testing.T) {
+	fm := func(repo, path string) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Repo: types.MinimalRepo{Name: api.RepoName(repo)}, Path: path}}
+	}
+
+	// Each page of repositories is ranked on its own.
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("a", "cmd/main_helper.go"), fm("a", "internal/mxaxixn.go")}})
+		s.Send(streaming.SearchEvent{Results: result.Matches{fm("b", "main.go"), fm("b", "docs/mainly.md")}})
+		return nil, nil
+	})
+
+	j := &FuzzyPathRankJob{Pattern: "main", Limit: 2, Child: child}
+	stream := streaming.NewAggregatingStream()
+	if _, err := j.Run(context.Background(), job.RuntimeClients{}, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, m := range stream.Results {
+		fm := m.(*result.FileMatch)
+		if len(fm.PathMatches) == 0 {
+			t.Fatalf("unexpected match %+v", fm)
+		}
+		paths = append(paths, string(fm.Repo.Name)+"/"+fm.Path)
+	}
+	autogold.Want("best paths across pages", []string{"b/main.go", "b/docs/mainly.md"}).Equal(t, paths)
+	if !stream.Stats.IsLimitHit {
+		t.Fatal("expected limit to be hit")
+	}
+}
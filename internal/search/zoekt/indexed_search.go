@@ -725,6 +725,18 @@ func (t *GlobalTextSearchJob) Fields(v job.Verbosity) (res []otlog.Field) {
 func (t *GlobalTextSearchJob) Children() []job.Describer       { return nil }
 func (t *GlobalTextSearchJob) MapChildren(job.MapFunc) job.Job { return t }
 
+// GlobalRepoScope returns a Zoekt query matching the repositories a global
+// search with q and repoOptions searches for the actor of ctx. Unlike Run, it
+// does not modify q.
+func GlobalRepoScope(ctx context.Context, logger log.Logger, db database.DB, q *GlobalZoektQuery, repoOptions search.RepoOptions) zoektquery.Q {
+	scoped := &GlobalZoektQuery{
+		RepoScope:      append([]zoektquery.Q{}, q.RepoScope...),
+		IncludePrivate: q.IncludePrivate,
+	}
+	scoped.ApplyPrivateFilter(privateReposForActor(ctx, logger, db, repoOptions))
+	return zoektquery.Simplify(zoektquery.NewOr(scoped.RepoScope...))
+}
+
 // Get all private repos for the the current actor. On sourcegraph.com, those are
 // only the repos directly added by the user. Otherwise it's all repos the user has
 // access to on all connected code hosts / external services.
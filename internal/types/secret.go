@@ -55,10 +55,14 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 	case *schema.AWSCodeCommitConnection:
 		es.redactString(c.SecretAccessKey, "secretAccessKey")
 		es.redactString(c.GitCredentials.Password, "gitCredentials", "password")
+	case *schema.AzureDevOpsConnection:
+		es.redactString(c.Token, "token")
 	case *schema.PhabricatorConnection:
 		es.redactString(c.Token, "token")
 	case *schema.PerforceConnection:
 		es.redactString(c.P4Passwd, "p4.passwd")
+	case *schema.SubversionConnection:
+		es.redactString(c.Password, "password")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -85,6 +89,11 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		es.redactString(c.Token, "token")
 	case *schema.NpmPackagesConnection:
 		es.redactString(c.Credentials, "credentials")
+	case *schema.MercurialConnection:
+		err = es.redactURL(c.Url, "url")
+		if err != nil {
+			return "", err
+		}
 	case *schema.OtherExternalServiceConnection:
 		err = es.redactURL(c.Url, "url")
 		if err != nil {
@@ -154,12 +163,18 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 		o := oldCfg.(*schema.AWSCodeCommitConnection)
 		es.unredactString(c.SecretAccessKey, o.SecretAccessKey, "secretAccessKey")
 		es.unredactString(c.GitCredentials.Password, o.GitCredentials.Password, "gitCredentials", "password")
+	case *schema.AzureDevOpsConnection:
+		o := oldCfg.(*schema.AzureDevOpsConnection)
+		es.unredactString(c.Token, o.Token, "token")
 	case *schema.PhabricatorConnection:
 		o := oldCfg.(*schema.PhabricatorConnection)
 		es.unredactString(c.Token, o.Token, "token")
 	case *schema.PerforceConnection:
 		o := oldCfg.(*schema.PerforceConnection)
 		es.unredactString(c.P4Passwd, o.P4Passwd, "p4.passwd")
+	case *schema.SubversionConnection:
+		o := oldCfg.(*schema.SubversionConnection)
+		es.unredactString(c.Password, o.Password, "password")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -185,6 +200,12 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.NpmPackagesConnection:
 		o := oldCfg.(*schema.NpmPackagesConnection)
 		es.unredactString(c.Credentials, o.Credentials, "credentials")
+	case *schema.MercurialConnection:
+		o := oldCfg.(*schema.MercurialConnection)
+		err = es.unredactURL(c.Url, o.Url, "url")
+		if err != nil {
+			return err
+		}
 	case *schema.OtherExternalServiceConnection:
 		o := oldCfg.(*schema.OtherExternalServiceConnection)
 		err = es.unredactURL(c.Url, o.Url, "url")
@@ -0,0 +1,381 @@
+package uploadstore
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type localStore struct {
+	dir        string
+	ttl        time.Duration
+	operations *Operations
+
+	// locks serializes Compose calls writing to the same destination, so that
+	// a concurrent call cannot observe (or delete) sources already consumed by
+	// another call.
+	locksMu sync.Mutex
+	locks   map[string]*keyLock
+
+	expireMu   sync.Mutex
+	lastExpiry time.Time
+}
+
+var _ Store = &localStore{}
+
+type LocalConfig struct {
+	// Dir is the directory under which buckets are created. Each bucket is a
+	// subdirectory named after the bucket.
+	Dir string
+}
+
+// tempFilePrefix is the prefix of the files objects are written to before they
+// are atomically moved into place. These files are never visible as objects.
+const tempFilePrefix = ".uploadstore-tmp-"
+
+// expireInterval is the minimum amount of time between two sweeps of expired
+// objects triggered by writes to the store.
+const expireInterval = time.Hour
+
+// newLocalFromConfig creates a new store backed by a directory on the local filesystem.
+func newLocalFromConfig(ctx context.Context, config Config, operations *Operations) (Store, error) {
+	if config.Local.Dir == "" {
+		return nil, errors.New("no directory configured for local upload store")
+	}
+
+	return newLocalWithDir(filepath.Join(config.Local.Dir, config.Bucket), config.TTL, operations), nil
+}
+
+func newLocalWithDir(dir string, ttl time.Duration, operations *Operations) *localStore {
+	return &localStore{
+		dir:        dir,
+		ttl:        ttl,
+		operations: operations,
+		locks:      map[string]*keyLock{},
+	}
+}
+
+// Init creates the target directory if it does not exist and removes all objects
+// that have outlived the configured TTL. There is no bucket configuration to manage
+// for a local directory, so the directory is created regardless of ManageBucket.
+func (s *localStore) Init(ctx context.Context) error {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+
+	if err := s.expire(ctx); err != nil {
+		return errors.Wrap(err, "failed to remove expired objects")
+	}
+
+	return nil
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, _, endObservation := s.operations.Get.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+	if s.expired(fi, time.Now()) {
+		// The object will be removed by the next sweep, but it must not be
+		// readable until then.
+		_ = f.Close()
+		return nil, errors.Wrap(&fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}, "failed to get object")
+	}
+
+	return f, nil
+}
+
+func (s *localStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Upload.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := writeFileAtomic(path, func(w io.Writer) (int64, error) {
+		return io.Copy(w, r)
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+
+	s.maybeExpire()
+	return n, nil
+}
+
+func (s *localStore) Compose(ctx context.Context, destination string, sources ...string) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Compose.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("destination", destination),
+		log.String("sources", strings.Join(sources, ", ")),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	path, err := s.path(destination)
+	if err != nil {
+		return 0, err
+	}
+
+	sourcePaths := make([]string, 0, len(sources))
+	for _, source := range sources {
+		sourcePath, err := s.path(source)
+		if err != nil {
+			return 0, err
+		}
+		sourcePaths = append(sourcePaths, sourcePath)
+	}
+
+	unlock := s.lock(destination)
+	defer unlock()
+
+	defer func() {
+		if err == nil {
+			// Delete sources on success
+			if err := s.deleteSources(sourcePaths); err != nil {
+				log15.Error("Failed to delete source objects", "error", err)
+			}
+		}
+	}()
+
+	n, err := writeFileAtomic(path, func(w io.Writer) (int64, error) {
+		var written int64
+		for _, sourcePath := range sourcePaths {
+			n, err := copyFile(w, sourcePath)
+			written += n
+			if err != nil {
+				return written, err
+			}
+		}
+
+		return written, nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	s.maybeExpire()
+	return n, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, _, endObservation := s.operations.Delete.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to delete object")
+	}
+
+	return nil
+}
+
+// path returns the path of the file holding the object with the given key. Keys
+// may contain slashes, but must not refer to a file outside of the store's directory
+// or to one of the store's temporary files.
+func (s *localStore) path(key string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid object key %q", key)
+	}
+	if strings.HasPrefix(filepath.Base(rel), tempFilePrefix) {
+		return "", errors.Errorf("invalid object key %q", key)
+	}
+
+	return filepath.Join(s.dir, rel), nil
+}
+
+func (s *localStore) deleteSources(sourcePaths []string) error {
+	var errs error
+	for _, sourcePath := range sourcePaths {
+		if err := os.Remove(sourcePath); err != nil && !os.IsNotExist(err) {
+			errs = errors.Append(errs, errors.Wrap(err, "failed to delete source object"))
+		}
+	}
+
+	return errs
+}
+
+// expired returns true if the file described by the given info has outlived the
+// configured TTL. Objects never expire when no TTL is configured.
+func (s *localStore) expired(fi fs.FileInfo, now time.Time) bool {
+	return s.ttl > 0 && now.Sub(fi.ModTime()) > s.ttl
+}
+
+// maybeExpire removes expired objects in the background if no sweep has happened
+// within the last expireInterval.
+func (s *localStore) maybeExpire() {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.expireMu.Lock()
+	defer s.expireMu.Unlock()
+	if time.Since(s.lastExpiry) < expireInterval {
+		return
+	}
+	s.lastExpiry = time.Now()
+
+	go func() {
+		if err := s.expire(context.Background()); err != nil {
+			log15.Error("Failed to remove expired objects", "dir", s.dir, "error", err)
+		}
+	}()
+}
+
+// expire removes all objects, as well as temporary files left behind by interrupted
+// writes, that have outlived the configured TTL.
+func (s *localStore) expire(ctx context.Context) error {
+	if s.ttl <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	return filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Removed concurrently
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !s.expired(fi, now) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// keyLock is a reference-counted mutex guarding a single key.
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock acquires the lock for the given key and returns a function that releases it.
+func (s *localStore) lock(key string) func() {
+	s.locksMu.Lock()
+	l, ok := s.locks[key]
+	if !ok {
+		l = &keyLock{}
+		s.locks[key] = l
+	}
+	l.refs++
+	s.locksMu.Unlock()
+
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+
+		s.locksMu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(s.locks, key)
+		}
+		s.locksMu.Unlock()
+	}
+}
+
+// writeFileAtomic invokes the given function with a temporary file in the same
+// directory as the given path, then moves the temporary file to the given path.
+// Readers of the path will see either the previous content or the complete new
+// content, but never a partial write.
+func writeFileAtomic(path string, fn func(w io.Writer) (int64, error)) (_ int64, err error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempFilePrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := fn(tmp)
+	if err != nil {
+		return 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// copyFile writes the content of the file at the given path to the given writer.
+func copyFile(w io.Writer, path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return io.Copy(w, f)
+}
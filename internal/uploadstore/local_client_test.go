@@ -0,0 +1,270 @@
+package uploadstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestLocalInit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "test-bucket")
+
+	client := testLocalClient(dir)
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+
+	if fi, err := os.Stat(dir); err != nil {
+		t.Fatalf("unexpected error statting directory: %s", err)
+	} else if !fi.IsDir() {
+		t.Errorf("expected %s to be a directory", dir)
+	}
+}
+
+func TestLocalInitRemovesExpiredObjects(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "expired", "TEST PAYLOAD", time.Now().Add(-time.Hour*24*4))
+	writeLocalObject(t, dir, "nested/expired", "TEST PAYLOAD", time.Now().Add(-time.Hour*24*4))
+	writeLocalObject(t, dir, tempFilePrefix+"123", "TEST PAYLOAD", time.Now().Add(-time.Hour*24*4))
+	writeLocalObject(t, dir, "fresh", "TEST PAYLOAD", time.Now())
+
+	client := testLocalClient(dir)
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+
+	for _, name := range []string{"expired", "nested/expired", tempFilePrefix + "123"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed. err=%v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fresh")); err != nil {
+		t.Errorf("unexpected error statting unexpired object: %s", err)
+	}
+}
+
+func TestLocalGet(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-key", "TEST PAYLOAD", time.Now())
+
+	client := testLocalClient(dir)
+	rc, err := client.Get(context.Background(), "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error getting key: %s", err)
+	}
+	defer rc.Close()
+
+	contents, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error reading object: %s", err)
+	}
+	if string(contents) != "TEST PAYLOAD" {
+		t.Fatalf("unexpected contents. want=%s have=%s", "TEST PAYLOAD", contents)
+	}
+}
+
+func TestLocalGetExpired(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-key", "TEST PAYLOAD", time.Now().Add(-time.Hour*24*4))
+
+	client := testLocalClient(dir)
+	if _, err := client.Get(context.Background(), "test-key"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not exist error getting expired key. have=%v", err)
+	}
+}
+
+func TestLocalUpload(t *testing.T) {
+	dir := t.TempDir()
+
+	client := testLocalClient(dir)
+	size, err := client.Upload(context.Background(), "nested/test-key", bytes.NewReader([]byte("TEST PAYLOAD")))
+	if err != nil {
+		t.Fatalf("unexpected error uploading key: %s", err)
+	} else if size != 12 {
+		t.Errorf("unexpected size. want=%d have=%d", 12, size)
+	}
+
+	if contents := readLocalObject(t, dir, "nested/test-key"); contents != "TEST PAYLOAD" {
+		t.Errorf("unexpected payload. want=%s have=%s", "TEST PAYLOAD", contents)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestLocalUploadFailure(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-key", "OLD PAYLOAD", time.Now())
+
+	client := testLocalClient(dir)
+	if _, err := client.Upload(context.Background(), "test-key", io.MultiReader(
+		bytes.NewReader([]byte("PARTIAL")),
+		errReader{err: errors.New("oops")},
+	)); err == nil {
+		t.Fatalf("expected error uploading key")
+	}
+
+	// A failed write must not replace the previous object
+	if contents := readLocalObject(t, dir, "test-key"); contents != "OLD PAYLOAD" {
+		t.Errorf("unexpected payload. want=%s have=%s", "OLD PAYLOAD", contents)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestLocalInvalidKeys(t *testing.T) {
+	client := testLocalClient(t.TempDir())
+
+	for _, key := range []string{"", ".", "..", "../escape", "a/../../escape", "/absolute/../../escape", tempFilePrefix + "123"} {
+		if _, err := client.Upload(context.Background(), key, bytes.NewReader(nil)); err == nil {
+			t.Errorf("expected error uploading key %q", key)
+		}
+	}
+}
+
+func TestLocalCompose(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-src1", "TEST", time.Now())
+	writeLocalObject(t, dir, "test-src2", " PAY", time.Now())
+	writeLocalObject(t, dir, "test-src3", "LOAD", time.Now())
+
+	client := testLocalClient(dir)
+	size, err := client.Compose(context.Background(), "test-key", "test-src1", "test-src2", "test-src3")
+	if err != nil {
+		t.Fatalf("unexpected error composing keys: %s", err)
+	} else if size != 12 {
+		t.Errorf("unexpected size. want=%d have=%d", 12, size)
+	}
+
+	if contents := readLocalObject(t, dir, "test-key"); contents != "TEST PAYLOAD" {
+		t.Errorf("unexpected payload. want=%s have=%s", "TEST PAYLOAD", contents)
+	}
+	for _, source := range []string{"test-src1", "test-src2", "test-src3"} {
+		if _, err := os.Stat(filepath.Join(dir, source)); !os.IsNotExist(err) {
+			t.Errorf("expected source %s to be removed. err=%v", source, err)
+		}
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestLocalComposeMissingSource(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-src1", "TEST", time.Now())
+
+	client := testLocalClient(dir)
+	if _, err := client.Compose(context.Background(), "test-key", "test-src1", "test-src2"); err == nil {
+		t.Fatalf("expected error composing keys")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "test-key")); !os.IsNotExist(err) {
+		t.Errorf("expected destination not to exist. err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test-src1")); err != nil {
+		t.Errorf("expected source to be kept on failure. err=%v", err)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestLocalComposeConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-src1", "TEST", time.Now())
+	writeLocalObject(t, dir, "test-src2", " PAYLOAD", time.Now())
+
+	client := testLocalClient(dir)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Compose(context.Background(), "test-key", "test-src1", "test-src2"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Exactly one call composes the sources, all others fail as the sources are gone
+	if n := len(errs); n != 9 {
+		t.Errorf("unexpected number of failed compose calls. want=%d have=%d", 9, n)
+	}
+	if contents := readLocalObject(t, dir, "test-key"); contents != "TEST PAYLOAD" {
+		t.Errorf("unexpected payload. want=%s have=%s", "TEST PAYLOAD", contents)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestLocalDelete(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalObject(t, dir, "test-key", "TEST PAYLOAD", time.Now())
+
+	client := testLocalClient(dir)
+	if err := client.Delete(context.Background(), "test-key"); err != nil {
+		t.Fatalf("unexpected error deleting key: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test-key")); !os.IsNotExist(err) {
+		t.Errorf("expected object to be removed. err=%v", err)
+	}
+
+	// Deleting a missing object is not an error
+	if err := client.Delete(context.Background(), "test-key"); err != nil {
+		t.Fatalf("unexpected error deleting missing key: %s", err)
+	}
+}
+
+func testLocalClient(dir string) Store {
+	return newLazyStore(newLocalWithDir(dir, time.Hour*24*3, NewOperations(&observation.TestContext, "test", "brittlestore")))
+}
+
+func writeLocalObject(t *testing.T, dir, key, contents string, modTime time.Time) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error creating directory: %s", err)
+	}
+	if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error writing object: %s", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("unexpected error setting modification time: %s", err)
+	}
+}
+
+func readLocalObject(t *testing.T, dir, key string) string {
+	t.Helper()
+
+	contents, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(key)))
+	if err != nil {
+		t.Fatalf("unexpected error reading object: %s", err)
+	}
+	return string(contents)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*", tempFilePrefix+"*"))
+	if err != nil {
+		t.Fatalf("unexpected error listing files: %s", err)
+	}
+	rootMatches, err := filepath.Glob(filepath.Join(dir, tempFilePrefix+"*"))
+	if err != nil {
+		t.Fatalf("unexpected error listing files: %s", err)
+	}
+	if files := append(matches, rootMatches...); len(files) != 0 {
+		t.Errorf("unexpected temporary files: %v", files)
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read(p []byte) (int, error) { return 0, r.err }
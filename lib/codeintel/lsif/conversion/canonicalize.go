@@ -48,6 +48,7 @@ func canonicalizeDocuments(state *State) {
 	canonicalizeDocumentsInDefinitionReferences(state.DefinitionData, canonicalIDs)
 	canonicalizeDocumentsInDefinitionReferences(state.ReferenceData, canonicalIDs)
 	canonicalizeDocumentsInDefinitionReferences(state.ImplementationData, canonicalIDs)
+	canonicalizeDocumentsInDefinitionReferences(state.TypeDefinitionData, canonicalIDs)
 
 	for documentID, canonicalID := range canonicalIDs {
 		// Move ranges and diagnostics into the canonical document
@@ -61,9 +62,9 @@ func canonicalizeDocuments(state *State) {
 	}
 }
 
-// canonicalizeDocumentsInDefinitionReferences moves definition, reference, and implementation result
-// data from a document to its canonical document (if they differ) and removes all references to the
-// non-canonical document.
+// canonicalizeDocumentsInDefinitionReferences moves definition, reference, implementation, and type
+// definition result data from a document to its canonical document (if they differ) and removes all
+// references to the non-canonical document.
 func canonicalizeDocumentsInDefinitionReferences(definitionReferenceData map[int]*datastructures.DefaultIDSetMap, canonicalIDs map[int]int) {
 	for _, documentRanges := range definitionReferenceData {
 		// The length of documentRanges will always be less than or equal to
@@ -169,9 +170,9 @@ func canonicalizeResultSetData(state *State, id int, item ResultSet) ResultSet {
 	return item
 }
 
-// mergeNextResultSetData merges the definition, reference, and hover result identifiers from
-// nextItem into item when not already defined. The moniker identifiers of nextItem are unioned
-// into the moniker identifiers of item.
+// mergeNextResultSetData merges the definition, reference, implementation, type definition, and
+// hover result identifiers from nextItem into item when not already defined. The moniker identifiers
+// of nextItem are unioned into the moniker identifiers of item.
 func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int, nextItem ResultSet) ResultSet {
 	if item.DefinitionResultID == 0 {
 		item = item.SetDefinitionResultID(nextItem.DefinitionResultID)
@@ -182,6 +183,9 @@ func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int
 	if item.ImplementationResultID == 0 {
 		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
 	}
+	if item.TypeDefinitionResultID == 0 {
+		item = item.SetTypeDefinitionResultID(nextItem.TypeDefinitionResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
@@ -190,9 +194,9 @@ func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int
 	return item
 }
 
-// mergeNextRangeData merges the definition, reference, and hover result identifiers from nextItem
-// into item when not already defined. The moniker identifiers of nextItem are unioned into the
-// moniker identifiers of item.
+// mergeNextRangeData merges the definition, reference, implementation, type definition, and hover
+// result identifiers from nextItem into item when not already defined. The moniker identifiers of
+// nextItem are unioned into the moniker identifiers of item.
 func mergeNextRangeData(state *State, itemID int, item Range, nextID int, nextItem ResultSet) Range {
 	if item.DefinitionResultID == 0 {
 		item = item.SetDefinitionResultID(nextItem.DefinitionResultID)
@@ -203,6 +207,9 @@ func mergeNextRangeData(state *State, itemID int, item Range, nextID int, nextIt
 	if item.ImplementationResultID == 0 {
 		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
 	}
+	if item.TypeDefinitionResultID == 0 {
+		item = item.SetTypeDefinitionResultID(nextItem.TypeDefinitionResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
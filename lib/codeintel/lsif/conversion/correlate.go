@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/protocol/reader"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/pathexistence"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -25,6 +26,26 @@ func Correlate(ctx context.Context, r io.Reader, root string, getChildren pathex
 		return nil, err
 	}
 
+	return processState(ctx, state, root, getChildren)
+}
+
+// CorrelateElements returns a correlation state object built from the given LSIF elements.
+// This is used for indexes that have already been decoded into LSIF from another format
+// (e.g., SCIP) rather than read from a stream of newline-delimited JSON.
+//
+// If getChildren == nil, no pruning of irrelevant data is performed.
+func CorrelateElements(ctx context.Context, elements []reader.Element, root string, getChildren pathexistence.GetChildrenFunc) (*precise.GroupedBundleDataChans, error) {
+	state, err := correlateFromElements(ctx, elements, root)
+	if err != nil {
+		return nil, err
+	}
+
+	return processState(ctx, state, root, getChildren)
+}
+
+// processState canonicalizes and prunes the given correlation state and converts it into
+// the format we send to the writer.
+func processState(ctx context.Context, state *State, root string, getChildren pathexistence.GetChildrenFunc) (*precise.GroupedBundleDataChans, error) {
 	// Remove duplicate elements, collapse linked elements
 	canonicalize(state)
 
@@ -116,7 +137,19 @@ func gitRoot(path string) (string, error) {
 // The data in the correlation state is neither canonicalized nor pruned.
 func correlateFromReader(ctx context.Context, r io.Reader, root string) (*State, error) {
 	ctx, cancel := context.WithCancel(ctx)
-	ch := Read(ctx, r)
+	return correlateFromPairs(Read(ctx, r), root, cancel)
+}
+
+// correlateFromElements reads the given elements and returns a correlation state object.
+// The data in the correlation state is neither canonicalized nor pruned.
+func correlateFromElements(ctx context.Context, elements []reader.Element, root string) (*State, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return correlateFromPairs(ReadElements(ctx, elements), root, cancel)
+}
+
+// correlateFromPairs consumes the given channel of elements and returns a correlation state
+// object. The given cancel function is invoked on exit to stop the producer of the channel.
+func correlateFromPairs(ch <-chan Pair, root string, cancel context.CancelFunc) (*State, error) {
 	defer func() {
 		// stop producer from reading more input on correlation error
 		cancel()
@@ -186,6 +219,7 @@ var vertexHandlers = map[string]vertexHandler{
 	"definitionResult":     correlateDefinitionResult,
 	"referenceResult":      correlateReferenceResult,
 	"implementationResult": correlateImplementationResult,
+	"typeDefinitionResult": correlateTypeDefinitionResult,
 	"hoverResult":          correlateHoverResult,
 	"moniker":              correlateMoniker,
 	"packageInformation":   correlatePackageInformation,
@@ -215,6 +249,7 @@ var edgeHandlers = map[string]func(state *wrappedState, id int, edge Edge) error
 	"textDocument/definition":     correlateTextDocumentDefinitionEdge,
 	"textDocument/references":     correlateTextDocumentReferencesEdge,
 	"textDocument/implementation": correlateTextDocumentImplementationEdge,
+	"textDocument/typeDefinition": correlateTextDocumentTypeDefinitionEdge,
 	"textDocument/hover":          correlateTextDocumentHoverEdge,
 	"moniker":                     correlateMonikerEdge,
 	"nextMoniker":                 correlateNextMonikerEdge,
@@ -314,6 +349,11 @@ func correlateImplementationResult(state *wrappedState, element Element) error {
 	return nil
 }
 
+func correlateTypeDefinitionResult(state *wrappedState, element Element) error {
+	state.TypeDefinitionData[element.ID] = datastructures.NewDefaultIDSetMap()
+	return nil
+}
+
 func correlateHoverResult(state *wrappedState, element Element) error {
 	payload, ok := element.Payload.(string)
 	if !ok {
@@ -444,6 +484,19 @@ func correlateItemEdge(state *wrappedState, id int, edge Edge) error {
 		return nil
 	}
 
+	if documentMap, ok := state.TypeDefinitionData[edge.OutV]; ok {
+		for _, inV := range edge.InVs {
+			if _, ok := state.RangeData[inV]; !ok {
+				return malformedDump(id, inV, "range")
+			}
+
+			// Link type definition data to the range defining the type
+			documentMap.AddID(edge.Document, inV)
+		}
+
+		return nil
+	}
+
 	if !state.unsupportedVertices.Contains(edge.OutV) {
 		return malformedDump(id, edge.OutV, "vertex")
 	}
@@ -496,6 +549,21 @@ func correlateTextDocumentImplementationEdge(state *wrappedState, id int, edge E
 	return nil
 }
 
+func correlateTextDocumentTypeDefinitionEdge(state *wrappedState, id int, edge Edge) error {
+	if _, ok := state.TypeDefinitionData[edge.InV]; !ok {
+		return malformedDump(id, edge.InV, "typeDefinitionResult")
+	}
+
+	if source, ok := state.RangeData[edge.OutV]; ok {
+		state.RangeData[edge.OutV] = source.SetTypeDefinitionResultID(edge.InV)
+	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
+		state.ResultSetData[edge.OutV] = source.SetTypeDefinitionResultID(edge.InV)
+	} else {
+		return malformedDump(id, edge.OutV, "range", "resultSet")
+	}
+	return nil
+}
+
 func correlateTextDocumentHoverEdge(state *wrappedState, id int, edge Edge) error {
 	if _, ok := state.HoverData[edge.InV]; !ok {
 		return malformedDump(id, edge.InV, "hoverResult")
@@ -58,8 +58,9 @@ func TestCorrelate(t *testing.T) {
 						End:   protocol.Pos{Line: 5, Character: 6},
 					},
 				},
-				DefinitionResultID: 13,
-				HoverResultID:      17,
+				DefinitionResultID:     13,
+				TypeDefinitionResultID: 103,
+				HoverResultID:          17,
 			},
 			7: {
 				Range: reader.Range{
@@ -109,6 +110,9 @@ func TestCorrelate(t *testing.T) {
 		ImplementationData: map[int]*datastructures.DefaultIDSetMap{
 			100: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{2: datastructures.IDSetWith(5)}),
 		},
+		TypeDefinitionData: map[int]*datastructures.DefaultIDSetMap{
+			103: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{3: datastructures.IDSetWith(8)}),
+		},
 		HoverData: map[int]string{
 			16: "```go\ntext A\n```",
 			17: "```go\ntext B\n```",
@@ -216,6 +220,7 @@ func TestCorrelateMetaDataRoot(t *testing.T) {
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
 		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -258,6 +263,7 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
 		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -278,6 +284,36 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 	}
 }
 
+func TestCorrelateElements(t *testing.T) {
+	input, err := os.ReadFile("../testdata/dump1.lsif")
+	if err != nil {
+		t.Fatalf("unexpected error reading test file: %s", err)
+	}
+
+	expectedState, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root")
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	var elements []reader.Element
+	for pair := range reader.Read(context.Background(), bytes.NewReader(input)) {
+		if pair.Err != nil {
+			t.Fatalf("unexpected error reading input: %s", pair.Err)
+		}
+
+		elements = append(elements, pair.Element)
+	}
+
+	state, err := correlateFromElements(context.Background(), elements, "root")
+	if err != nil {
+		t.Fatalf("unexpected error correlating elements: %s", err)
+	}
+
+	if diff := cmp.Diff(expectedState, state, datastructures.Comparers...); diff != "" {
+		t.Errorf("unexpected state (-want +got):\n%s", diff)
+	}
+}
+
 func TestCorrelateConflictingDocumentProperties(t *testing.T) {
 	dump, err := os.ReadFile("../testdata/dump1.lsif")
 	if err != nil {
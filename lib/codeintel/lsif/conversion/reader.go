@@ -21,20 +21,41 @@ func Read(ctx context.Context, r io.Reader) <-chan Pair {
 		defer close(elements)
 
 		for pair := range reader.Read(ctx, r) {
-			element := Element{
-				ID:      pair.Element.ID,
-				Type:    pair.Element.Type,
-				Label:   pair.Element.Label,
-				Payload: translatePayload(pair.Element.Payload),
-			}
-
-			elements <- Pair{Element: element, Err: pair.Err}
+			elements <- Pair{Element: translateElement(pair.Element), Err: pair.Err}
 		}
 	}()
 
 	return elements
 }
 
+// ReadElements returns a channel of Pair values for each of the given already-decoded elements.
+func ReadElements(ctx context.Context, elements []reader.Element) <-chan Pair {
+	pairs := make(chan Pair)
+
+	go func() {
+		defer close(pairs)
+
+		for _, element := range elements {
+			select {
+			case pairs <- Pair{Element: translateElement(element)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return pairs
+}
+
+func translateElement(element reader.Element) Element {
+	return Element{
+		ID:      element.ID,
+		Type:    element.Type,
+		Label:   element.Label,
+		Payload: translatePayload(element.Payload),
+	}
+}
+
 func translatePayload(payload any) any {
 	switch v := payload.(type) {
 	case reader.Edge:
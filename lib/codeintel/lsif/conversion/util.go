@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/protocol"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -18,3 +19,18 @@ func toID(id int) precise.ID {
 
 	return precise.ID(strconv.FormatInt(int64(id), 10))
 }
+
+// toFullRange returns the extent of the symbol defined at a range with the given tag, or nil if
+// the tag does not describe a definition with a full range.
+func toFullRange(tag *protocol.RangeTag) *precise.FullRangeData {
+	if tag == nil || tag.Type != "definition" || tag.FullRange == nil {
+		return nil
+	}
+
+	return &precise.FullRangeData{
+		StartLine:      tag.FullRange.Start.Line,
+		StartCharacter: tag.FullRange.Start.Character,
+		EndLine:        tag.FullRange.End.Line,
+		EndCharacter:   tag.FullRange.End.Character,
+	}
+}
@@ -27,15 +27,26 @@ type DocumentData struct {
 // that was reachable via a result set has been collapsed into this object during
 // conversion.
 type RangeData struct {
-	StartLine              int  // 0-indexed, inclusive
-	StartCharacter         int  // 0-indexed, inclusive
-	EndLine                int  // 0-indexed, inclusive
-	EndCharacter           int  // 0-indexed, inclusive
-	DefinitionResultID     ID   // possibly empty
-	ReferenceResultID      ID   // possibly empty
-	ImplementationResultID ID   // possibly empty
-	HoverResultID          ID   // possibly empty
-	MonikerIDs             []ID // possibly empty
+	StartLine              int            // 0-indexed, inclusive
+	StartCharacter         int            // 0-indexed, inclusive
+	EndLine                int            // 0-indexed, inclusive
+	EndCharacter           int            // 0-indexed, inclusive
+	DefinitionResultID     ID             // possibly empty
+	ReferenceResultID      ID             // possibly empty
+	ImplementationResultID ID             // possibly empty
+	TypeDefinitionResultID ID             // possibly empty
+	HoverResultID          ID             // possibly empty
+	MonikerIDs             []ID           // possibly empty
+	FullRange              *FullRangeData // possibly nil
+}
+
+// FullRangeData is the extent of the symbol defined at a range, such as the
+// whole body of a function, as given by the fullRange of its range tag.
+type FullRangeData struct {
+	StartLine      int // 0-indexed, inclusive
+	StartCharacter int // 0-indexed, inclusive
+	EndLine        int // 0-indexed, inclusive
+	EndCharacter   int // 0-indexed, inclusive
 }
 
 const (
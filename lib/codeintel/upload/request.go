@@ -60,7 +60,12 @@ func makeUploadRequest(opts uploadRequestOptions) (*http.Request, error) {
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/x-ndjson+lsif")
+	contentType := opts.UploadRecordOptions.ContentType
+	if contentType == "" {
+		contentType = LSIFContentType
+	}
+
+	req.Header.Set("Content-Type", contentType)
 	if opts.UncompressedSize != 0 {
 		req.Header.Set("X-Uncompressed-Size", strconv.Itoa(int(opts.UncompressedSize)))
 	}
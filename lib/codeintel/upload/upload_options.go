@@ -43,4 +43,13 @@ type UploadRecordOptions struct {
 	Indexer           string
 	IndexerVersion    string
 	AssociatedIndexID *int
+	ContentType       string // The format of the index file (defaults to LSIFContentType)
 }
+
+const (
+	// LSIFContentType is the content type of a newline-delimited JSON LSIF index.
+	LSIFContentType = "application/x-ndjson+lsif"
+
+	// SCIPContentType is the content type of a protobuf-encoded SCIP index.
+	SCIPContentType = "application/x-protobuf+scip"
+)
@@ -7,6 +7,11 @@ import _ "embed"
 //go:embed aws_codecommit.schema.json
 var AWSCodeCommitSchemaJSON string
 
+// AzureDevOpsSchemaJSON is the content of the file "azure_devops.schema.json".
+//
+//go:embed azure_devops.schema.json
+var AzureDevOpsSchemaJSON string
+
 // BatchSpecSchemaJSON is the content of the file "batch_spec.schema.json".
 //
 //go:embed batch_spec.schema.json
@@ -57,6 +62,11 @@ var GoModulesSchemaJSON string
 //go:embed jvm-packages.schema.json
 var JVMPackagesSchemaJSON string
 
+// MercurialSchemaJSON is the content of the file "mercurial.schema.json".
+//
+//go:embed mercurial.schema.json
+var MercurialSchemaJSON string
+
 // NpmPackagesSchemaJSON is the content of the file "npm-packages.schema.json".
 //
 //go:embed npm-packages.schema.json
@@ -101,3 +111,8 @@ var SettingsSchemaJSON string
 //
 //go:embed site.schema.json
 var SiteSchemaJSON string
+
+// SubversionSchemaJSON is the content of the file "subversion.schema.json".
+//
+//go:embed subversion.schema.json
+var SubversionSchemaJSON string